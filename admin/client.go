@@ -0,0 +1,216 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/spf13/pflag"
+
+	"github.com/chaosblade-io/chaos-agent/diagnose"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/transport"
+	"github.com/chaosblade-io/chaos-agent/web/handler"
+)
+
+const (
+	TableOutput = "table"
+	JsonOutput  = "json"
+)
+
+// commands are the local admin commands, value is the http method
+var commands = map[string]string{
+	StatusCommand:      http.MethodGet,
+	ExperimentsCommand: http.MethodGet,
+	DestroyAllCommand:  http.MethodPost,
+	ConfigCommand:      http.MethodGet,
+	DiagnoseCommand:    http.MethodGet,
+}
+
+// IsCommand returns true if the first argument of the agent is a local admin command
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// RunCommand calls the running agent through the admin socket and prints the result, returns the exit code
+func RunCommand(command string, args []string) int {
+	flags := pflag.NewFlagSet(command, pflag.ExitOnError)
+	socket := flags.String("admin.socket", options.DefaultAdminSocket, "the unix socket of the local admin server")
+	output := flags.StringP("output", "o", TableOutput, "output format: table|json")
+	timeout := flags.Duration("timeout", 5*time.Minute, "the timeout of the command")
+	if err := flags.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	response, err := call(*socket, commands[command], command, *timeout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "call chaos agent by %s failed, is the agent running? err: %v\n", *socket, err)
+		return 1
+	}
+	if !response.Success {
+		fmt.Fprintf(os.Stderr, "%s failed, code: %d, err: %s\n", command, response.Code, response.Error)
+		return 1
+	}
+	if *output == JsonOutput {
+		bytes, _ := json.MarshalIndent(response.Result, "", "  ")
+		fmt.Println(string(bytes))
+		return 0
+	}
+	if err := printTable(command, response.Result); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+	return 0
+}
+
+func call(socket, method, command string, timeout time.Duration) (*transport.Response, error) {
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+	request, err := http.NewRequest(method, "http://admin/"+command, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var response transport.Response
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed, err: %v, body: %s", err, string(body))
+	}
+	return &response, nil
+}
+
+func printTable(command string, result interface{}) error {
+	table := uitable.New()
+	table.MaxColWidth = 100
+	table.Wrap = true
+	switch command {
+	case StatusCommand:
+		var status Status
+		if err := decode(result, &status); err != nil {
+			return err
+		}
+		table.AddRow("REGISTERED:", status.Registered)
+		table.AddRow("CID:", status.Cid)
+		table.AddRow("UID:", status.Uid)
+		table.AddRow("IP:", status.Ip)
+		table.AddRow("PID:", status.Pid)
+		table.AddRow("ENDPOINT:", status.Endpoint)
+		table.AddRow("AGENT MODE:", status.AgentMode)
+		table.AddRow("STARTUP MODE:", status.StartupMode)
+		table.AddRow("AGENT VERSION:", status.AgentVersion)
+		table.AddRow("CHAOSBLADE VERSION:", status.ChaosbladeVersion)
+		table.AddRow("LITMUS VERSION:", status.LitmusVersion)
+		table.AddRow("START TIME:", status.StartTime.Format(time.RFC3339))
+		table.AddRow("EXPERIMENTS:", status.Experiments)
+		heartbeats := ""
+		for _, snapshot := range status.Heartbeat {
+			if snapshot.Success {
+				heartbeats += "+"
+			} else {
+				heartbeats += "-"
+			}
+		}
+		table.AddRow("HEARTBEAT:", heartbeats)
+		names := make([]string, 0, len(status.Concurrency))
+		for name := range status.Concurrency {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			stat := status.Concurrency[name]
+			table.AddRow(fmt.Sprintf("CONCURRENCY %s:", name),
+				fmt.Sprintf("inflight %d/%d, waiting %d, rejected %d, timeout %d",
+					stat.Inflight, stat.Concurrency, stat.Waiting, stat.Rejected, stat.Timeout))
+		}
+	case ExperimentsCommand:
+		var experiments []handler.Experiment
+		if err := decode(result, &experiments); err != nil {
+			return err
+		}
+		table.AddRow("UID", "COMMAND")
+		for _, experiment := range experiments {
+			table.AddRow(experiment.Uid, experiment.Command)
+		}
+	case DestroyAllCommand:
+		var results []handler.DestroyResult
+		if err := decode(result, &results); err != nil {
+			return err
+		}
+		table.AddRow("UID", "SUCCESS", "ERROR")
+		for _, result := range results {
+			table.AddRow(result.Uid, result.Response.Success, result.Response.Error)
+		}
+	case ConfigCommand:
+		var config map[string]string
+		if err := decode(result, &config); err != nil {
+			return err
+		}
+		names := make([]string, 0, len(config))
+		for name := range config {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		table.AddRow("FLAG", "VALUE")
+		for _, name := range names {
+			table.AddRow(name, config[name])
+		}
+	case DiagnoseCommand:
+		var checks []diagnose.Check
+		if err := decode(result, &checks); err != nil {
+			return err
+		}
+		table.AddRow("CHECK", "OK", "MESSAGE")
+		for _, check := range checks {
+			table.AddRow(check.Name, check.Ok, check.Message)
+		}
+	}
+	fmt.Println(table.String())
+	return nil
+}
+
+// decode converts the generic result to the typed value
+func decode(result interface{}, value interface{}) error {
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, value)
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/conn/heartbeat"
+	"github.com/chaosblade-io/chaos-agent/diagnose"
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/limiter"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+	"github.com/chaosblade-io/chaos-agent/web/handler"
+)
+
+// local admin commands
+const (
+	StatusCommand      = "status"
+	ExperimentsCommand = "experiments"
+	DestroyAllCommand  = "destroy-all"
+	ConfigCommand      = "config"
+	DiagnoseCommand    = "diagnose"
+)
+
+// unixSourceIp is recorded as the source of admin operations in the audit trail
+const unixSourceIp = "unix"
+
+type Status struct {
+	Registered        bool                    `json:"registered"`
+	Cid               string                  `json:"cid"`
+	Uid               string                  `json:"uid"`
+	Ip                string                  `json:"ip"`
+	Pid               string                  `json:"pid"`
+	Endpoint          string                  `json:"endpoint"`
+	AgentMode         string                  `json:"agentMode"`
+	StartupMode       string                  `json:"startupMode"`
+	AgentVersion      string                  `json:"agentVersion"`
+	ChaosbladeVersion string                  `json:"chaosbladeVersion"`
+	LitmusVersion     string                  `json:"litmusVersion"`
+	StartTime         time.Time               `json:"startTime"`
+	Experiments       int                     `json:"experiments"`
+	Heartbeat         []heartbeat.HBSnapshot  `json:"heartbeat"`
+	Concurrency       map[string]limiter.Stat `json:"concurrency"`
+}
+
+// Server serves the local admin commands on a unix socket, which is only accessible by root or the agent user
+type Server struct {
+	socket     string
+	chaosblade *handler.ChaosbladeHandler
+	startTime  time.Time
+	listener   net.Listener
+}
+
+func NewServer(socket string, chaosblade *handler.ChaosbladeHandler) *Server {
+	return &Server{
+		socket:     socket,
+		chaosblade: chaosblade,
+		startTime:  time.Now(),
+	}
+}
+
+func (s *Server) Start() error {
+	// remove the socket left by the last process, other files are never removed
+	if info, err := os.Lstat(s.socket); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%s exists and is not a socket", s.socket)
+		}
+		if err := os.Remove(s.socket); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	listener, err := listen(s.socket)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(s.socket, 0o600); err != nil {
+		listener.Close()
+		return err
+	}
+	s.listener = listener
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+StatusCommand, s.status)
+	mux.HandleFunc("/"+ExperimentsCommand, s.experiments)
+	mux.HandleFunc("/"+DestroyAllCommand, s.destroyAll)
+	mux.HandleFunc("/"+ConfigCommand, s.config)
+	mux.HandleFunc("/"+DiagnoseCommand, s.diagnose)
+	go func() {
+		defer tools.PanicPrintStack()
+		if err := http.Serve(listener, mux); err != nil {
+			logrus.Infof("[admin] admin server stopped, err: %v", err)
+		}
+	}()
+	logrus.Infof("[admin] admin server listen on %s", s.socket)
+	return nil
+}
+
+// Shutdown closes the listener and removes the socket
+func (s *Server) Shutdown() {
+	if s.listener == nil {
+		return
+	}
+	s.listener.Close()
+	os.Remove(s.socket)
+}
+
+func (s *Server) status(writer http.ResponseWriter, request *http.Request) {
+	health := handler.GetHealth()
+	status := &Status{
+		Registered:        options.Opts.Cid != "",
+		Cid:               options.Opts.Cid,
+		Uid:               options.Opts.Uid,
+		Ip:                options.Opts.Ip,
+		Pid:               options.Opts.Pid,
+		Endpoint:          options.Opts.TransportConfig.Endpoint,
+		AgentMode:         options.Opts.AgentMode,
+		StartupMode:       options.Opts.StartupMode,
+		AgentVersion:      options.Opts.Version,
+		ChaosbladeVersion: options.Opts.ChaosbladeVersion,
+		LitmusVersion:     options.Opts.LitmusChaosVerison,
+		StartTime:         s.startTime,
+		Experiments:       len(s.chaosblade.Experiments()),
+		Heartbeat:         health.Heartbeat,
+		Concurrency:       health.Concurrency,
+	}
+	writeResponse(writer, transport.ReturnSuccessWithResult(status))
+}
+
+func (s *Server) experiments(writer http.ResponseWriter, request *http.Request) {
+	writeResponse(writer, transport.ReturnSuccessWithResult(s.chaosblade.Experiments()))
+}
+
+func (s *Server) destroyAll(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writeResponse(writer, transport.ReturnFail(transport.ServiceNotSupport, request.Method))
+		return
+	}
+	logrus.Warningf("[admin] destroy all experiments by local admin")
+	results := s.chaosblade.DestroyAll()
+	for _, result := range results {
+		audit.Record(&audit.Entry{
+			SourceIp: unixSourceIp,
+			Handler:  "admin/" + DestroyAllCommand,
+			Command:  result.Command,
+			Code:     result.Response.Code,
+			Success:  result.Response.Success,
+			Error:    result.Response.Error,
+		})
+	}
+	writeResponse(writer, transport.ReturnSuccessWithResult(results))
+}
+
+func (s *Server) config(writer http.ResponseWriter, request *http.Request) {
+	writeResponse(writer, transport.ReturnSuccessWithResult(options.Opts.SanitizedConfig()))
+}
+
+func (s *Server) diagnose(writer http.ResponseWriter, request *http.Request) {
+	writeResponse(writer, transport.ReturnSuccessWithResult(diagnose.Checks()))
+}
+
+func writeResponse(writer http.ResponseWriter, response *transport.Response) {
+	bytes, err := json.Marshal(response)
+	if err != nil {
+		logrus.Warningf("[admin] marshal response failed, err: %v", err)
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	if _, err := writer.Write(bytes); err != nil {
+		logrus.Warningf("[admin] write response failed, err: %v", err)
+	}
+}
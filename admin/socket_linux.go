@@ -0,0 +1,76 @@
+//go:build linux
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package admin
+
+import (
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+// listen creates the unix socket, the connections of other users are refused by the peer credential
+// until the socket is chmodded by Start. The umask is not changed, it's shared by the whole process.
+func listen(socket string) (net.Listener, error) {
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, err
+	}
+	return &peerListener{Listener: listener}, nil
+}
+
+// peerListener only accepts the connections of root or the user running the agent, checked by SO_PEERCRED
+type peerListener struct {
+	net.Listener
+}
+
+func (l *peerListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if uid, ok := peerUid(conn); ok && (uid == 0 || uid == uint32(os.Geteuid())) {
+			return conn, nil
+		}
+		logrus.Warningf("[admin] connection from other user is refused, remote: %v", conn.RemoteAddr())
+		conn.Close()
+	}
+}
+
+// peerUid returns the uid of the process on the other side of the unix connection
+func peerUid(conn net.Conn) (uint32, bool) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return 0, false
+	}
+	raw, err := unixConn.SyscallConn()
+	if err != nil {
+		return 0, false
+	}
+	var cred *syscall.Ucred
+	var credErr error
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	}); err != nil || credErr != nil {
+		return 0, false
+	}
+	return cred.Uid, true
+}
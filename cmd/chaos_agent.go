@@ -24,31 +24,65 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/chaosblade-io/chaos-agent/admin"
 	"github.com/chaosblade-io/chaos-agent/conn"
 	closer "github.com/chaosblade-io/chaos-agent/conn/close"
 	"github.com/chaosblade-io/chaos-agent/conn/connect"
 	"github.com/chaosblade-io/chaos-agent/conn/heartbeat"
 	"github.com/chaosblade-io/chaos-agent/conn/metric"
 	"github.com/chaosblade-io/chaos-agent/metricreport"
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
 	"github.com/chaosblade-io/chaos-agent/pkg/helm3"
 	chaoshttp "github.com/chaosblade-io/chaos-agent/pkg/http"
 	"github.com/chaosblade-io/chaos-agent/pkg/kubernetes"
 	"github.com/chaosblade-io/chaos-agent/pkg/log"
 	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
 	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/pkg/upgrade"
 	"github.com/chaosblade-io/chaos-agent/transport"
 	api2 "github.com/chaosblade-io/chaos-agent/web/api"
+	"github.com/chaosblade-io/chaos-agent/web/handler"
 	"github.com/chaosblade-io/chaos-agent/web/handler/litmuschaos"
 )
 
 var pidFile = "/var/run/chaos.pid"
 
 func main() {
+	// upgrade guardian, started by the agent which is upgrading
+	if len(os.Args) > 2 && os.Args[1] == upgrade.GuardCommand {
+		os.Exit(upgrade.Guard(os.Args[2]))
+	}
+
+	// local admin commands, talk to the running agent
+	if len(os.Args) > 1 && admin.IsCommand(os.Args[1]) {
+		os.Exit(admin.RunCommand(os.Args[1], os.Args[2:]))
+	}
+
 	options.NewOptions()
 	log.InitLog(&options.Opts.LogConfig)
 
 	options.Opts.SetOthersByFlags()
 
+	// audit
+	if err := audit.Init(&options.Opts.AuditConfig); err != nil {
+		logrus.Errorf("init audit failed, err: %s", err.Error())
+		handlerErr(err)
+	}
+
+	// safety policy
+	if err := policy.Init(options.Opts.PolicyFile); err != nil {
+		logrus.Errorf("init safety policy failed, err: %s", err.Error())
+		handlerErr(err)
+	}
+
+	// status deadlines of experiment types
+	if err := status.Init(options.Opts.StatusDeadlines); err != nil {
+		logrus.Errorf("init status tracker failed, err: %s", err.Error())
+		handlerErr(err)
+	}
+
 	// new transport newConn
 	clientInstance, err := chaoshttp.NewHttpClient(options.Opts.TransportConfig)
 	if err != nil {
@@ -81,12 +115,41 @@ func main() {
 
 	// new api
 	api := api2.NewAPI()
-	err = api.Register(transportClient, k8sInstance, h)
+	err = api.Register(transportClient, k8sInstance, h, reportMetricConfigMap)
 	if err != nil {
 		logrus.Errorf("register api failed, err: %s", err.Error())
 		handlerErr(err)
 	}
 
+	// confirm or report the upgrade which restarted this agent
+	handler.ResumeUpgrade(transportClient)
+
+	// chaosblade may be missing at startup
+	handler.InstallMissingBlade()
+
+	// local admin server
+	adminServer := admin.NewServer(options.Opts.AdminSocket, api.Chaosblade)
+	if err := adminServer.Start(); err != nil {
+		logrus.Warningf("start local admin server failed, err: %s", err.Error())
+	}
+
+	// experiment ttl watchdog
+	watchdog := handler.NewWatchdog(api.Chaosblade, options.Opts.TTLConfig.Interval)
+	if err := watchdog.Start(); err != nil {
+		logrus.Warningf("start experiment watchdog failed, err: %s", err.Error())
+	}
+
+	// experiment status reconciler
+	reconciler := handler.NewReconciler(api.Chaosblade, transportClient, options.Opts.ReconcileInterval)
+	if err := reconciler.Start(); err != nil {
+		logrus.Warningf("start experiment reconciler failed, err: %s", err.Error())
+	}
+
+	// local experiment schedules
+	if err := api.Schedule.Start(); err != nil {
+		logrus.Warningf("start experiment scheduler failed, err: %s", err.Error())
+	}
+
 	// listen server
 	go func() {
 		defer tools.PanicPrintStack()
@@ -100,7 +163,7 @@ func main() {
 	handlerSuccess()
 
 	closeClient := closer.NewClientCloseHandler(transportClient)
-	tools.Hold(closeClient)
+	tools.Hold(closeClient, adminServer, watchdog, reconciler, api.Schedule)
 }
 
 func handlerSuccess() {
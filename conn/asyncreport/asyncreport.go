@@ -46,7 +46,38 @@ func (arh *AsyncReportHandler) ReportStatus(uid, status, errorMsg, toolType stri
 		request.AddParam("ToolType", toolType)
 	}
 
-	logrus.Infof("report install status: %v", request)
+	logrus.Debugf("report install status: %v", request)
+	arh.report(request, recordMsg, uri)
+}
+
+// ReportPreparationStatus reports the status of the preparation, phase is install or uninstall
+func (arh *AsyncReportHandler) ReportPreparationStatus(uid, programType, phase, status, errorMsg string, uri transport.Uri) {
+	recordMsg := fmt.Sprintf("uid: %s, type: %s, phase: %s, status: %s", uid, programType, phase, status)
+	request := transport.NewRequest()
+	request.AddParam("uid", uid).AddParam("programType", programType).
+		AddParam("phase", phase).AddParam("status", status)
+	if errorMsg != "" {
+		request.AddParam("error", errorMsg)
+	}
+
+	logrus.Debugf("report preparation status: %v", request)
+	arh.report(request, recordMsg, uri)
+}
+
+// ReportJobStatus reports the result of an async job, uid is empty if the job has not created an experiment
+func (arh *AsyncReportHandler) ReportJobStatus(jobId, uid, status, errorMsg string, uri transport.Uri) {
+	recordMsg := fmt.Sprintf("job: %s, uid: %s, status: %s", jobId, uid, status)
+	request := transport.NewRequest()
+	request.AddParam("jobId", jobId).AddParam("uid", uid).AddParam("status", status)
+	if errorMsg != "" {
+		request.AddParam("error", errorMsg)
+	}
+
+	logrus.Debugf("report job status: %v", request)
+	arh.report(request, recordMsg, uri)
+}
+
+func (arh *AsyncReportHandler) report(request *transport.Request, recordMsg string, uri transport.Uri) {
 	response, err := arh.transportClient.Invoke(uri, request, true)
 	if err != nil {
 		logrus.Warningf("Report status err, %v, %s", err, recordMsg)
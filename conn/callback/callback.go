@@ -35,7 +35,6 @@ func NewClientCloseHandler(transportClient *transport.TransportClient) *Callback
 }
 
 func (ch *CallbackHandler) Callback(status int, oldVersion, newVersion, currVersion, message, programType string) {
-	// community is null
 	uri, ok := transport.TransportUriMap[transport.API_UPGRADE_CALLBACK]
 	if !ok {
 		return
@@ -17,14 +17,17 @@
 package connect
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime"
 	"strconv"
+	"strings"
 
 	"github.com/c9s/goprocinfo/linux"
 	"github.com/sirupsen/logrus"
 
+	"github.com/chaosblade-io/chaos-agent/pkg/host"
 	"github.com/chaosblade-io/chaos-agent/pkg/options"
 	"github.com/chaosblade-io/chaos-agent/pkg/tools"
 	"github.com/chaosblade-io/chaos-agent/transport"
@@ -66,6 +69,13 @@ func (cc *ClientConnectHandler) Start() error {
 	if chaosBladeVersion != "" {
 		request.AddParam("cbv", chaosBladeVersion)
 	}
+	capabilities := host.CurrentCapabilities()
+	if gaps := capabilities.Gaps(); len(gaps) > 0 {
+		request.AddParam("capabilityGaps", strings.Join(gaps, ","))
+	}
+	if bytes, err := json.Marshal(capabilities); err == nil {
+		request.AddParam("capabilities", string(bytes))
+	}
 
 	// todo windows cant be work
 	if memInfo, err := linux.ReadMemInfo("/proc/meminfo"); err != nil {
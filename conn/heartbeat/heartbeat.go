@@ -17,10 +17,13 @@
 package heartbeat
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/chaosblade-io/chaos-agent/pkg/host"
+	"github.com/chaosblade-io/chaos-agent/pkg/limiter"
 	"github.com/chaosblade-io/chaos-agent/pkg/options"
 	"github.com/chaosblade-io/chaos-agent/pkg/tools"
 	"github.com/chaosblade-io/chaos-agent/transport"
@@ -29,10 +32,13 @@ import (
 type ClientHeartbeatHandler struct {
 	heartbeatConfig options.HeartbeatConfig
 	transportClient *transport.TransportClient
+	// capabilitiesChanged is true until the changed capabilities are sent, it's only accessed by the heartbeat loop
+	capabilitiesChanged bool
 }
 
 type HBSnapshot struct {
-	Success bool
+	Success bool      `json:"success"`
+	Time    time.Time `json:"time"`
 }
 
 var HBSnapshotList, _ = tools.NewLimitedSortList(26)
@@ -58,7 +64,23 @@ func (chh *ClientHeartbeatHandler) Start() error {
 			}
 			request.AddParam(options.AppInstanceKeyName, options.Opts.ApplicationInstance)
 			request.AddParam(options.AppGroupKeyName, options.Opts.ApplicationGroup)
-			chh.sendHeartbeat(uri, request)
+			if concurrency, err := json.Marshal(limiter.Stats()); err == nil {
+				request.AddParam("concurrency", string(concurrency))
+			}
+			// the capabilities are reported at registration, and again once they are changed
+			// until a heartbeat with them is sent successfully
+			if interval := options.Opts.CapabilityInterval; interval > 0 {
+				current, changed := host.RefreshCapabilities(interval)
+				chh.capabilitiesChanged = chh.capabilitiesChanged || changed
+				if chh.capabilitiesChanged {
+					if capabilities, err := json.Marshal(current); err == nil {
+						request.AddParam("capabilities", string(capabilities))
+					}
+				}
+			}
+			if chh.sendHeartbeat(uri, request) {
+				chh.capabilitiesChanged = false
+			}
 		}
 	}()
 	log().Infoln("[heartbeat] start successfully")
@@ -70,27 +92,29 @@ func (cc *ClientHeartbeatHandler) Stop(stopCh chan bool) error {
 	return nil
 }
 
-// sendHeartbeat
-func (chh *ClientHeartbeatHandler) sendHeartbeat(uri transport.Uri, request *transport.Request) {
+// sendHeartbeat returns true if the heartbeat is accepted by the server
+func (chh *ClientHeartbeatHandler) sendHeartbeat(uri transport.Uri, request *transport.Request) bool {
 	response, err := chh.transportClient.Invoke(uri, request, true)
 	if err != nil {
 		log().Errorln("[heartbeat] send failed.", err)
 		chh.record(false)
-		return
+		return false
 	}
 	if !response.Success {
 		log().Errorf("[heartbeat] send failed. %+v", response)
 		chh.record(false)
-		return
+		return false
 	}
 	log().Infoln("[heartbeat] success")
 	chh.record(true)
+	return true
 }
 
 // recode heartbeat result, for monitor heartbeat status
 func (chh *ClientHeartbeatHandler) record(success bool) {
 	HBSnapshotList.Put(HBSnapshot{
 		Success: success,
+		Time:    time.Now(),
 	})
 }
 
@@ -0,0 +1,144 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diagnose
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime/pprof"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/host"
+	"github.com/chaosblade-io/chaos-agent/pkg/limiter"
+	"github.com/chaosblade-io/chaos-agent/pkg/log"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+// DefaultLogSize is the default size of the agent log tail in the bundle
+const DefaultLogSize = 2 << 20
+
+type bundleFile struct {
+	name    string
+	content []byte
+}
+
+// Bundle is a tar.gz of diagnostics files
+type Bundle struct {
+	Name  string
+	files []bundleFile
+}
+
+// NewBundle creates a bundle which contains the common diagnostics of the agent
+func NewBundle(logSize int64) *Bundle {
+	bundle := &Bundle{
+		Name: fmt.Sprintf("chaos-agent-diagnose-%s-%s.tar.gz", options.Opts.Ip, time.Now().Format("20060102150405")),
+	}
+	if logSize <= 0 {
+		logSize = DefaultLogSize
+	}
+	bundle.AddFile(tools.AgentLog, log.Redact(readTail(tools.GetAgentLogFilePath(), logSize)))
+	bundle.AddJson("config.json", options.Opts.SanitizedConfig())
+	bundle.AddJson("version.json", map[string]string{
+		"agent":      options.Opts.Version,
+		"chaosblade": options.Opts.ChaosbladeVersion,
+		"litmus":     options.Opts.LitmusChaosVerison,
+	})
+	bundle.AddJson("heartbeat.json", HeartbeatSnapshots())
+	bundle.AddJson("concurrency.json", limiter.Stats())
+	bundle.AddJson("checks.json", Checks())
+	bundle.AddJson("environment.json", host.GetEnvironment())
+	bundle.AddJson("capabilities.json", host.CurrentCapabilities())
+
+	var goroutines bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+		goroutines.WriteString(fmt.Sprintf("dump goroutines err: %v", err))
+	}
+	bundle.AddFile("goroutines.txt", goroutines.Bytes())
+	return bundle
+}
+
+func (b *Bundle) AddFile(name string, content []byte) {
+	b.files = append(b.files, bundleFile{name: name, content: content})
+}
+
+// AddJson adds the value as an indented json file, the error is written to the file if marshal failed
+func (b *Bundle) AddJson(name string, value interface{}) {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		content = []byte(fmt.Sprintf("marshal %s err: %v", name, err))
+	}
+	b.AddFile(name, content)
+}
+
+// Bytes returns the tar.gz of the bundle
+func (b *Bundle) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	now := time.Now()
+	for _, file := range b.files {
+		header := &tar.Header{
+			Name:    file.name,
+			Mode:    0o644,
+			Size:    int64(len(file.content)),
+			ModTime: now,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := tarWriter.Write(file.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readTail reads the last size bytes of the file, the error is returned as content
+func readTail(fileName string, size int64) []byte {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return []byte(fmt.Sprintf("open %s err: %v", fileName, err))
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return []byte(fmt.Sprintf("stat %s err: %v", fileName, err))
+	}
+	if info.Size() > size {
+		if _, err := file.Seek(-size, io.SeekEnd); err != nil {
+			return []byte(fmt.Sprintf("seek %s err: %v", fileName, err))
+		}
+	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return []byte(fmt.Sprintf("read %s err: %v", fileName, err))
+	}
+	return content
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diagnose
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/conn/heartbeat"
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+// Check is one item of the diagnosis
+type Check struct {
+	Name    string `json:"name"`
+	Ok      bool   `json:"ok"`
+	Message string `json:"message"`
+}
+
+// Checks diagnoses the agent, the registration, the connection to server and the local tools
+func Checks() []Check {
+	return []Check{
+		checkRegistered(),
+		checkEndpoint(),
+		checkHeartbeat(),
+		checkBlade(),
+		checkAudit(),
+	}
+}
+
+// HeartbeatSnapshots returns the recent heartbeat results, the latest is the last
+func HeartbeatSnapshots() []heartbeat.HBSnapshot {
+	snapshots := make([]heartbeat.HBSnapshot, 0)
+	heartbeat.HBSnapshotList.Foreach(func(v interface{}) error {
+		if snapshot, ok := v.(heartbeat.HBSnapshot); ok {
+			snapshots = append(snapshots, snapshot)
+		}
+		return nil
+	}, false)
+	return snapshots
+}
+
+func checkRegistered() Check {
+	if options.Opts.Cid == "" {
+		return Check{Name: "registered", Message: "agent is not registered to server"}
+	}
+	return Check{Name: "registered", Ok: true, Message: fmt.Sprintf("cid: %s", options.Opts.Cid)}
+}
+
+func checkEndpoint() Check {
+	endpoint := options.Opts.TransportConfig.Endpoint
+	conn, err := net.DialTimeout("tcp", endpoint, options.Opts.TransportConfig.Timeout)
+	if err != nil {
+		return Check{Name: "endpoint", Message: fmt.Sprintf("connect %s failed, err: %v", endpoint, err)}
+	}
+	conn.Close()
+	return Check{Name: "endpoint", Ok: true, Message: fmt.Sprintf("connect %s success", endpoint)}
+}
+
+func checkHeartbeat() Check {
+	snapshots := HeartbeatSnapshots()
+	if len(snapshots) == 0 {
+		return Check{Name: "heartbeat", Message: "no heartbeat sent yet"}
+	}
+	last := snapshots[len(snapshots)-1]
+	if !last.Success {
+		return Check{Name: "heartbeat", Message: fmt.Sprintf("last heartbeat failed at %s", last.Time.Format(time.RFC3339))}
+	}
+	return Check{Name: "heartbeat", Ok: true, Message: fmt.Sprintf("last heartbeat success at %s", last.Time.Format(time.RFC3339))}
+}
+
+func checkBlade() Check {
+	if !tools.IsExist(options.BladeBinPath) {
+		return Check{Name: "chaosblade", Message: fmt.Sprintf("%s not found", options.BladeBinPath)}
+	}
+	return Check{Name: "chaosblade", Ok: true, Message: fmt.Sprintf("version: %s", options.Opts.ChaosbladeVersion)}
+}
+
+func checkAudit() Check {
+	result, err := audit.Query(audit.Filter{Limit: 1})
+	if err != nil {
+		return Check{Name: "audit", Message: err.Error()}
+	}
+	if !result.Verified {
+		return Check{Name: "audit", Message: fmt.Sprintf("audit chain broken at %d, %s", result.BrokenAt, result.Reason)}
+	}
+	return Check{Name: "audit", Ok: true, Message: "audit chain verified"}
+}
@@ -104,3 +104,20 @@ func (rmc *ReportMetricConfigMap) CloseEnable(metricName string) error {
 	rmc.ReportMetricConfig[metricName] = reportConfig
 	return nil
 }
+
+// MetricState is the state of a metric collector
+type MetricState struct {
+	Enable bool   `json:"enable"`
+	Period string `json:"period"`
+}
+
+// State returns the state of all registered metric collectors
+func (rmc *ReportMetricConfigMap) State() map[string]MetricState {
+	rmc.RLock()
+	defer rmc.RUnlock()
+	state := make(map[string]MetricState, len(rmc.ReportMetricConfig))
+	for metricName, config := range rmc.ReportMetricConfig {
+		state[metricName] = MetricState{Enable: config.Enable, Period: config.Period.String()}
+	}
+	return state
+}
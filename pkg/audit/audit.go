@@ -0,0 +1,367 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+const (
+	// maxLineSize is the maximum size of one audit record when reading the file back
+	maxLineSize = 1 << 20
+
+	// anchorSuffix is the suffix of the file which keeps the previous hash of the first entry in the
+	// audit file, so that the removed head of the file is detected
+	anchorSuffix = ".anchor"
+)
+
+// Entry is one audit record. Every entry carries the hash of the previous one,
+// so removing or modifying a record breaks the chain and is detected by Verify.
+type Entry struct {
+	// unix millis
+	Time       int64             `json:"time"`
+	RequestId  string            `json:"requestId,omitempty"`
+	AccessKey  string            `json:"accessKey,omitempty"`
+	SourceIp   string            `json:"sourceIp,omitempty"`
+	Handler    string            `json:"handler"`
+	Params     map[string]string `json:"params,omitempty"`
+	Command    string            `json:"command,omitempty"`
+	Code       int32             `json:"code"`
+	Success    bool              `json:"success"`
+	Error      string            `json:"error,omitempty"`
+	DurationMs int64             `json:"durationMs"`
+	PrevHash   string            `json:"prevHash"`
+	Hash       string            `json:"hash"`
+}
+
+// Filter selects audit entries, zero values match everything
+type Filter struct {
+	// unix millis
+	Since   int64
+	Until   int64
+	Handler string
+	// Limit keeps the latest n matched entries
+	Limit int
+}
+
+// QueryResult is the matched entries and the verification result of the whole audit file
+type QueryResult struct {
+	Entries  []Entry `json:"entries"`
+	Total    int     `json:"total"`
+	Verified bool    `json:"verified"`
+	// BrokenAt is the index of the first entry which breaks the chain, -1 if verified
+	BrokenAt int    `json:"brokenAt"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type auditor struct {
+	mutex    sync.Mutex
+	fileName string
+	writer   io.WriteCloser
+	lastHash string
+	// anchor is the previous hash of the first entry in the current audit file
+	anchor string
+}
+
+var instance *auditor
+
+// Init opens the audit file and recovers the tail of the hash chain
+func Init(cfg *options.AuditConfig) error {
+	fileName := cfg.FileName
+	if fileName == "" {
+		fileName = tools.GetAuditLogFilePath()
+	}
+
+	// only the first and the last entries are needed to recover the chain
+	var first *Entry
+	var lastHash string
+	err := scanEntries(fileName, func(entry Entry) {
+		if first == nil {
+			first = &entry
+		}
+		lastHash = entry.Hash
+	})
+	if err != nil {
+		return err
+	}
+	anchor, err := readAnchor(fileName, first)
+	if err != nil {
+		return err
+	}
+
+	if instance != nil {
+		instance.writer.Close()
+	}
+	instance = &auditor{
+		fileName: fileName,
+		writer: &lumberjack.Logger{
+			Filename:   fileName,
+			MaxSize:    cfg.MaxFileSize, // m
+			MaxBackups: cfg.MaxFileCount,
+			Compress:   false,
+		},
+		lastHash: lastHash,
+		anchor:   anchor,
+	}
+	logrus.Infof("[audit] audit file: %s", fileName)
+	return nil
+}
+
+// Record appends the entry to the audit file, it does nothing if audit is not initialized
+func Record(entry *Entry) {
+	if instance == nil || entry == nil {
+		return
+	}
+	if err := instance.append(entry); err != nil {
+		logrus.Warningf("[audit] record audit entry failed, handler: %s, err: %v", entry.Handler, err)
+	}
+}
+
+// Query reads the current audit file, verifies the whole chain and returns the matched entries
+func Query(filter Filter) (*QueryResult, error) {
+	if instance == nil {
+		return nil, fmt.Errorf("audit is not initialized")
+	}
+
+	result := &QueryResult{Verified: true, BrokenAt: -1, Entries: make([]Entry, 0)}
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+	c := newChain(instance.anchor)
+	err := scanEntries(instance.fileName, func(entry Entry) {
+		c.add(entry)
+		if !filter.match(entry) {
+			return
+		}
+		result.Total++
+		// only the latest entries within the limit are kept
+		if filter.Limit > 0 && len(result.Entries) == filter.Limit {
+			result.Entries = append(result.Entries[1:], entry)
+			return
+		}
+		result.Entries = append(result.Entries, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.err != nil {
+		result.Verified = false
+		result.BrokenAt = c.brokenAt
+		result.Reason = c.err.Error()
+	}
+	return result, nil
+}
+
+// Verify checks the hash chain, it returns the index of the first broken entry. anchor is the
+// previous hash of the first entry, because its predecessor may be rotated away.
+func Verify(anchor string, entries []Entry) (int, error) {
+	c := newChain(anchor)
+	for _, entry := range entries {
+		c.add(entry)
+	}
+	return c.brokenAt, c.err
+}
+
+// chain verifies the hash chain entry by entry, so that the audit file is verified without being
+// read into memory
+type chain struct {
+	anchor   string
+	lastHash string
+	count    int
+	// brokenAt is the index of the first broken entry, -1 if verified
+	brokenAt int
+	err      error
+}
+
+func newChain(anchor string) *chain {
+	return &chain{anchor: anchor, brokenAt: -1}
+}
+
+// add verifies the next entry, the entries after the first broken one are not checked
+func (c *chain) add(entry Entry) {
+	index := c.count
+	prevHash := c.lastHash
+	c.count++
+	c.lastHash = entry.Hash
+	if c.err != nil {
+		return
+	}
+	if index == 0 && entry.PrevHash != c.anchor {
+		c.broken(index, fmt.Errorf("previous hash not matched with the anchor, the head of the audit file was removed"))
+		return
+	}
+	hash, err := entry.digest()
+	if err != nil {
+		c.broken(index, err)
+		return
+	}
+	if hash != entry.Hash {
+		c.broken(index, fmt.Errorf("hash not matched, entry at %d was modified", index))
+		return
+	}
+	if index > 0 && entry.PrevHash != prevHash {
+		c.broken(index, fmt.Errorf("previous hash not matched, entry before %d was removed or modified", index))
+	}
+}
+
+func (c *chain) broken(index int, err error) {
+	c.brokenAt, c.err = index, err
+}
+
+func (a *auditor) append(entry *Entry) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if entry.Time == 0 {
+		entry.Time = time.Now().UnixMilli()
+	}
+	entry.PrevHash = a.lastHash
+	hash, err := entry.digest()
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line := append(bytes, '\n')
+	if _, err = a.writer.Write(line); err != nil {
+		return err
+	}
+	a.lastHash = hash
+	// the entry is the first one of a new or rotated audit file
+	if info, err := os.Stat(a.fileName); err == nil && info.Size() == int64(len(line)) {
+		a.anchor = entry.PrevHash
+		if err := tools.WriteFileAtomic(a.fileName+anchorSuffix, []byte(a.anchor), 0o600); err != nil {
+			logrus.Warningf("[audit] save audit anchor failed, err: %v", err)
+		}
+	}
+	return nil
+}
+
+// digest is the sha256 of the entry encoded without its own hash
+func (entry Entry) digest() (string, error) {
+	entry.Hash = ""
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (filter Filter) match(entry Entry) bool {
+	if filter.Since > 0 && entry.Time < filter.Since {
+		return false
+	}
+	if filter.Until > 0 && entry.Time > filter.Until {
+		return false
+	}
+	if filter.Handler != "" && entry.Handler != filter.Handler {
+		return false
+	}
+	return true
+}
+
+// readAnchor returns the saved anchor of the audit file. The file written before the anchor is
+// introduced has no anchor, its first entry is trusted and saved as the anchor.
+func readAnchor(fileName string, first *Entry) (string, error) {
+	content, err := os.ReadFile(fileName + anchorSuffix)
+	if err == nil {
+		return strings.TrimSpace(string(content)), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	if first == nil {
+		return "", nil
+	}
+	anchor := first.PrevHash
+	return anchor, tools.WriteFileAtomic(fileName+anchorSuffix, []byte(anchor), 0o600)
+}
+
+// scanEntries reads the audit file line by line and calls fn with each entry. The broken and
+// oversized lines are passed as the entries without hash, so that the chain reports them.
+func scanEntries(fileName string, fn func(entry Entry)) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for index := 0; ; {
+		line, oversized, err := readLine(reader)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if oversized {
+			logrus.Warningf("[audit] audit entry %d is larger than %d bytes, skipped", index, maxLineSize)
+			fn(Entry{Error: fmt.Sprintf("audit entry is larger than %d bytes", maxLineSize)})
+			index++
+		} else if len(line) > 0 {
+			var entry Entry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				entry = Entry{Error: fmt.Sprintf("unmarshal audit entry err: %v", err)}
+			}
+			fn(entry)
+			index++
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// readLine reads one line without the line break, the rest of the line longer than maxLineSize is
+// discarded and oversized is true
+func readLine(reader *bufio.Reader) (line []byte, oversized bool, err error) {
+	for {
+		fragment, err := reader.ReadSlice('\n')
+		if !oversized {
+			if len(line)+len(fragment) > maxLineSize+1 {
+				oversized, line = true, nil
+			} else {
+				line = append(line, fragment...)
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return bytes.TrimRight(line, "\r\n"), oversized, err
+	}
+}
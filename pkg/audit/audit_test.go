@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+)
+
+func TestRecordAndVerify(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "audit.log")
+	if err := Init(&options.AuditConfig{FileName: fileName, MaxFileSize: 1, MaxFileCount: 1}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	for _, handler := range []string{"chaosblade", "ping", "chaosblade"} {
+		Record(&Entry{Handler: handler, Command: "/opt/chaosblade/blade create cpu load", Code: 200, Success: true})
+	}
+
+	result, err := Query(Filter{Handler: "chaosblade"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if !result.Verified || result.Total != 2 {
+		t.Fatalf("Query() = %+v, want verified with 2 entries", result)
+	}
+	if result.Entries[1].PrevHash == "" {
+		t.Errorf("Query() entry has no previous hash")
+	}
+	result, err = Query(Filter{Limit: 1})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if !result.Verified || result.Total != 3 || len(result.Entries) != 1 || result.Entries[0].Hash != instance.lastHash {
+		t.Errorf("Query() with limit = %+v, want the latest of 3 entries", result)
+	}
+
+	// the chain continues after restart
+	lastHash := instance.lastHash
+	if err := Init(&options.AuditConfig{FileName: fileName, MaxFileSize: 1, MaxFileCount: 1}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if instance.lastHash != lastHash {
+		t.Errorf("Init() lastHash = %s, want %s", instance.lastHash, lastHash)
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "audit.log")
+	if err := Init(&options.AuditConfig{FileName: fileName, MaxFileSize: 1, MaxFileCount: 1}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	for _, command := range []string{"create cpu load", "create network loss", "destroy abc"} {
+		Record(&Entry{Handler: "chaosblade", Command: command, Code: 200, Success: true})
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(lines []string) []string
+		want   int
+	}{
+		{
+			name: "modified",
+			tamper: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], "network loss", "network delay", 1)
+				return lines
+			},
+			want: 1,
+		},
+		{
+			name: "removed",
+			tamper: func(lines []string) []string {
+				return append(lines[:1], lines[2:]...)
+			},
+			want: 1,
+		},
+		{
+			name: "head removed",
+			tamper: func(lines []string) []string {
+				return lines[1:]
+			},
+			want: 0,
+		},
+		{
+			name: "oversized",
+			tamper: func(lines []string) []string {
+				lines[2] = strings.Repeat("x", maxLineSize+1)
+				return lines
+			},
+			want: 2,
+		},
+	}
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+			tampered := filepath.Join(t.TempDir(), "audit.log")
+			if err := os.WriteFile(tampered, []byte(strings.Join(tt.tamper(lines), "\n")), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			entries := make([]Entry, 0)
+			if err := scanEntries(tampered, func(entry Entry) { entries = append(entries, entry) }); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := Verify("", entries); got != tt.want || err == nil {
+				t.Errorf("Verify() = %d, %v, want %d", got, err, tt.want)
+			}
+		})
+	}
+}
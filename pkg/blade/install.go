@@ -0,0 +1,401 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/progress"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/pkg/upgrade"
+)
+
+const (
+	// versionDirPrefix is the prefix of the version directories, eg: /opt/chaosblade-1.7.4
+	versionDirPrefix = options.BladeDirName + "-"
+	// previousLinkSuffix is the suffix of the link to the version before the active one
+	previousLinkSuffix = ".previous"
+	// legacyVersion names the directory of a legacy installation whose version is unknown
+	legacyVersion = "legacy"
+)
+
+// installMutex serializes the installations and rollbacks
+var installMutex sync.Mutex
+
+// versionPattern finds the version in the directory or file name of a package, eg: chaosblade-1.7.4
+var versionPattern = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+// Package is the chaosblade tar package to install
+type Package struct {
+	Url    string
+	Sha256 string
+	Md5    string
+	// Signature is the base64 ed25519 signature of the tar package
+	Signature string
+	// Insecure allows the package without checksum
+	Insecure bool
+}
+
+// Result is the chaosblade versions after the installation or rollback
+type Result struct {
+	Version  string `json:"version"`
+	Previous string `json:"previous,omitempty"`
+	Dir      string `json:"dir"`
+}
+
+// Installer installs chaosblade side by side. Each version is installed in its own directory
+// next to home, home is a symlink to the active version which is switched atomically, and
+// home.previous links to the version before for rollback.
+type Installer struct {
+	home string
+	// verifyKey is the base64 ed25519 public key file, the signature is not verified if empty
+	verifyKey string
+	// downloadTimeout is the deadline of downloading the package
+	downloadTimeout time.Duration
+	// safePointTimeout is how long to wait for the in-flight experiment operations before switching
+	safePointTimeout time.Duration
+}
+
+func NewInstaller(home, verifyKey string, downloadTimeout, safePointTimeout time.Duration) *Installer {
+	return &Installer{
+		home:             home,
+		verifyKey:        verifyKey,
+		downloadTimeout:  downloadTimeout,
+		safePointTimeout: safePointTimeout,
+	}
+}
+
+// Install downloads, verifies and extracts the package, then switches to it at the safe point. The
+// active version is kept unchanged if any step fails. The blade of an insecure package without
+// checksum and signature is never executed, its version is taken from the package name.
+func (i *Installer) Install(pkg Package, reporter *progress.Reporter) (*Result, error) {
+	if pkg.Url == "" {
+		return nil, errors.New("package url is empty")
+	}
+	if pkg.Sha256 == "" && pkg.Md5 == "" && !pkg.Insecure {
+		return nil, errors.New("sha256 or md5 checksum of package is required")
+	}
+
+	parent := filepath.Dir(i.home)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(parent, ".chaosblade-staging-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(staging)
+
+	tarFile := filepath.Join(staging, "chaosblade.tar.gz")
+	reporter.Phase("download %s", pkg.Url)
+	ctx, cancel := context.WithTimeout(context.Background(), i.downloadTimeout)
+	err = tools.DownloadWithContext(ctx, tarFile, pkg.Url)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("download package failed, %v", err)
+	}
+	reporter.Phase("verify package")
+	verified, err := i.verify(tarFile, pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	reporter.Phase("extract package")
+	extractDir := filepath.Join(staging, "extract")
+	if err := tools.DeCompressTgz(tarFile, extractDir); err != nil {
+		return nil, fmt.Errorf("extract package failed, %v", err)
+	}
+	root, err := findBladeRoot(extractDir)
+	if err != nil {
+		return nil, err
+	}
+	var version string
+	if verified {
+		version, err = options.GetBladeVersionOf(filepath.Join(root, options.BladeBin))
+		if err != nil {
+			return nil, fmt.Errorf("invalid package, %v", err)
+		}
+	} else {
+		version = versionFromName(filepath.Base(root), path.Base(pkg.Url))
+		if version == "" {
+			return nil, fmt.Errorf("version of the insecure package cannot be found in its name, %s", pkg.Url)
+		}
+		logrus.Warningf("[blade install] install insecure chaosblade package %s as version %s", pkg.Url, version)
+	}
+
+	installMutex.Lock()
+	defer installMutex.Unlock()
+	release, err := i.enterSafePoint(reporter)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	current, err := i.migrate()
+	if err != nil {
+		return nil, err
+	}
+	target := filepath.Join(parent, versionDirPrefix+version)
+	if current == target {
+		reporter.Phase("chaosblade %s is already installed", version)
+		return &Result{Version: version, Dir: target}, nil
+	}
+	if err := os.RemoveAll(target); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(root, target); err != nil {
+		return nil, err
+	}
+
+	reporter.Phase("switch to chaosblade %s", version)
+	if !verified {
+		return i.switchTo(current, target, version)
+	}
+	return i.switchTo(current, target, "")
+}
+
+// Rollback switches to the version before the active one at the safe point
+func (i *Installer) Rollback(reporter *progress.Reporter) (*Result, error) {
+	installMutex.Lock()
+	defer installMutex.Unlock()
+
+	previous := readLink(i.home + previousLinkSuffix)
+	if previous == "" {
+		return nil, errors.New("no previous version to roll back to")
+	}
+	release, err := i.enterSafePoint(reporter)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	current, err := i.migrate()
+	if err != nil {
+		return nil, err
+	}
+	if current == previous {
+		return nil, fmt.Errorf("%s is already active", previous)
+	}
+	reporter.Phase("roll back to chaosblade %s", versionOf(previous))
+	return i.switchTo(current, previous, "")
+}
+
+// enterSafePoint refuses the new experiment operations and waits for the in-flight ones, so that no
+// blade process writes the experiment data while it's copied and the link is switched
+func (i *Installer) enterSafePoint(reporter *progress.Reporter) (func(), error) {
+	reporter.Phase("wait for the in-flight experiment operations")
+	if err := upgrade.Begin(i.safePointTimeout); err != nil {
+		return nil, fmt.Errorf("wait for safe point failed, %v", err)
+	}
+	return upgrade.End, nil
+}
+
+// switchTo points home to target, and switches back if the blade of target cannot work. The blade of
+// target is not executed if version is given, which is the version of an insecure package.
+func (i *Installer) switchTo(current, target, version string) (*Result, error) {
+	if current != "" {
+		// the created experiments are recorded in the data file, keep them destroyable
+		if err := copyFile(filepath.Join(current, options.BladeDatFileName), filepath.Join(target, options.BladeDatFileName)); err != nil {
+			return nil, fmt.Errorf("copy experiment data failed, %v", err)
+		}
+	}
+	if err := replaceLink(target, i.home); err != nil {
+		return nil, err
+	}
+	if version == "" {
+		var err error
+		version, err = options.GetBladeVersionOf(filepath.Join(i.home, options.BladeBin))
+		if err != nil {
+			if current != "" {
+				if rerr := replaceLink(current, i.home); rerr != nil {
+					logrus.Errorf("[blade install] switch back to %s failed, err: %v", current, rerr)
+				}
+			}
+			return nil, fmt.Errorf("verify chaosblade after switch failed, %v", err)
+		}
+	}
+
+	result := &Result{Version: version, Dir: target}
+	if current != "" {
+		if err := replaceLink(current, i.home+previousLinkSuffix); err != nil {
+			logrus.Warningf("[blade install] record previous version %s failed, err: %v", current, err)
+		}
+		result.Previous = versionOf(current)
+	}
+	logrus.Infof("[blade install] switch chaosblade from %s to %s", result.Previous, version)
+	return result, nil
+}
+
+// migrate turns a legacy home directory into a version directory, returns the active version
+// directory, empty if chaosblade is not installed
+func (i *Installer) migrate() (string, error) {
+	info, err := os.Lstat(i.home)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return readLink(i.home), nil
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", i.home)
+	}
+
+	version, err := options.GetBladeVersionOf(filepath.Join(i.home, options.BladeBin))
+	if err != nil {
+		version = legacyVersion
+	}
+	dir := filepath.Join(filepath.Dir(i.home), versionDirPrefix+version)
+	if tools.IsExist(dir) {
+		dir = fmt.Sprintf("%s-%d", dir, time.Now().Unix())
+	}
+	if err := os.Rename(i.home, dir); err != nil {
+		return "", err
+	}
+	if err := os.Symlink(dir, i.home); err != nil {
+		if rerr := os.Rename(dir, i.home); rerr != nil {
+			logrus.Errorf("[blade install] restore %s failed, err: %v", i.home, rerr)
+		}
+		return "", err
+	}
+	logrus.Infof("[blade install] move legacy installation %s to %s", i.home, dir)
+	return dir, nil
+}
+
+// verify checks the checksum and the signature of the package, verified is false if the package
+// is insecure without any of them
+func (i *Installer) verify(tarFile string, pkg Package) (verified bool, err error) {
+	if pkg.Sha256 != "" {
+		if _, err := tools.CheckSha256(tarFile, pkg.Sha256); err != nil {
+			return false, fmt.Errorf("verify package failed, %v", err)
+		}
+		verified = true
+	}
+	if pkg.Md5 != "" {
+		if _, err := tools.CheckMd5(tarFile, pkg.Md5); err != nil {
+			return false, fmt.Errorf("verify package failed, %v", err)
+		}
+		verified = true
+	}
+	if i.verifyKey == "" {
+		return verified, nil
+	}
+	if pkg.Signature == "" {
+		return false, errors.New("signature of package is required")
+	}
+	if err := tools.VerifySignature(tarFile, pkg.Signature, i.verifyKey); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// versionFromName returns the version in the first name which has one
+func versionFromName(names ...string) string {
+	for _, name := range names {
+		if version := versionPattern.FindString(name); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// findBladeRoot returns the directory which contains the blade binary, the package may be
+// extracted into a top level directory
+func findBladeRoot(dir string) (string, error) {
+	if tools.IsExist(filepath.Join(dir, options.BladeBin)) {
+		return dir, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		root := filepath.Join(dir, entry.Name())
+		if entry.IsDir() && tools.IsExist(filepath.Join(root, options.BladeBin)) {
+			return root, nil
+		}
+	}
+	return "", errors.New("blade binary not found in package")
+}
+
+// replaceLink points link to target atomically
+func replaceLink(target, link string) error {
+	tmp := link + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// readLink returns the absolute target of link, empty if link or its target does not exist
+func readLink(link string) string {
+	target, err := os.Readlink(link)
+	if err != nil {
+		return ""
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(link), target)
+	}
+	if !tools.IsExist(target) {
+		return ""
+	}
+	return target
+}
+
+func versionOf(dir string) string {
+	return strings.TrimPrefix(filepath.Base(dir), versionDirPrefix)
+}
+
+// copyFile copies src to dst, nothing to do if src does not exist
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blade
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/upgrade"
+)
+
+// bladeScript prints the version like blade version
+func bladeScript(version string) string {
+	return fmt.Sprintf("#!/bin/sh\necho \"version: %s\"\n", version)
+}
+
+func buildPackage(t *testing.T, version string) []byte {
+	return buildPackageOf(t, map[string]string{
+		"chaosblade-" + version + "/blade":      bladeScript(version),
+		"chaosblade-" + version + "/bin/README": "bin",
+	})
+}
+
+func buildPackageOf(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	zw.Close()
+	return buf.Bytes()
+}
+
+func checksum(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+func activeVersion(t *testing.T, home string) string {
+	content, err := os.ReadFile(filepath.Join(home, "blade"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestInstallAndRollback(t *testing.T) {
+	packages := map[string][]byte{
+		"/1.7.4.tar.gz": buildPackage(t, "1.7.4"),
+		"/1.8.0.tar.gz": buildPackage(t, "1.8.0"),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := packages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	// legacy installation in a plain directory
+	home := filepath.Join(t.TempDir(), "chaosblade")
+	if err := os.MkdirAll(home, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "blade"), []byte(bladeScript("1.7.3")), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "chaosblade.dat"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	installer := NewInstaller(home, "", time.Minute, time.Second)
+
+	if _, err := installer.Install(Package{Url: server.URL + "/1.7.4.tar.gz", Sha256: checksum([]byte("other"))}, nil); err == nil {
+		t.Fatalf("Install() with wrong checksum, want error")
+	}
+	if _, err := installer.Install(Package{Url: server.URL + "/1.7.4.tar.gz"}, nil); err == nil {
+		t.Fatalf("Install() without checksum, want error")
+	}
+	if got := activeVersion(t, home); got != bladeScript("1.7.3") {
+		t.Fatalf("active version changed after failed installation: %s", got)
+	}
+
+	result, err := installer.Install(Package{Url: server.URL + "/1.7.4.tar.gz", Sha256: checksum(packages["/1.7.4.tar.gz"])}, nil)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if result.Version != "1.7.4" || result.Previous != "1.7.3" {
+		t.Errorf("Install() = %+v, want 1.7.4 with previous 1.7.3", result)
+	}
+	if data, err := os.ReadFile(filepath.Join(home, "chaosblade.dat")); err != nil || string(data) != "data" {
+		t.Errorf("experiment data is not kept after installation, %s, %v", data, err)
+	}
+
+	result, err = installer.Install(Package{Url: server.URL + "/1.8.0.tar.gz", Sha256: checksum(packages["/1.8.0.tar.gz"])}, nil)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if result.Version != "1.8.0" || result.Previous != "1.7.4" {
+		t.Errorf("Install() = %+v, want 1.8.0 with previous 1.7.4", result)
+	}
+
+	result, err = installer.Rollback(nil)
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if result.Version != "1.7.4" || result.Previous != "1.8.0" {
+		t.Errorf("Rollback() = %+v, want 1.7.4 with previous 1.8.0", result)
+	}
+	if got := activeVersion(t, home); got != bladeScript("1.7.4") {
+		t.Errorf("active blade after rollback = %s", got)
+	}
+}
+
+func TestInstall_Insecure(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "executed")
+	// the blade of the insecure package must never be executed
+	script := fmt.Sprintf("#!/bin/sh\ntouch %s\necho \"version: 9.9.9\"\n", marker)
+	packages := map[string][]byte{
+		"/chaosblade-1.7.4-linux-amd64.tar.gz": buildPackageOf(t, map[string]string{"chaosblade-1.7.4/blade": script}),
+		"/latest.tar.gz":                       buildPackageOf(t, map[string]string{"blade": script}),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(packages[r.URL.Path])
+	}))
+	defer server.Close()
+
+	installer := NewInstaller(filepath.Join(dir, "chaosblade"), "", time.Minute, time.Second)
+	if _, err := installer.Install(Package{Url: server.URL + "/latest.tar.gz", Insecure: true}, nil); err == nil {
+		t.Errorf("Install() insecure package without version in name, want error")
+	}
+	result, err := installer.Install(Package{Url: server.URL + "/chaosblade-1.7.4-linux-amd64.tar.gz", Insecure: true}, nil)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if result.Version != "1.7.4" {
+		t.Errorf("Install() version = %s, want 1.7.4 from the package name", result.Version)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Errorf("blade of the insecure package is executed")
+	}
+}
+
+func TestInstall_SafePoint(t *testing.T) {
+	data := buildPackage(t, "1.7.4")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	home := filepath.Join(t.TempDir(), "chaosblade")
+	installer := NewInstaller(home, "", time.Minute, 300*time.Millisecond)
+	release, ok := upgrade.Enter()
+	if !ok {
+		t.Fatal("Enter() refused")
+	}
+	_, err := installer.Install(Package{Url: server.URL + "/1.7.4.tar.gz", Sha256: checksum(data)}, nil)
+	release()
+	if err == nil {
+		t.Fatalf("Install() with an operation in flight, want error")
+	}
+	if _, err := os.Lstat(home); !os.IsNotExist(err) {
+		t.Errorf("home is switched without the safe point, err: %v", err)
+	}
+	if _, err := installer.Install(Package{Url: server.URL + "/1.7.4.tar.gz", Sha256: checksum(data)}, nil); err != nil {
+		t.Errorf("Install() after the operation finished, err: %v", err)
+	}
+}
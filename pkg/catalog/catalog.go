@@ -0,0 +1,418 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package catalog
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+const (
+	// ChecksumFile is the sha256 checksums of the object files of a version, key is the path
+	// relative to the version directory, eg: generic/pod-delete/experiment.yaml
+	ChecksumFile = "checksums.json"
+
+	// maxObjectSize is the maximum size of an object file or a bundle
+	maxObjectSize = 64 << 20
+
+	downloadTimeout = 30 * time.Second
+)
+
+// Entry is an experiment in the catalog
+type Entry struct {
+	Version string   `json:"version"`
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Objects []string `json:"objects"`
+}
+
+// Catalog keeps the litmus experiment and rbac yaml of each version in the directory:
+// <dir>/<version>/<type>/<name>/<object>.yaml. The objects are verified by the checksum file of
+// the version if it has them, and the objects fetched from the hub must have them.
+type Catalog struct {
+	mutex  sync.Mutex
+	dir    string
+	hub    string
+	client *http.Client
+}
+
+// NewCatalog returns the catalog in dir, the missing objects are fetched from hub, or never if hub is empty
+func NewCatalog(dir, hub string) *Catalog {
+	return &Catalog{
+		dir:    dir,
+		hub:    strings.TrimSuffix(hub, "/"),
+		client: &http.Client{Timeout: downloadTimeout},
+	}
+}
+
+// Dir returns the directory of the catalog
+func (c *Catalog) Dir() string {
+	return c.dir
+}
+
+// Get returns the object yaml of the experiment, eg: 1.13.5 generic pod-delete experiment
+func (c *Catalog) Get(version, experimentType, name, object string) ([]byte, error) {
+	rel := path.Join(experimentType, name, object+".yaml")
+	if err := checkNames(version, experimentType, name, object); err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	content, found, err := c.readLocked(version, rel)
+	c.mutex.Unlock()
+	if found || err != nil {
+		return content, err
+	}
+	if c.hub == "" {
+		return nil, fmt.Errorf("%s of version %s is not in the catalog %s", rel, version, c.dir)
+	}
+	c.mutex.Lock()
+	checksums, err := c.checksumsLocked(version)
+	c.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := checksums[rel]; !ok {
+		return nil, fmt.Errorf("%s of version %s is not in the catalog %s, and it can't be fetched without checksum", rel, version, c.dir)
+	}
+	// the hub is fetched without the lock, so the objects in the catalog are not blocked by the download
+	fetched, err := c.fetch(version, rel)
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if content, found, err := c.readLocked(version, rel); found || err != nil {
+		// it's fetched by another request meanwhile
+		return content, err
+	}
+	return fetched, c.saveLocked(version, map[string][]byte{rel: fetched}, nil)
+}
+
+// readLocked reads the object in the catalog and verifies it, found is false if it's not in the catalog
+func (c *Catalog) readLocked(version, rel string) (content []byte, found bool, err error) {
+	content, err = os.ReadFile(filepath.Join(c.dir, version, rel))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return content, true, c.verifyLocked(version, rel, content)
+}
+
+// Prefetch fetches the experiments of the version from the hub, eg: generic/pod-delete. The objects
+// are verified by checksums, which is keyed as the checksum file, or by the checksum file of the
+// version. The object without checksum is refused, as what's fetched from the hub isn't trusted.
+func (c *Catalog) Prefetch(version string, experiments []string, objects []string, checksums map[string]string) ([]Entry, error) {
+	if c.hub == "" {
+		return nil, fmt.Errorf("hub is not configured, import a bundle instead")
+	}
+	if err := checkNames(version); err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	known, err := c.checksumsLocked(version)
+	c.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	expected := make(map[string]string, len(known)+len(checksums))
+	for rel, checksum := range known {
+		expected[rel] = checksum
+	}
+	for rel, checksum := range checksums {
+		expected[rel] = checksum
+	}
+	files := make(map[string][]byte)
+	for _, experiment := range experiments {
+		parts := strings.Split(experiment, "/")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("experiment %q must be type/name", experiment)
+		}
+		for _, object := range objects {
+			if err := checkNames(version, parts[0], parts[1], object); err != nil {
+				return nil, err
+			}
+			rel := path.Join(parts[0], parts[1], object+".yaml")
+			if _, ok := expected[rel]; !ok {
+				return nil, fmt.Errorf("%s of version %s has no checksum", rel, version)
+			}
+			content, err := c.fetch(version, rel)
+			if err != nil {
+				return nil, err
+			}
+			files[rel] = content
+		}
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.saveLocked(version, files, expected); err != nil {
+		return nil, err
+	}
+	return c.listLocked(version)
+}
+
+// Import extracts the bundle, which is a tar.gz of the version directories with their checksum files.
+// The bundle is verified by bundleSha256 if it's not empty.
+func (c *Catalog) Import(bundle io.Reader, bundleSha256 string) ([]Entry, error) {
+	data, err := io.ReadAll(io.LimitReader(bundle, maxObjectSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxObjectSize {
+		return nil, fmt.Errorf("bundle is larger than %d bytes", maxObjectSize)
+	}
+	if bundleSha256 != "" && !strings.EqualFold(Sha256(data), bundleSha256) {
+		return nil, fmt.Errorf("bundle checksum mismatch, expected %s, actual %s", bundleSha256, Sha256(data))
+	}
+	versions, err := readBundle(data)
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for version, files := range versions {
+		var checksums map[string]string
+		if content, ok := files[ChecksumFile]; ok {
+			if err := json.Unmarshal(content, &checksums); err != nil {
+				return nil, fmt.Errorf("%s of version %s is broken, %s", ChecksumFile, version, err.Error())
+			}
+			delete(files, ChecksumFile)
+		}
+		if err := c.saveLocked(version, files, checksums); err != nil {
+			return nil, err
+		}
+	}
+	return c.listLocked("")
+}
+
+// List returns the experiments of the version, or of all versions if version is empty
+func (c *Catalog) List(version string) ([]Entry, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.listLocked(version)
+}
+
+func (c *Catalog) listLocked(version string) ([]Entry, error) {
+	pattern := filepath.Join(c.dir, "*", "*", "*", "*.yaml")
+	if version != "" {
+		pattern = filepath.Join(c.dir, version, "*", "*", "*.yaml")
+	}
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]*Entry)
+	for _, file := range files {
+		rel, err := filepath.Rel(c.dir, file)
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		key := strings.Join(parts[:3], "/")
+		entry, ok := entries[key]
+		if !ok {
+			entry = &Entry{Version: parts[0], Type: parts[1], Name: parts[2], Objects: make([]string, 0)}
+			entries[key] = entry
+		}
+		entry.Objects = append(entry.Objects, strings.TrimSuffix(parts[3], ".yaml"))
+	}
+	list := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		sort.Strings(entry.Objects)
+		list = append(list, *entry)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Version != list[j].Version {
+			return list[i].Version < list[j].Version
+		}
+		if list[i].Type != list[j].Type {
+			return list[i].Type < list[j].Type
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// fetch downloads the object from the hub,
+// eg: https://hub.litmuschaos.io/api/chaos/1.13.5?file=charts/generic/pod-delete/experiment.yaml
+func (c *Catalog) fetch(version, rel string) ([]byte, error) {
+	url := fmt.Sprintf("%s/%s?file=charts/%s", c.hub, version, rel)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download `%s` failed! response code: %d", url, resp.StatusCode)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxObjectSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download `%s` failed, %s", url, err.Error())
+	}
+	if len(content) > maxObjectSize {
+		return nil, fmt.Errorf("download `%s` failed, it's larger than %d bytes", url, maxObjectSize)
+	}
+	logrus.Infof("[litmus catalog] %s is downloaded, size: %d", url, len(content))
+	return content, nil
+}
+
+// verifyLocked checks the content by the checksum file of the version, the object without checksum is trusted
+func (c *Catalog) verifyLocked(version, rel string, content []byte) error {
+	checksums, err := c.checksumsLocked(version)
+	if err != nil {
+		return err
+	}
+	if expected, ok := checksums[rel]; ok && !strings.EqualFold(expected, Sha256(content)) {
+		return fmt.Errorf("%s of version %s checksum mismatch, expected %s, actual %s", rel, version, expected, Sha256(content))
+	}
+	return nil
+}
+
+func (c *Catalog) checksumsLocked(version string) (map[string]string, error) {
+	checksums := make(map[string]string)
+	content, err := os.ReadFile(filepath.Join(c.dir, version, ChecksumFile))
+	if os.IsNotExist(err) {
+		return checksums, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &checksums); err != nil {
+		return nil, fmt.Errorf("%s of version %s is broken, %s", ChecksumFile, version, err.Error())
+	}
+	return checksums, nil
+}
+
+// saveLocked verifies the files by the expected checksums and writes them, the checksums of the
+// written files are recorded in the checksum file of the version. The expected checksums of the
+// objects not in files are recorded too, so they can be fetched from the hub later.
+func (c *Catalog) saveLocked(version string, files map[string][]byte, expected map[string]string) error {
+	checksums, err := c.checksumsLocked(version)
+	if err != nil {
+		return err
+	}
+	for rel, content := range files {
+		actual := Sha256(content)
+		if want, ok := expected[rel]; ok && !strings.EqualFold(want, actual) {
+			return fmt.Errorf("%s of version %s checksum mismatch, expected %s, actual %s", rel, version, want, actual)
+		}
+		checksums[rel] = actual
+	}
+	for rel, want := range expected {
+		if _, ok := files[rel]; !ok {
+			checksums[rel] = strings.ToLower(want)
+		}
+	}
+	for rel, content := range files {
+		if err := tools.WriteFileAtomic(filepath.Join(c.dir, version, filepath.FromSlash(rel)), content, 0o644); err != nil {
+			return err
+		}
+	}
+	content, err := json.MarshalIndent(checksums, "", "  ")
+	if err != nil {
+		return err
+	}
+	return tools.WriteFileAtomic(filepath.Join(c.dir, version, ChecksumFile), content, 0o644)
+}
+
+// readBundle returns the files of each version in the tar.gz bundle
+func readBundle(data []byte) (map[string]map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("bundle must be a tar.gz, %s", err.Error())
+	}
+	defer gz.Close()
+	versions := make(map[string]map[string][]byte)
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		parts := strings.Split(name, "/")
+		switch {
+		case len(parts) == 2 && parts[1] == ChecksumFile:
+		case len(parts) == 4 && strings.HasSuffix(parts[3], ".yaml"):
+			if err := checkNames(parts[0], parts[1], parts[2], strings.TrimSuffix(parts[3], ".yaml")); err != nil {
+				return nil, err
+			}
+		default:
+			logrus.Warningf("[litmus catalog] %s in the bundle is ignored", header.Name)
+			continue
+		}
+		if err := checkNames(parts[0]); err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(io.LimitReader(reader, maxObjectSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(content) > maxObjectSize {
+			return nil, fmt.Errorf("%s in the bundle is larger than %d bytes", header.Name, maxObjectSize)
+		}
+		if versions[parts[0]] == nil {
+			versions[parts[0]] = make(map[string][]byte)
+		}
+		versions[parts[0]][strings.Join(parts[1:], "/")] = content
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no experiment in the bundle")
+	}
+	return versions, nil
+}
+
+// checkNames refuses the empty names and the names which escape the catalog directory
+func checkNames(names ...string) error {
+	for _, name := range names {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("illegal name %q", name)
+		}
+	}
+	return nil
+}
+
+// Sha256 returns the hex sha256 of the content
+func Sha256(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package catalog
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const podDelete = "kind: ChaosExperiment\nmetadata:\n  name: pod-delete\n"
+
+func bundleOf(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	writer := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := writer.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		writer.Write(content)
+	}
+	writer.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestCatalogGet(t *testing.T) {
+	requests := 0
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/1.13.5" || r.URL.Query().Get("file") != "charts/generic/pod-delete/experiment.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(podDelete))
+	}))
+	defer hub.Close()
+
+	dir := t.TempDir()
+	catalog := NewCatalog(dir, hub.URL)
+	if _, err := catalog.Get("1.13.5", "generic", "pod-delete", "experiment"); err == nil || requests != 0 {
+		t.Fatalf("Get() without checksum err = %v, hub requests = %d, want refused before fetch", err, requests)
+	}
+	// the checksum file without objects allows them to be fetched from the hub
+	checksums, _ := json.Marshal(map[string]string{"generic/pod-delete/experiment.yaml": Sha256([]byte(podDelete))})
+	if _, err := catalog.Import(bytes.NewReader(bundleOf(t, map[string][]byte{"1.13.5/checksums.json": checksums})), ""); err != nil {
+		t.Fatalf("Import() of checksum file err = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		content, err := catalog.Get("1.13.5", "generic", "pod-delete", "experiment")
+		if err != nil || string(content) != podDelete {
+			t.Fatalf("Get() = %q, %v, want the experiment", content, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("hub requests = %d, want 1 as the object is cached", requests)
+	}
+	if _, err := NewCatalog(dir, "").Get("1.13.5", "generic", "pod-delete", "experiment"); err != nil {
+		t.Errorf("offline Get() of cached object err = %v", err)
+	}
+	if _, err := NewCatalog(dir, "").Get("1.13.5", "generic", "pod-cpu-hog", "experiment"); err == nil {
+		t.Errorf("offline Get() of missing object err = nil")
+	}
+	if _, err := catalog.Get("..", "generic", "pod-delete", "experiment"); err == nil {
+		t.Errorf("Get() of illegal version err = nil")
+	}
+
+	// the tampered object is refused
+	os.WriteFile(filepath.Join(dir, "1.13.5", "generic", "pod-delete", "experiment.yaml"), []byte("tampered"), 0o644)
+	if _, err := catalog.Get("1.13.5", "generic", "pod-delete", "experiment"); err == nil {
+		t.Errorf("Get() of tampered object err = nil")
+	}
+}
+
+func TestCatalogGet_Oversized(t *testing.T) {
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := bytes.Repeat([]byte("#"), 1<<20)
+		for written := 0; written <= maxObjectSize; written += len(chunk) {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+		}
+	}))
+	defer hub.Close()
+
+	dir := t.TempDir()
+	catalog := NewCatalog(dir, hub.URL)
+	if _, err := catalog.Prefetch("1.13.5", []string{"generic/pod-delete"}, []string{"experiment"},
+		map[string]string{"generic/pod-delete/experiment.yaml": Sha256([]byte(podDelete))}); err == nil {
+		t.Errorf("Prefetch() of oversized object err = nil")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "1.13.5", "generic", "pod-delete", "experiment.yaml")); !os.IsNotExist(err) {
+		t.Errorf("oversized object is saved, err: %v", err)
+	}
+}
+
+func TestCatalogImport(t *testing.T) {
+	checksums, _ := json.Marshal(map[string]string{"generic/pod-delete/experiment.yaml": Sha256([]byte(podDelete))})
+	bundle := bundleOf(t, map[string][]byte{
+		"2.0.0/checksums.json":                     checksums,
+		"2.0.0/generic/pod-delete/experiment.yaml": []byte(podDelete),
+		"2.0.0/generic/pod-delete/rbac.yaml":       []byte("kind: Role"),
+	})
+
+	catalog := NewCatalog(t.TempDir(), "")
+	if _, err := catalog.Import(bytes.NewReader(bundle), "0000"); err == nil {
+		t.Errorf("Import() with wrong bundle checksum err = nil")
+	}
+	entries, err := catalog.Import(bytes.NewReader(bundle), Sha256(bundle))
+	if err != nil {
+		t.Fatalf("Import() err = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "pod-delete" || len(entries[0].Objects) != 2 {
+		t.Errorf("Import() = %+v, want pod-delete with experiment and rbac", entries)
+	}
+
+	tampered := bundleOf(t, map[string][]byte{
+		"2.0.1/checksums.json":                     checksums,
+		"2.0.1/generic/pod-delete/experiment.yaml": []byte("tampered"),
+	})
+	if _, err := catalog.Import(bytes.NewReader(tampered), ""); err == nil {
+		t.Errorf("Import() of tampered bundle err = nil")
+	}
+	if entries, _ := catalog.List("2.0.1"); len(entries) != 0 {
+		t.Errorf("List() of refused version = %+v, want empty", entries)
+	}
+}
+
+func TestCatalogPrefetch(t *testing.T) {
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(podDelete))
+	}))
+	defer hub.Close()
+
+	catalog := NewCatalog(t.TempDir(), hub.URL)
+	experiments, objects := []string{"generic/pod-delete"}, []string{"experiment"}
+	if _, err := catalog.Prefetch("1.13.5", experiments, objects, nil); err == nil {
+		t.Errorf("Prefetch() without checksums err = nil")
+	}
+	if _, err := catalog.Prefetch("1.13.5", experiments, objects, map[string]string{"generic/pod-delete/experiment.yaml": "0000"}); err == nil {
+		t.Errorf("Prefetch() with wrong checksums err = nil")
+	}
+	if entries, _ := catalog.List("1.13.5"); len(entries) != 0 {
+		t.Errorf("List() after refused prefetch = %+v, want empty", entries)
+	}
+	entries, err := catalog.Prefetch("1.13.5", experiments, objects, map[string]string{"generic/pod-delete/experiment.yaml": Sha256([]byte(podDelete))})
+	if err != nil || len(entries) != 1 || entries[0].Name != "pod-delete" {
+		t.Fatalf("Prefetch() = %+v, %v, want pod-delete", entries, err)
+	}
+	// the recorded checksums verify the later prefetch
+	if _, err := catalog.Prefetch("1.13.5", experiments, objects, nil); err != nil {
+		t.Errorf("Prefetch() by the recorded checksums err = %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+const (
+	// DefaultTimeout is used if the timeout of the call is not specified
+	DefaultTimeout = 60 * time.Second
+	// DefaultMaxOutput is the default capture limit of stdout and stderr respectively
+	DefaultMaxOutput = 4 << 20
+	// waitDelay is how long to wait for the pipes after the process is killed
+	waitDelay = 3 * time.Second
+)
+
+// Options of one call, the zero value uses the defaults
+type Options struct {
+	Timeout   time.Duration
+	MaxOutput int
+	Dir       string
+	Env       []string
+	// OnLine is called with each line of stdout and stderr while the process is running
+	OnLine func(line string)
+}
+
+// Result is the structured exit status of the process
+type Result struct {
+	Stdout          string        `json:"stdout"`
+	Stderr          string        `json:"stderr"`
+	StdoutTruncated bool          `json:"stdoutTruncated,omitempty"`
+	StderrTruncated bool          `json:"stderrTruncated,omitempty"`
+	ExitCode        int           `json:"exitCode"`
+	Signaled        bool          `json:"signaled,omitempty"`
+	TimedOut        bool          `json:"timedOut,omitempty"`
+	Duration        time.Duration `json:"duration"`
+	// Err is set if the process cannot be started or waited
+	Err error `json:"-"`
+}
+
+// Success returns true if the process exits with 0
+func (r *Result) Success() bool {
+	return r.Err == nil && r.ExitCode == 0 && !r.TimedOut && !r.Signaled
+}
+
+// Error describes why the call failed, with the stderr if any
+func (r *Result) Error() string {
+	if r.Success() {
+		return ""
+	}
+	var reason string
+	switch {
+	case r.TimedOut:
+		reason = fmt.Sprintf("timeout after %s", r.Duration.Truncate(time.Millisecond))
+	case r.Err != nil:
+		reason = r.Err.Error()
+	case r.Signaled:
+		reason = "killed by signal"
+	default:
+		reason = fmt.Sprintf("exit status %d", r.ExitCode)
+	}
+	if stderr := strings.TrimSpace(r.Stderr); stderr != "" {
+		return fmt.Sprintf("%s, stderr: %s", reason, stderr)
+	}
+	return reason
+}
+
+// Run executes the program with the arguments without shell. The whole process group is killed
+// if the timeout is reached or the ctx is done.
+func Run(ctx context.Context, name string, args []string, opts Options) *Result {
+	startTime := time.Now()
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxOutput <= 0 {
+		opts.MaxOutput = DefaultMaxOutput
+	}
+	if !tools.IsExist(name) {
+		return &Result{ExitCode: -1, Err: fmt.Errorf("%s not found", name)}
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: opts.MaxOutput}
+	stderr := &limitedBuffer{limit: opts.MaxOutput}
+	cmd := exec.CommandContext(timeoutCtx, name, args...)
+	cmd.Dir = opts.Dir
+	cmd.Env = opts.Env
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if opts.OnLine != nil {
+		stdoutLines := &lineWriter{fn: opts.OnLine}
+		stderrLines := &lineWriter{fn: opts.OnLine}
+		defer stdoutLines.flush()
+		defer stderrLines.flush()
+		cmd.Stdout = io.MultiWriter(stdout, stdoutLines)
+		cmd.Stderr = io.MultiWriter(stderr, stderrLines)
+	}
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+
+	logrus.Debugf("[executor] run %s %v, timeout: %s", name, args, opts.Timeout)
+	err := cmd.Run()
+	result := &Result{
+		Stdout:          stdout.String(),
+		Stderr:          stderr.String(),
+		StdoutTruncated: stdout.truncated,
+		StderrTruncated: stderr.truncated,
+		Duration:        time.Since(startTime),
+	}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+		result.Signaled = result.ExitCode == -1
+	}
+	if errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		result.TimedOut = true
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && !errors.Is(err, exec.ErrWaitDelay) {
+		result.Err = err
+		if cmd.ProcessState == nil {
+			result.ExitCode = -1
+		}
+	} else if ctx.Err() != nil && !result.TimedOut {
+		result.Err = ctx.Err()
+	}
+	if !result.Success() {
+		logrus.Warningf("[executor] run %s %v failed, duration: %v, err: %s", name, args, result.Duration, result.Error())
+	}
+	return result
+}
+
+// Shell executes the command line by the system shell, only for the legacy commands which rely on the shell
+func Shell(ctx context.Context, command string, opts Options) *Result {
+	if tools.IsWindows() {
+		return Run(ctx, shellPath(), []string{"/c", command}, opts)
+	}
+	return Run(ctx, shellPath(), []string{"-c", command}, opts)
+}
+
+// limitedBuffer keeps the first limit bytes and discards the rest, it never blocks the writer
+type limitedBuffer struct {
+	mutex     sync.Mutex
+	buf       []byte
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	remain := b.limit - len(b.buf)
+	if remain >= len(p) {
+		b.buf = append(b.buf, p...)
+		return len(p), nil
+	}
+	if remain > 0 {
+		b.buf = append(b.buf, p[:remain]...)
+	}
+	b.truncated = true
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return string(b.buf)
+}
+
+// lineWriter calls fn with each complete line, the incomplete line is kept until flush
+type lineWriter struct {
+	mutex sync.Mutex
+	buf   []byte
+	fn    func(line string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		index := bytes.IndexByte(w.buf, '\n')
+		if index < 0 {
+			break
+		}
+		w.fn(strings.TrimRight(string(w.buf[:index]), "\r"))
+		w.buf = w.buf[index+1:]
+	}
+	return len(p), nil
+}
+
+func (w *lineWriter) flush() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if len(w.buf) > 0 {
+		w.fn(string(w.buf))
+		w.buf = nil
+	}
+}
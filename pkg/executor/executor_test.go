@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		command      string
+		opts         Options
+		wantSuccess  bool
+		wantStdout   string
+		wantStderr   string
+		wantExitCode int
+		wantTimeout  bool
+		wantTrunc    bool
+	}{
+		{"success", `echo '{"code":200}'; echo warn >&2`, Options{}, true, "{\"code\":200}\n", "warn\n", 0, false, false},
+		{"exit code", "echo failed >&2; exit 3", Options{}, false, "", "failed\n", 3, false, false},
+		{"truncated", "echo 1234567890", Options{MaxOutput: 4}, true, "1234", "", 0, false, true},
+		{"timeout kills group", "sleep 30 & sleep 30", Options{Timeout: 200 * time.Millisecond}, false, "", "", -1, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			result := Shell(context.Background(), tt.command, tt.opts)
+			if result.Success() != tt.wantSuccess || result.Stdout != tt.wantStdout || result.Stderr != tt.wantStderr ||
+				result.ExitCode != tt.wantExitCode || result.TimedOut != tt.wantTimeout || result.StdoutTruncated != tt.wantTrunc {
+				t.Errorf("Shell() = %+v, err: %s", result, result.Error())
+			}
+			if time.Since(start) > 10*time.Second {
+				t.Errorf("Shell() took %v", time.Since(start))
+			}
+		})
+	}
+}
+
+func TestRun_NoShell(t *testing.T) {
+	result := Run(context.Background(), "/bin/echo", []string{"a b", "$HOME;", "`id`"}, Options{})
+	if !result.Success() || result.Stdout != "a b $HOME; `id`\n" {
+		t.Errorf("Run() = %+v", result)
+	}
+
+	result = Run(context.Background(), "/not/exist", nil, Options{})
+	if result.Success() || !strings.Contains(result.Error(), "not found") {
+		t.Errorf("Run() with missing program = %+v", result)
+	}
+}
+
+func TestRun_OnLine(t *testing.T) {
+	var mutex sync.Mutex
+	var lines []string
+	result := Shell(context.Background(), "echo one; echo two >&2; printf three", Options{OnLine: func(line string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		lines = append(lines, line)
+	}})
+	sort.Strings(lines)
+	if !result.Success() || strings.Join(lines, ",") != "one,three,two" {
+		t.Errorf("Shell() = %+v, lines: %v", result, lines)
+	}
+}
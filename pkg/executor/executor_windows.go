@@ -0,0 +1,32 @@
+//go:build windows
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// setProcessGroup only kills the process on windows, the default of exec.CommandContext
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func shellPath() string {
+	return filepath.Join(os.Getenv("SystemRoot"), "System32", "cmd.exe")
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CreateOperation  = "create"
+	DestroyOperation = "destroy"
+	PrepareOperation = "prepare"
+	RevokeOperation  = "revoke"
+	StatusOperation  = "status"
+
+	UidFlag = "uid"
+)
+
+// namePattern restricts the operation, scope, target, action and flag names
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Experiment is the structured blade command, eg:
+// operation: create, scope: k8s, target: pod-network, action: loss, flags: {percent: 50, namespace: default}
+type Experiment struct {
+	Operation string            `json:"operation"`
+	Scope     string            `json:"scope,omitempty"`
+	Target    string            `json:"target,omitempty"`
+	Action    string            `json:"action,omitempty"`
+	Uid       string            `json:"uid,omitempty"`
+	Flags     map[string]string `json:"flags,omitempty"`
+}
+
+// Parse reads the experiment from the request params: operation, scope, target, action, uid,
+// and flags which is a json object, eg: {"percent":"50","interface":"eth0"}
+func Parse(params map[string]string) (*Experiment, error) {
+	exp := &Experiment{
+		Operation: params["operation"],
+		Scope:     params["scope"],
+		Target:    params["target"],
+		Action:    params["action"],
+		Uid:       params["uid"],
+		Flags:     make(map[string]string),
+	}
+	if value := params["flags"]; value != "" {
+		var flags map[string]interface{}
+		if err := json.Unmarshal([]byte(value), &flags); err != nil {
+			return nil, fmt.Errorf("flags must be a json object, %s", err.Error())
+		}
+		for name, v := range flags {
+			switch v := v.(type) {
+			case string:
+				exp.Flags[name] = v
+			case float64:
+				exp.Flags[name] = strconv.FormatFloat(v, 'f', -1, 64)
+			case bool:
+				exp.Flags[name] = strconv.FormatBool(v)
+			default:
+				return nil, fmt.Errorf("the value of flag %s must be a string, number or bool", name)
+			}
+		}
+	}
+	return exp, exp.check()
+}
+
+// check validates the structure of the experiment without the blade spec
+func (e *Experiment) check() error {
+	switch e.Operation {
+	case CreateOperation:
+		if e.Target == "" || e.Action == "" {
+			return fmt.Errorf("target and action are required by %s", e.Operation)
+		}
+	case PrepareOperation:
+		if e.Target == "" {
+			return fmt.Errorf("target is required by %s", e.Operation)
+		}
+	case DestroyOperation, RevokeOperation, StatusOperation:
+		if e.Uid == "" {
+			return fmt.Errorf("uid is required by %s", e.Operation)
+		}
+	case "":
+		return fmt.Errorf("operation is required")
+	default:
+		return fmt.Errorf("operation %s is not supported", e.Operation)
+	}
+	for _, name := range []string{e.Scope, e.Target, e.Action, e.Uid} {
+		if name != "" && !namePattern.MatchString(name) {
+			return fmt.Errorf("illegal name %q", name)
+		}
+	}
+	for name := range e.Flags {
+		if !namePattern.MatchString(name) {
+			return fmt.Errorf("illegal flag name %q", name)
+		}
+		if name == UidFlag {
+			return fmt.Errorf("use the uid param instead of the uid flag")
+		}
+	}
+	return nil
+}
+
+// Args returns the blade arguments, the flags are sorted by name
+func (e *Experiment) Args() []string {
+	switch e.Operation {
+	case DestroyOperation, RevokeOperation, StatusOperation:
+		return []string{e.Operation, e.Uid}
+	}
+	args := []string{e.Operation}
+	for _, name := range []string{e.Scope, e.Target, e.Action} {
+		if name != "" {
+			args = append(args, name)
+		}
+	}
+	names := make([]string, 0, len(e.Flags))
+	for name := range e.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := e.Flags[name]
+		switch value {
+		case "":
+			args = append(args, "--"+name)
+		default:
+			args = append(args, "--"+name, value)
+		}
+	}
+	if e.Uid != "" {
+		args = append(args, "--"+UidFlag, e.Uid)
+	}
+	return args
+}
+
+// CommandLine joins the arguments for logs, the arguments with spaces or quotes are quoted
+func CommandLine(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`;&|<>*?()[]{}") {
+			quoted[i] = strconv.Quote(arg)
+		} else {
+			quoted[i] = arg
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ttlGrace is added to the timeout flag, so blade destroys the experiment itself before the watchdog
+const ttlGrace = time.Minute
+
+// TTL returns how long the created experiment can run: the requested ttl, or the timeout flag
+// with grace, or the default ttl. It's limited by max if max is positive, 0 means no limit.
+func TTL(args []string, requested, defaultTTL, max time.Duration) time.Duration {
+	ttl := requested
+	if ttl <= 0 {
+		ttl = defaultTTL
+		if timeout := timeoutFlag(args); timeout > 0 {
+			ttl = timeout + ttlGrace
+		}
+	}
+	if max > 0 && (ttl <= 0 || ttl > max) {
+		ttl = max
+	}
+	return ttl
+}
+
+// timeoutFlag returns the value of --timeout, unit: second
+func timeoutFlag(args []string) time.Duration {
+	for i, arg := range args {
+		var value string
+		switch {
+		case arg == "--timeout" && i+1 < len(args):
+			value = args[i+1]
+		case strings.HasPrefix(arg, "--timeout="):
+			value = strings.TrimPrefix(arg, "--timeout=")
+		default:
+			continue
+		}
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
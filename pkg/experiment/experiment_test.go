@@ -0,0 +1,262 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSpec = `
+version: v1
+kind: plugin
+items:
+- target: network
+  actions:
+  - action: loss
+    matchers:
+    - name: interface
+      required: true
+    - name: local-port
+    flags:
+    - name: percent
+      required: true
+    - name: force
+      noArgs: true
+- target: jvm
+  scope: host
+  actions:
+  - action: delay
+  flags:
+  - name: process
+`
+
+const testK8sSpec = `
+items:
+- target: network
+  scope: pod
+  actions:
+  - action: loss
+    aliases: [drop]
+    flags:
+    - name: namespace
+      required: true
+    - name: percent
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   map[string]string
+		wantArgs []string
+		wantErr  string
+	}{
+		{
+			name: "create",
+			params: map[string]string{"operation": "create", "target": "network", "action": "loss", "uid": "abc",
+				"flags": `{"interface":"eth0","percent":50,"force":true,"local-port":"80,8080"}`},
+			wantArgs: []string{"create", "network", "loss", "--force", "true", "--interface", "eth0",
+				"--local-port", "80,8080", "--percent", "50", "--uid", "abc"},
+		},
+		{
+			name:     "value with spaces",
+			params:   map[string]string{"operation": "create", "target": "script", "action": "delay", "flags": `{"file":"/tmp/a b.sh"}`},
+			wantArgs: []string{"create", "script", "delay", "--file", "/tmp/a b.sh"},
+		},
+		{
+			name:     "destroy",
+			params:   map[string]string{"operation": "destroy", "uid": "abc", "target": "ignored"},
+			wantArgs: []string{"destroy", "abc"},
+		},
+		{"no operation", map[string]string{}, nil, "operation is required"},
+		{"unsupported operation", map[string]string{"operation": "server"}, nil, "not supported"},
+		{"no action", map[string]string{"operation": "create", "target": "cpu"}, nil, "target and action are required"},
+		{"no uid", map[string]string{"operation": "destroy"}, nil, "uid is required"},
+		{"shell target", map[string]string{"operation": "create", "target": "cpu;reboot", "action": "load"}, nil, "illegal name"},
+		{"illegal flags", map[string]string{"operation": "create", "target": "cpu", "action": "load", "flags": `[1]`}, nil, "json object"},
+		{"illegal flag name", map[string]string{"operation": "create", "target": "cpu", "action": "load", "flags": `{"a b":"1"}`}, nil, "illegal flag name"},
+		{"uid flag", map[string]string{"operation": "create", "target": "cpu", "action": "load", "flags": `{"uid":"1"}`}, nil, "uid param"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp, err := Parse(tt.params)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() err: %v", err)
+			}
+			if got := exp.Args(); !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("Args() = %v, want %v", got, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSpec_Validate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "chaosblade-os-spec-1.7.4.yaml"), []byte(testSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "chaosblade-k8s-spec-1.7.4.yaml"), []byte(testK8sSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := LoadSpec(dir)
+	if err != nil {
+		t.Fatalf("LoadSpec() err: %v", err)
+	}
+	tests := []struct {
+		name     string
+		exp      Experiment
+		wantArgs []string
+		wantErr  string
+	}{
+		{
+			name:     "host",
+			exp:      Experiment{Operation: "create", Target: "network", Action: "loss", Flags: map[string]string{"interface": "eth0", "percent": "50", "force": "true", "timeout": "60"}},
+			wantArgs: []string{"create", "network", "loss", "--force", "--interface", "eth0", "--percent", "50", "--timeout", "60"},
+		},
+		{
+			name:     "no args flag false",
+			exp:      Experiment{Operation: "create", Target: "network", Action: "loss", Flags: map[string]string{"interface": "eth0", "percent": "50", "force": "false"}},
+			wantArgs: []string{"create", "network", "loss", "--interface", "eth0", "--percent", "50"},
+		},
+		{
+			name:     "k8s alias",
+			exp:      Experiment{Operation: "create", Scope: "k8s", Target: "pod-network", Action: "drop", Flags: map[string]string{"namespace": "default"}},
+			wantArgs: []string{"create", "k8s", "pod-network", "drop", "--namespace", "default"},
+		},
+		{
+			name:     "prepare",
+			exp:      Experiment{Operation: "prepare", Target: "jvm", Flags: map[string]string{"process": "tomcat"}},
+			wantArgs: []string{"prepare", "jvm", "--process", "tomcat"},
+		},
+		{"unknown target", Experiment{Operation: "create", Target: "disk", Action: "fill"}, nil, "target disk not found"},
+		{"unknown scope target", Experiment{Operation: "create", Scope: "k8s", Target: "network", Action: "loss"}, nil, "target network of k8s not found"},
+		{"unknown action", Experiment{Operation: "create", Target: "network", Action: "delay"}, nil, "action delay"},
+		{"unknown flag", Experiment{Operation: "create", Target: "network", Action: "loss", Flags: map[string]string{"interface": "eth0", "percent": "1", "rate": "1"}}, nil, "flag rate is not supported"},
+		{"required flag", Experiment{Operation: "create", Target: "network", Action: "loss", Flags: map[string]string{"interface": "eth0"}}, nil, "flag percent is required"},
+		{"no args flag value", Experiment{Operation: "create", Target: "network", Action: "loss", Flags: map[string]string{"interface": "eth0", "percent": "1", "force": "1"}}, nil, "has no value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := spec.Validate(&tt.exp)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Validate() err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() err: %v", err)
+			}
+			if got := tt.exp.Args(); !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("Args() = %v, want %v", got, tt.wantArgs)
+			}
+		})
+	}
+}
+
+// testJvmSpec is a part of chaosblade-jvm-spec, which describes the create flags only
+const testJvmSpec = `
+items:
+- target: jvm
+  actions:
+  - action: cpufullload
+    aliases: [cfl]
+    flags:
+    - name: cpu-count
+  flags:
+  - name: process
+  - name: pid
+  - name: effect-count
+  - name: effect-percent
+`
+
+func TestSpec_ValidateJvm(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "chaosblade-jvm-spec-1.7.4.yaml"), []byte(testJvmSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := LoadSpec(dir)
+	if err != nil {
+		t.Fatalf("LoadSpec() err: %v", err)
+	}
+	prepare := &Experiment{Operation: "prepare", Target: "jvm", Flags: map[string]string{"javaHome": "/opt/jdk", "port": "9526", "process": "tomcat"}}
+	if err := spec.Validate(prepare); err != nil {
+		t.Fatalf("Validate() prepare err: %v", err)
+	}
+	want := []string{"prepare", "jvm", "--javaHome", "/opt/jdk", "--port", "9526", "--process", "tomcat"}
+	if got := prepare.Args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Args() = %v, want %v", got, want)
+	}
+
+	create := &Experiment{Operation: "create", Target: "jvm", Action: "cfl", Flags: map[string]string{"process": "tomcat", "cpu-count": "2"}}
+	if err := spec.Validate(create); err != nil {
+		t.Fatalf("Validate() create err: %v", err)
+	}
+	create = &Experiment{Operation: "create", Target: "jvm", Action: "cfl", Flags: map[string]string{"javaHome": "/opt/jdk"}}
+	if err := spec.Validate(create); err == nil || !strings.Contains(err.Error(), "flag javaHome is not supported") {
+		t.Errorf("Validate() create with prepare flag err = %v, want not supported", err)
+	}
+	unknown := &Experiment{Operation: "prepare", Target: "python"}
+	if err := spec.Validate(unknown); err == nil || !strings.Contains(err.Error(), "target python not found") {
+		t.Errorf("Validate() unknown prepare target err = %v, want not found", err)
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	got := CommandLine([]string{"create", "script", "delay", "--file", "/tmp/a b.sh", "--cmd", "a;b"})
+	want := `create script delay --file "/tmp/a b.sh" --cmd "a;b"`
+	if got != want {
+		t.Errorf("CommandLine() = %s, want %s", got, want)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		requested time.Duration
+		want      time.Duration
+	}{
+		{"requested", []string{"create", "cpu", "load", "--timeout", "60"}, 10 * time.Minute, 10 * time.Minute},
+		{"timeout flag", []string{"create", "cpu", "load", "--timeout", "60"}, 0, 2 * time.Minute},
+		{"timeout flag with equal", []string{"create", "cpu", "load", "--timeout=120"}, 0, 3 * time.Minute},
+		{"default", []string{"create", "cpu", "load"}, 0, time.Hour},
+		{"illegal timeout", []string{"create", "cpu", "load", "--timeout", "abc"}, 0, time.Hour},
+		{"ceiling", []string{"create", "cpu", "load", "--timeout", "86400"}, 0, 2 * time.Hour},
+		{"requested ceiling", nil, 48 * time.Hour, 2 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TTL(tt.args, tt.requested, time.Hour, 2*time.Hour); got != tt.want {
+				t.Errorf("TTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := TTL(nil, 0, 0, 0); got != 0 {
+		t.Errorf("TTL() without limit = %v, want 0", got)
+	}
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package experiment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+// specFilePattern matches the spec files of blade, eg: chaosblade-os-spec-1.7.4.yaml
+var specFilePattern = regexp.MustCompile(`^chaosblade-([a-z0-9]+)-spec.*\.yaml$`)
+
+// scopedKinds are the spec kinds which are invoked with the kind as the scope, eg: blade create k8s ...
+var scopedKinds = map[string]bool{
+	"k8s":    true,
+	"cri":    true,
+	"docker": true,
+}
+
+// commonFlags are accepted by all of the experiments
+var commonFlags = map[string]bool{
+	"timeout":     true,
+	"async":       true,
+	"endpoint":    true,
+	"nohup":       true,
+	"debug":       true,
+	"override":    true,
+	"cgroup-root": true,
+}
+
+// models is the blade spec file, only the fields used by validation are declared
+type models struct {
+	Items []targetModel `json:"items"`
+}
+
+type targetModel struct {
+	Target  string        `json:"target"`
+	Scope   string        `json:"scope"`
+	Actions []actionModel `json:"actions"`
+	Flags   []FlagSpec    `json:"flags"`
+}
+
+type actionModel struct {
+	Action   string     `json:"action"`
+	Aliases  []string   `json:"aliases"`
+	Matchers []FlagSpec `json:"matchers"`
+	Flags    []FlagSpec `json:"flags"`
+}
+
+type FlagSpec struct {
+	Name     string `json:"name"`
+	NoArgs   bool   `json:"noArgs"`
+	Required bool   `json:"required"`
+}
+
+// TargetSpec is the actions of a target, key is the action name and aliases
+type TargetSpec struct {
+	Actions map[string]*ActionSpec
+	Flags   map[string]FlagSpec
+}
+
+type ActionSpec struct {
+	Name  string
+	Flags map[string]FlagSpec
+}
+
+// Spec is the flag model of blade, key is scope, then target. The scope of host experiments is empty.
+type Spec struct {
+	Targets map[string]map[string]*TargetSpec
+}
+
+// LoadSpec reads the spec files in the yaml directory of blade
+func LoadSpec(dir string) (*Spec, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	spec := &Spec{Targets: make(map[string]map[string]*TargetSpec)}
+	for _, entry := range entries {
+		matches := specFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		bytes, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var m models
+		if err := yaml.Unmarshal(bytes, &m); err != nil {
+			return nil, fmt.Errorf("parse %s failed, %s", entry.Name(), err.Error())
+		}
+		spec.add(matches[1], &m)
+	}
+	return spec, nil
+}
+
+func (s *Spec) add(kind string, m *models) {
+	scope := ""
+	if scopedKinds[kind] {
+		scope = kind
+	}
+	targets, ok := s.Targets[scope]
+	if !ok {
+		targets = make(map[string]*TargetSpec)
+		s.Targets[scope] = targets
+	}
+	for _, item := range m.Items {
+		name := item.Target
+		// the k8s experiments are invoked as pod-network, node-cpu, container-process
+		if kind == "k8s" && item.Scope != "" {
+			name = fmt.Sprintf("%s-%s", item.Scope, item.Target)
+		}
+		target, ok := targets[name]
+		if !ok {
+			target = &TargetSpec{Actions: make(map[string]*ActionSpec), Flags: make(map[string]FlagSpec)}
+			targets[name] = target
+		}
+		for _, flag := range item.Flags {
+			target.Flags[flag.Name] = flag
+		}
+		for _, action := range item.Actions {
+			as := &ActionSpec{Name: action.Action, Flags: make(map[string]FlagSpec)}
+			for _, flag := range append(action.Matchers, action.Flags...) {
+				as.Flags[flag.Name] = flag
+			}
+			target.Actions[action.Action] = as
+			for _, alias := range action.Aliases {
+				target.Actions[alias] = as
+			}
+		}
+	}
+}
+
+// Validate checks the target, action and flags of create experiments and the target of prepare
+// experiments with the spec. The spec files only describe the create flags, so the flags of prepare,
+// eg: --javaHome and --port of prepare jvm, are left to blade.
+// The flags without args are rewritten: true to empty value, false is removed.
+func (s *Spec) Validate(e *Experiment) error {
+	if e.Operation != CreateOperation && e.Operation != PrepareOperation {
+		return nil
+	}
+	target, ok := s.Targets[e.Scope][e.Target]
+	if !ok {
+		if e.Scope != "" {
+			return fmt.Errorf("target %s of %s not found", e.Target, e.Scope)
+		}
+		return fmt.Errorf("target %s not found", e.Target)
+	}
+	if e.Operation == PrepareOperation {
+		return nil
+	}
+	action, ok := target.Actions[e.Action]
+	if !ok {
+		return fmt.Errorf("action %s of target %s not found", e.Action, e.Target)
+	}
+	flags := make(map[string]FlagSpec, len(target.Flags)+len(action.Flags))
+	for name, flag := range target.Flags {
+		flags[name] = flag
+	}
+	for name, flag := range action.Flags {
+		flags[name] = flag
+	}
+
+	for name, value := range e.Flags {
+		flag, ok := flags[name]
+		if !ok {
+			if commonFlags[name] {
+				continue
+			}
+			return fmt.Errorf("flag %s is not supported by %s %s", name, e.Target, e.Action)
+		}
+		if !flag.NoArgs {
+			continue
+		}
+		switch value {
+		case "", "true":
+			e.Flags[name] = ""
+		case "false":
+			delete(e.Flags, name)
+		default:
+			return fmt.Errorf("flag %s has no value, but got %s", name, value)
+		}
+	}
+	for name, flag := range flags {
+		if _, ok := e.Flags[name]; flag.Required && !ok {
+			return fmt.Errorf("flag %s is required", name)
+		}
+	}
+	return nil
+}
+
+var (
+	specMutex   sync.Mutex
+	specDir     string
+	specModTime time.Time
+	cachedSpec  *Spec
+)
+
+// GetSpec returns the spec of the yaml directory, which is reloaded if the directory is modified.
+// It returns nil if the spec files cannot be read, then the experiments are not validated by spec.
+func GetSpec(dir string) *Spec {
+	specMutex.Lock()
+	defer specMutex.Unlock()
+	info, err := os.Stat(dir)
+	if err != nil {
+		logrus.Debugf("[experiment] blade spec directory %s not found, err: %v", dir, err)
+		return nil
+	}
+	if cachedSpec != nil && dir == specDir && info.ModTime().Equal(specModTime) {
+		return cachedSpec
+	}
+	spec, err := LoadSpec(dir)
+	if err != nil {
+		logrus.Warningf("[experiment] load blade spec from %s failed, err: %v", dir, err)
+		return nil
+	}
+	cachedSpec, specDir, specModTime = spec, dir, info.ModTime()
+	return cachedSpec
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+const (
+	// capNetAdmin and capSysAdmin are the bits of the linux capabilities
+	capNetAdmin = 12
+	capSysAdmin = 21
+
+	SELinuxEnforcing  = "enforcing"
+	SELinuxPermissive = "permissive"
+	Disabled          = "disabled"
+	Enabled           = "enabled"
+)
+
+// capabilityTools are the commands required by the network experiments
+var capabilityTools = []string{"tc", "iptables", "nft"}
+
+// Capabilities is what the experiments need from the host, the console hides the unsupported experiments
+type Capabilities struct {
+	// Chaosblade is the installed chaosblade version, empty if it's not installed
+	Chaosblade        string          `json:"chaosblade"`
+	KernelVersion     string          `json:"kernelVersion"`
+	CgroupVersion     string          `json:"cgroupVersion"`
+	Tools             map[string]bool `json:"tools"`
+	ContainerRuntimes []string        `json:"containerRuntimes"`
+	Jvms              []int           `json:"jvms"`
+	SELinux           string          `json:"selinux"`
+	AppArmor          string          `json:"apparmor"`
+	Root              bool            `json:"root"`
+	NetAdmin          bool            `json:"netAdmin"`
+	SysAdmin          bool            `json:"sysAdmin"`
+	// Supports is whether the experiment targets can run, eg: network, jvm
+	Supports map[string]bool `json:"supports"`
+}
+
+var (
+	capabilityMutex sync.Mutex
+	capabilities    *Capabilities
+	discoverTime    time.Time
+)
+
+// DiscoverCapabilities probes the host, the facts which cannot be read are left empty
+func DiscoverCapabilities() *Capabilities {
+	c := &Capabilities{
+		Chaosblade:        chaosbladeVersion(),
+		KernelVersion:     GetKernelVersion(),
+		CgroupVersion:     GetCgroupVersion(),
+		Tools:             make(map[string]bool, len(capabilityTools)),
+		ContainerRuntimes: GetContainerRuntimes(),
+		Jvms:              GetJvms(),
+		SELinux:           GetSELinuxMode(),
+		AppArmor:          GetAppArmorMode(),
+		Root:              os.Geteuid() == 0,
+	}
+	for _, tool := range capabilityTools {
+		_, err := exec.LookPath(tool)
+		c.Tools[tool] = err == nil
+	}
+	if effective, ok := parseCapEff(readStatusField("/proc/self/status", "CapEff")); ok {
+		c.NetAdmin = effective&(1<<capNetAdmin) != 0
+		c.SysAdmin = effective&(1<<capSysAdmin) != 0
+	} else {
+		c.NetAdmin, c.SysAdmin = c.Root, c.Root
+	}
+	c.Supports = supports(c)
+	return c
+}
+
+// CurrentCapabilities returns the capabilities discovered last time, they are discovered if absent
+func CurrentCapabilities() *Capabilities {
+	capabilityMutex.Lock()
+	defer capabilityMutex.Unlock()
+	if capabilities == nil {
+		capabilities, discoverTime = DiscoverCapabilities(), time.Now()
+	}
+	return capabilities
+}
+
+// RefreshCapabilities discovers the capabilities again if they are older than maxAge, changed is true
+// if they are different from the last ones
+func RefreshCapabilities(maxAge time.Duration) (current *Capabilities, changed bool) {
+	capabilityMutex.Lock()
+	defer capabilityMutex.Unlock()
+	if capabilities != nil && time.Since(discoverTime) < maxAge {
+		return capabilities, false
+	}
+	previous := capabilities
+	capabilities, discoverTime = DiscoverCapabilities(), time.Now()
+	return capabilities, previous == nil || !capabilities.Equal(previous)
+}
+
+// Equal returns true if the capabilities are the same. The pids of the jvms are excluded, they
+// change with every restart of the java processes, whether jvm is supported is compared instead.
+func (c *Capabilities) Equal(other *Capabilities) bool {
+	a, err := c.fingerprint()
+	if err != nil {
+		return false
+	}
+	b, err := other.fingerprint()
+	return err == nil && string(a) == string(b)
+}
+
+func (c *Capabilities) fingerprint() ([]byte, error) {
+	copied := *c
+	copied.Jvms = nil
+	return json.Marshal(&copied)
+}
+
+// Gaps returns the capabilities which are unavailable, chaosblade if it's not installed and the
+// experiment targets which cannot run
+func (c *Capabilities) Gaps() []string {
+	gaps := make([]string, 0)
+	if c.Chaosblade == "" {
+		gaps = append(gaps, options.BladeDirName)
+	}
+	targets := make([]string, 0)
+	for target, supported := range c.Supports {
+		if !supported {
+			targets = append(targets, target)
+		}
+	}
+	sort.Strings(targets)
+	return append(gaps, targets...)
+}
+
+func chaosbladeVersion() string {
+	if options.Opts == nil {
+		return ""
+	}
+	return options.Opts.ChaosbladeVersion
+}
+
+// supports returns whether the experiment targets can run with the capabilities
+func supports(c *Capabilities) map[string]bool {
+	privileged := c.Root || c.SysAdmin
+	return map[string]bool{
+		"cpu":       true,
+		"mem":       true,
+		"process":   true,
+		"network":   c.Tools["tc"] && c.NetAdmin,
+		"firewall":  (c.Tools["iptables"] || c.Tools["nft"]) && c.NetAdmin,
+		"disk":      privileged,
+		"container": len(c.ContainerRuntimes) > 0 && c.CgroupVersion != "" && privileged,
+		"jvm":       len(c.Jvms) > 0,
+	}
+}
+
+// GetJvms returns the pids of the running java processes
+func GetJvms() []int {
+	jvms := make([]int, 0)
+	for _, pid := range tools.FindProcesses("java") {
+		fields := strings.Fields(tools.ProcessCmdline(pid))
+		if len(fields) > 0 && filepath.Base(fields[0]) == "java" {
+			jvms = append(jvms, pid)
+		}
+	}
+	return jvms
+}
+
+// GetSELinuxMode returns enforcing, permissive or disabled
+func GetSELinuxMode() string {
+	switch readFirstLine("/sys/fs/selinux/enforce") {
+	case "1":
+		return SELinuxEnforcing
+	case "0":
+		return SELinuxPermissive
+	}
+	return Disabled
+}
+
+// GetAppArmorMode returns enabled or disabled
+func GetAppArmorMode() string {
+	if readFirstLine("/sys/module/apparmor/parameters/enabled") == "Y" {
+		return Enabled
+	}
+	return Disabled
+}
+
+// parseCapEff parses the hex capability set of /proc/<pid>/status
+func parseCapEff(value string) (uint64, bool) {
+	if value == "" {
+		return 0, false
+	}
+	effective, err := strconv.ParseUint(value, 16, 64)
+	return effective, err == nil
+}
+
+// readStatusField returns the value of the field in the proc status file
+func readStatusField(fileName, field string) string {
+	bytes, err := os.ReadFile(fileName)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(bytes), "\n") {
+		if strings.HasPrefix(line, field+":") {
+			return strings.TrimSpace(strings.TrimPrefix(line, field+":"))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCapEff(t *testing.T) {
+	tests := []struct {
+		value    string
+		netAdmin bool
+		sysAdmin bool
+		ok       bool
+	}{
+		{value: "000001ffffffffff", netAdmin: true, sysAdmin: true, ok: true},
+		{value: "0000000000001000", netAdmin: true, ok: true},
+		{value: "0000000000000000", ok: true},
+		{value: ""},
+		{value: "broken"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			effective, ok := parseCapEff(tt.value)
+			if ok != tt.ok {
+				t.Fatalf("parseCapEff() ok = %t, want %t", ok, tt.ok)
+			}
+			if netAdmin := effective&(1<<capNetAdmin) != 0; netAdmin != tt.netAdmin {
+				t.Errorf("netAdmin = %t, want %t", netAdmin, tt.netAdmin)
+			}
+			if sysAdmin := effective&(1<<capSysAdmin) != 0; sysAdmin != tt.sysAdmin {
+				t.Errorf("sysAdmin = %t, want %t", sysAdmin, tt.sysAdmin)
+			}
+		})
+	}
+}
+
+func TestSupports(t *testing.T) {
+	c := &Capabilities{
+		Tools:             map[string]bool{"tc": true, "nft": true},
+		ContainerRuntimes: []string{"containerd"},
+		CgroupVersion:     CgroupV2,
+		NetAdmin:          true,
+	}
+	got := supports(c)
+	want := map[string]bool{"network": true, "firewall": true, "disk": false, "container": false, "jvm": false}
+	for target, supported := range want {
+		if got[target] != supported {
+			t.Errorf("supports()[%s] = %t, want %t", target, got[target], supported)
+		}
+	}
+}
+
+func TestCapabilities_Gaps(t *testing.T) {
+	c := &Capabilities{Supports: map[string]bool{"cpu": true, "network": false, "jvm": false}}
+	if got, want := c.Gaps(), []string{"chaosblade", "jvm", "network"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Gaps() = %v, want %v", got, want)
+	}
+	c.Chaosblade = "1.7.4"
+	if got, want := c.Gaps(), []string{"jvm", "network"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Gaps() = %v, want %v", got, want)
+	}
+}
+
+func TestCapabilities_Equal(t *testing.T) {
+	a := &Capabilities{Jvms: []int{100}, Supports: map[string]bool{"jvm": true}}
+	b := &Capabilities{Jvms: []int{200}, Supports: map[string]bool{"jvm": true}}
+	if !a.Equal(b) {
+		t.Errorf("Equal() = false with different jvm pids, want true")
+	}
+	b.Supports["jvm"] = false
+	if a.Equal(b) {
+		t.Errorf("Equal() = true with different supports, want false")
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+const (
+	CgroupV1 = "v1"
+	CgroupV2 = "v2"
+)
+
+// runtimeSockets is the well-known sockets of container runtimes
+var runtimeSockets = map[string][]string{
+	"docker":     {"/var/run/docker.sock", "/run/docker.sock"},
+	"containerd": {"/run/containerd/containerd.sock", "/var/run/containerd/containerd.sock"},
+	"crio":       {"/var/run/crio/crio.sock", "/run/crio/crio.sock"},
+	"podman":     {"/run/podman/podman.sock"},
+}
+
+// Environment is the facts of the host which the agent runs on
+type Environment struct {
+	Os                string   `json:"os"`
+	Arch              string   `json:"arch"`
+	OsRelease         string   `json:"osRelease"`
+	KernelVersion     string   `json:"kernelVersion"`
+	CgroupVersion     string   `json:"cgroupVersion"`
+	ContainerRuntimes []string `json:"containerRuntimes"`
+	InContainer       bool     `json:"inContainer"`
+	NumCpu            int      `json:"numCpu"`
+	GoVersion         string   `json:"goVersion"`
+}
+
+// GetEnvironment collects the environment facts, the facts which cannot be read are left empty
+func GetEnvironment() *Environment {
+	return &Environment{
+		Os:                runtime.GOOS,
+		Arch:              runtime.GOARCH,
+		OsRelease:         GetOsRelease(),
+		KernelVersion:     GetKernelVersion(),
+		CgroupVersion:     GetCgroupVersion(),
+		ContainerRuntimes: GetContainerRuntimes(),
+		InContainer:       IsInContainer(),
+		NumCpu:            runtime.NumCPU(),
+		GoVersion:         runtime.Version(),
+	}
+}
+
+// GetKernelVersion returns the kernel release, eg: 5.10.134-16.al8.x86_64
+func GetKernelVersion() string {
+	return readFirstLine("/proc/sys/kernel/osrelease")
+}
+
+// GetOsRelease returns the pretty name in /etc/os-release
+func GetOsRelease() string {
+	bytes, err := os.ReadFile("/etc/os-release")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(bytes), "\n") {
+		if strings.HasPrefix(line, "PRETTY_NAME=") {
+			return strings.Trim(strings.TrimPrefix(line, "PRETTY_NAME="), `"`)
+		}
+	}
+	return ""
+}
+
+// GetCgroupVersion returns v2 if the unified hierarchy is mounted, otherwise v1
+func GetCgroupVersion() string {
+	if tools.IsExist("/sys/fs/cgroup/cgroup.controllers") {
+		return CgroupV2
+	}
+	if tools.IsExist("/sys/fs/cgroup") {
+		return CgroupV1
+	}
+	return ""
+}
+
+// GetContainerRuntimes returns the container runtimes whose socket exists
+func GetContainerRuntimes() []string {
+	runtimes := make([]string, 0)
+	for _, name := range []string{"docker", "containerd", "crio", "podman"} {
+		for _, socket := range runtimeSockets[name] {
+			if tools.IsExist(socket) {
+				runtimes = append(runtimes, name)
+				break
+			}
+		}
+	}
+	return runtimes
+}
+
+// IsInContainer returns true if the agent itself runs in a container
+func IsInContainer() bool {
+	if tools.IsExist("/.dockerenv") || tools.IsExist("/run/.containerenv") {
+		return true
+	}
+	bytes, err := os.ReadFile("/proc/1/cgroup")
+	if err != nil {
+		return false
+	}
+	content := string(bytes)
+	return strings.Contains(content, "docker") || strings.Contains(content, "kubepods") ||
+		strings.Contains(content, "containerd")
+}
+
+func readFirstLine(fileName string) string {
+	bytes, err := os.ReadFile(fileName)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.SplitN(string(bytes), "\n", 2)[0])
+}
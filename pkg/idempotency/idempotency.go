@@ -0,0 +1,141 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+// ErrConflict means the key is used by another command
+var ErrConflict = errors.New("idempotency key is used by another command")
+
+// Entry is the result of the command executed with the key
+type Entry struct {
+	Key        string              `json:"key"`
+	Command    string              `json:"command"`
+	Response   *transport.Response `json:"response,omitempty"`
+	CreateTime time.Time           `json:"createTime"`
+
+	// done is closed once the response is set
+	done chan struct{}
+}
+
+// Store keeps the successful results of the keys for retention, the failed ones are forgotten
+// so the command is executed again by the retry
+type Store struct {
+	mutex     sync.Mutex
+	file      string
+	retention time.Duration
+	entries   map[string]*Entry
+}
+
+// NewStore loads the results from the file, the broken file is moved to .corrupt
+func NewStore(file string, retention time.Duration) *Store {
+	store := &Store{file: file, retention: retention, entries: make(map[string]*Entry)}
+	var entries []*Entry
+	loaded, err := tools.LoadJsonFile(file, &entries)
+	if err != nil {
+		logrus.Errorf("[idempotency] load idempotency file %s failed, err: %v", file, err)
+		return store
+	}
+	if !loaded {
+		return store
+	}
+	for _, entry := range entries {
+		entry.done = make(chan struct{})
+		close(entry.done)
+		store.entries[entry.Key] = entry
+	}
+	store.mutex.Lock()
+	store.cleanLocked(time.Now())
+	store.mutex.Unlock()
+	logrus.Infof("[idempotency] idempotency file %s loaded, keys: %d", file, len(store.entries))
+	return store
+}
+
+// Do executes fn once for the key. The duplicates get the result of the first execution, and wait
+// for it if it's still running. executed is false if fn is not called.
+func (s *Store) Do(key, command string, fn func() *transport.Response) (response *transport.Response, executed bool, err error) {
+	s.mutex.Lock()
+	s.cleanLocked(time.Now())
+	if entry, ok := s.entries[key]; ok {
+		s.mutex.Unlock()
+		if entry.Command != command {
+			return nil, false, ErrConflict
+		}
+		<-entry.done
+		return entry.Response, false, nil
+	}
+	entry := &Entry{Key: key, Command: command, CreateTime: time.Now(), done: make(chan struct{})}
+	s.entries[key] = entry
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		entry.Response = response
+		if response == nil || !response.Success {
+			delete(s.entries, key)
+		} else if err := s.saveLocked(); err != nil {
+			logrus.Warningf("[idempotency] save idempotency file %s failed, err: %v", s.file, err)
+		}
+		close(entry.done)
+	}()
+	return fn(), true, nil
+}
+
+// cleanLocked removes the finished entries created before retention
+func (s *Store) cleanLocked(now time.Time) {
+	if s.retention <= 0 {
+		return
+	}
+	expired := now.Add(-s.retention)
+	for key, entry := range s.entries {
+		if entry.Response != nil && entry.CreateTime.Before(expired) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func (s *Store) saveLocked() error {
+	if s.file == "" {
+		return nil
+	}
+	entries := make([]*Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		if entry.Response != nil {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreateTime.Before(entries[j].CreateTime)
+	})
+	bytes, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return tools.WriteFileAtomic(s.file, bytes, 0o600)
+}
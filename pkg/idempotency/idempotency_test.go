@@ -0,0 +1,95 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idempotency
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+func TestStoreDo(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "idempotency.json")
+	store := NewStore(file, time.Hour)
+
+	var calls int32
+	started, finish := make(chan struct{}), make(chan struct{})
+	create := func() *transport.Response {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-finish
+		}
+		return transport.ReturnSuccessWithResult("uid-1")
+	}
+
+	var wg sync.WaitGroup
+	responses := make([]*transport.Response, 2)
+	executed := make([]bool, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		responses[0], executed[0], _ = store.Do("key", "create cpu fullload", create)
+	}()
+	<-started
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		responses[1], executed[1], _ = store.Do("key", "create cpu fullload", create)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(finish)
+	wg.Wait()
+
+	if calls != 1 || !executed[0] || executed[1] {
+		t.Fatalf("calls = %d, executed = %v, want the command executed once", calls, executed)
+	}
+	if responses[1].Result != "uid-1" {
+		t.Errorf("duplicate result = %v, want uid-1", responses[1].Result)
+	}
+	if _, _, err := store.Do("key", "create mem load", create); err != ErrConflict {
+		t.Errorf("Do() with another command err = %v, want ErrConflict", err)
+	}
+
+	// restored by the new store
+	response, ok, _ := NewStore(file, time.Hour).Do("key", "create cpu fullload", create)
+	if ok || response.Result != "uid-1" {
+		t.Errorf("restored Do() = %v, %t, want uid-1 not executed", response.Result, ok)
+	}
+}
+
+func TestStoreForgetFailure(t *testing.T) {
+	store := NewStore("", time.Hour)
+	fail := func() *transport.Response { return transport.ReturnFail(transport.Upgrading, "retry later") }
+	store.Do("key", "create cpu fullload", fail)
+	if _, executed, _ := store.Do("key", "create cpu fullload", fail); !executed {
+		t.Errorf("failed result is kept, want executed again")
+	}
+}
+
+func TestStoreRetention(t *testing.T) {
+	store := NewStore("", time.Minute)
+	success := func() *transport.Response { return transport.ReturnSuccessWithResult("uid") }
+	store.Do("key", "create cpu fullload", success)
+	store.entries["key"].CreateTime = time.Now().Add(-2 * time.Minute)
+	if _, executed, _ := store.Do("key", "create cpu fullload", success); !executed {
+		t.Errorf("expired result is kept, want executed again")
+	}
+}
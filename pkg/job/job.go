@@ -0,0 +1,226 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package job
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+type Phase string
+
+const (
+	Pending   Phase = "Pending"
+	Running   Phase = "Running"
+	Succeeded Phase = "Succeeded"
+	Failed    Phase = "Failed"
+)
+
+var ErrQueueFull = errors.New("job queue is full")
+
+// Job is an asynchronous execution of a command
+type Job struct {
+	Id         string              `json:"id"`
+	RequestId  string              `json:"rid,omitempty"`
+	Command    string              `json:"command"`
+	Phase      Phase               `json:"phase"`
+	Stdout     string              `json:"stdout,omitempty"`
+	Stderr     string              `json:"stderr,omitempty"`
+	ExitCode   *int                `json:"exitCode,omitempty"`
+	Response   *transport.Response `json:"response,omitempty"`
+	SubmitTime time.Time           `json:"submitTime"`
+	StartTime  *time.Time          `json:"startTime,omitempty"`
+	EndTime    *time.Time          `json:"endTime,omitempty"`
+}
+
+// Output is the result of a job function
+type Output struct {
+	Stdout   string
+	Stderr   string
+	ExitCode *int
+	Response *transport.Response
+}
+
+// Func does the work of the job, id is the job id
+type Func func(id string) *Output
+
+// DoneFunc is called with the copy of the job when the job is finished
+type DoneFunc func(job *Job)
+
+type task struct {
+	job  *Job
+	fn   Func
+	done DoneFunc
+}
+
+// Manager runs the jobs in a bounded worker pool and keeps the finished jobs for retention
+type Manager struct {
+	mutex     sync.Mutex
+	jobs      map[string]*Job
+	queue     chan *task
+	retention time.Duration
+}
+
+func NewManager(workers, queueSize int, retention time.Duration) *Manager {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	m := &Manager{
+		jobs:      make(map[string]*Job),
+		queue:     make(chan *task, queueSize),
+		retention: retention,
+	}
+	for i := 0; i < workers; i++ {
+		go m.work()
+	}
+	return m
+}
+
+// Submit queues the job, returns ErrQueueFull if there are too many pending jobs
+func (m *Manager) Submit(command, rid string, fn Func, done DoneFunc) (*Job, error) {
+	job := &Job{
+		Id:         tools.GetUUID(),
+		RequestId:  rid,
+		Command:    command,
+		Phase:      Pending,
+		SubmitTime: time.Now(),
+	}
+	m.mutex.Lock()
+	m.cleanLocked()
+	m.jobs[job.Id] = job
+	snapshot := *job
+	m.mutex.Unlock()
+
+	select {
+	case m.queue <- &task{job: job, fn: fn, done: done}:
+		return &snapshot, nil
+	default:
+		m.mutex.Lock()
+		delete(m.jobs, job.Id)
+		m.mutex.Unlock()
+		return nil, ErrQueueFull
+	}
+}
+
+// Get returns the copy of the job
+func (m *Manager) Get(id string) (*Job, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	snapshot := *job
+	return &snapshot, true
+}
+
+// List returns the copies of all jobs ordered by submit time
+func (m *Manager) List() []*Job {
+	m.mutex.Lock()
+	m.cleanLocked()
+	jobs := make([]*Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		snapshot := *job
+		jobs = append(jobs, &snapshot)
+	}
+	m.mutex.Unlock()
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].SubmitTime.Before(jobs[j].SubmitTime)
+	})
+	return jobs
+}
+
+func (m *Manager) work() {
+	for t := range m.queue {
+		m.run(t)
+	}
+}
+
+func (m *Manager) run(t *task) {
+	m.update(t.job, func(job *Job) {
+		now := time.Now()
+		job.Phase = Running
+		job.StartTime = &now
+	})
+
+	output := m.call(t)
+	m.update(t.job, func(job *Job) {
+		now := time.Now()
+		job.EndTime = &now
+		job.Stdout = output.Stdout
+		job.Stderr = output.Stderr
+		job.ExitCode = output.ExitCode
+		job.Response = output.Response
+		if output.Response != nil && output.Response.Success {
+			job.Phase = Succeeded
+		} else {
+			job.Phase = Failed
+		}
+	})
+	logrus.Infof("[job] job %s finished, command: %s", t.job.Id, t.job.Command)
+
+	if t.done != nil {
+		snapshot, _ := m.Get(t.job.Id)
+		if snapshot != nil {
+			t.done(snapshot)
+		}
+	}
+}
+
+// call runs the job function, a panic fails the job instead of the worker
+func (m *Manager) call(t *task) (output *Output) {
+	defer func() {
+		if err := recover(); err != nil {
+			logrus.Warningf("[job] job %s panic, err: %v", t.job.Id, err)
+			output = &Output{Response: transport.ReturnFail(transport.ServerError, "job panic")}
+		}
+	}()
+	output = t.fn(t.job.Id)
+	if output == nil {
+		output = &Output{Response: transport.ReturnFail(transport.ServerError, "job has no output")}
+	}
+	return output
+}
+
+func (m *Manager) update(job *Job, fn func(job *Job)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	fn(job)
+}
+
+// cleanLocked removes the jobs finished before retention
+func (m *Manager) cleanLocked() {
+	if m.retention <= 0 {
+		return
+	}
+	expired := time.Now().Add(-m.retention)
+	for id, job := range m.jobs {
+		if job.EndTime != nil && job.EndTime.Before(expired) {
+			delete(m.jobs, id)
+		}
+	}
+}
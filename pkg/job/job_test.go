@@ -0,0 +1,109 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+func TestManager_Submit(t *testing.T) {
+	tests := []struct {
+		name      string
+		fn        Func
+		wantPhase Phase
+	}{
+		{"success", func(string) *Output {
+			return &Output{Stdout: "out", Response: transport.ReturnSuccessWithResult("uid")}
+		}, Succeeded},
+		{"fail", func(string) *Output {
+			return &Output{Stderr: "err", Response: transport.ReturnFail(transport.ServerError, "err")}
+		}, Failed},
+		{"panic", func(string) *Output {
+			panic("boom")
+		}, Failed},
+		{"no output", func(string) *Output {
+			return nil
+		}, Failed},
+	}
+	m := NewManager(2, 4, time.Hour)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan *Job, 1)
+			job, err := m.Submit("create cpu load", "rid", tt.fn, func(job *Job) { done <- job })
+			if err != nil {
+				t.Fatalf("Submit() err: %v", err)
+			}
+			if job.Phase != Pending {
+				t.Errorf("Submit() phase = %s, want %s", job.Phase, Pending)
+			}
+			select {
+			case finished := <-done:
+				if finished.Phase != tt.wantPhase || finished.StartTime == nil || finished.EndTime == nil {
+					t.Errorf("finished job = %+v, want phase %s", finished, tt.wantPhase)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("job not finished")
+			}
+			got, ok := m.Get(job.Id)
+			if !ok || got.Phase != tt.wantPhase {
+				t.Errorf("Get() = %+v, %v, want phase %s", got, ok, tt.wantPhase)
+			}
+		})
+	}
+}
+
+func TestManager_QueueFull(t *testing.T) {
+	m := NewManager(1, 1, time.Hour)
+	block := make(chan struct{})
+	defer close(block)
+	fn := func(string) *Output {
+		<-block
+		return &Output{Response: transport.ReturnSuccess()}
+	}
+	var err error
+	// one running, one pending, then the queue is full
+	for i := 0; i < 3 && err == nil; i++ {
+		_, err = m.Submit("cmd", "", fn, nil)
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err != ErrQueueFull {
+		t.Errorf("Submit() err = %v, want %v", err, ErrQueueFull)
+	}
+	if jobs := m.List(); len(jobs) != 2 {
+		t.Errorf("List() = %d jobs, want 2", len(jobs))
+	}
+}
+
+func TestManager_Retention(t *testing.T) {
+	m := NewManager(1, 1, time.Millisecond)
+	done := make(chan *Job, 1)
+	job, err := m.Submit("cmd", "", func(string) *Output {
+		return &Output{Response: transport.ReturnSuccess()}
+	}, func(job *Job) { done <- job })
+	if err != nil {
+		t.Fatalf("Submit() err: %v", err)
+	}
+	<-done
+	time.Sleep(10 * time.Millisecond)
+	m.List()
+	if _, ok := m.Get(job.Id); ok {
+		t.Errorf("Get() found the expired job")
+	}
+}
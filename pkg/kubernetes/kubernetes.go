@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
@@ -46,6 +47,8 @@ var (
 
 type Channel struct {
 	ClientSet *kubernetes.Clientset
+	// DynamicClient reads the custom resources, eg: the chaosblade resources of chaosblade-operator
+	DynamicClient dynamic.Interface
 }
 
 func GetInstance() *Channel {
@@ -63,6 +66,9 @@ func GetInstance() *Channel {
 			channel = &Channel{
 				ClientSet: clientset,
 			}
+			if channel.DynamicClient, err = NewDynamicClient(); err != nil {
+				logrus.Warningf("create k8s dynamic client err, %s", err.Error())
+			}
 		},
 	)
 	return channel
@@ -82,3 +88,12 @@ func NewK8sClient() (*kubernetes.Clientset, error) {
 	clientset, err := kubernetes.NewForConfig(clusterConfig)
 	return clientset, err
 }
+
+// NewDynamicClient creates the client of the custom resources by the in cluster config
+func NewDynamicClient() (dynamic.Interface, error) {
+	clusterConfig, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(clusterConfig)
+}
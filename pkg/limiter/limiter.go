@@ -0,0 +1,151 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrQueueFull    = errors.New("wait queue is full")
+	ErrQueueTimeout = errors.New("wait in queue timeout")
+)
+
+// Limiter bounds the concurrent work, the requests over the limit wait in a bounded queue
+type Limiter struct {
+	name         string
+	slots        chan struct{}
+	queueSize    int32
+	queueTimeout time.Duration
+
+	inflight int32
+	waiting  int32
+	rejected uint64
+	timeout  uint64
+}
+
+// Stat is the snapshot of a limiter
+type Stat struct {
+	Concurrency int    `json:"concurrency"`
+	QueueSize   int    `json:"queueSize"`
+	Inflight    int32  `json:"inflight"`
+	Waiting     int32  `json:"waiting"`
+	Rejected    uint64 `json:"rejected"`
+	Timeout     uint64 `json:"timeout"`
+}
+
+var (
+	limiters = make(map[string]*Limiter)
+	mutex    sync.RWMutex
+)
+
+// New creates and registers a limiter, concurrency less or equal than 0 means unlimited
+func New(name string, concurrency, queueSize int, queueTimeout time.Duration) *Limiter {
+	l := &Limiter{
+		name:         name,
+		queueSize:    int32(queueSize),
+		queueTimeout: queueTimeout,
+	}
+	if concurrency > 0 {
+		l.slots = make(chan struct{}, concurrency)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	limiters[name] = l
+	return l
+}
+
+// Get returns the registered limiter, nil if not found
+func Get(name string) *Limiter {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return limiters[name]
+}
+
+// Stats returns snapshots of all registered limiters
+func Stats() map[string]Stat {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	stats := make(map[string]Stat, len(limiters))
+	for name, l := range limiters {
+		stats[name] = l.Stat()
+	}
+	return stats
+}
+
+// Acquire takes a slot, Release must be called after the work if it returns nil
+func (l *Limiter) Acquire() error {
+	if l == nil {
+		return nil
+	}
+	if l.slots == nil {
+		atomic.AddInt32(&l.inflight, 1)
+		return nil
+	}
+
+	// fast path
+	select {
+	case l.slots <- struct{}{}:
+		atomic.AddInt32(&l.inflight, 1)
+		return nil
+	default:
+	}
+
+	if atomic.AddInt32(&l.waiting, 1) > l.queueSize {
+		atomic.AddInt32(&l.waiting, -1)
+		atomic.AddUint64(&l.rejected, 1)
+		return ErrQueueFull
+	}
+	defer atomic.AddInt32(&l.waiting, -1)
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		atomic.AddInt32(&l.inflight, 1)
+		return nil
+	case <-timer.C:
+		atomic.AddUint64(&l.timeout, 1)
+		return ErrQueueTimeout
+	}
+}
+
+// Release returns the slot
+func (l *Limiter) Release() {
+	if l == nil {
+		return
+	}
+	atomic.AddInt32(&l.inflight, -1)
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+func (l *Limiter) Stat() Stat {
+	return Stat{
+		Concurrency: cap(l.slots),
+		QueueSize:   int(l.queueSize),
+		Inflight:    atomic.LoadInt32(&l.inflight),
+		Waiting:     atomic.LoadInt32(&l.waiting),
+		Rejected:    atomic.LoadUint64(&l.rejected),
+		Timeout:     atomic.LoadUint64(&l.timeout),
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package limiter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	l := New("test", 1, 1, 100*time.Millisecond)
+	if err := l.Acquire(); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	waited := make(chan error)
+	go func() {
+		waited <- l.Acquire()
+	}()
+	// wait until the second request is queued
+	for l.Stat().Waiting != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := l.Acquire(); err != ErrQueueFull {
+		t.Errorf("Acquire() error = %v, want %v", err, ErrQueueFull)
+	}
+	if err := <-waited; err != ErrQueueTimeout {
+		t.Errorf("Acquire() error = %v, want %v", err, ErrQueueTimeout)
+	}
+
+	go func() {
+		waited <- l.Acquire()
+	}()
+	l.Release()
+	if err := <-waited; err != nil {
+		t.Errorf("Acquire() error = %v, want nil", err)
+	}
+	l.Release()
+
+	stat := Stats()["test"]
+	if stat.Inflight != 0 || stat.Rejected != 1 || stat.Timeout != 1 {
+		t.Errorf("Stats() = %+v", stat)
+	}
+}
+
+func TestUnlimited(t *testing.T) {
+	l := New("unlimited", 0, 0, time.Second)
+	for i := 0; i < 10; i++ {
+		if err := l.Acquire(); err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+	}
+	if got := l.Stat().Inflight; got != 10 {
+		t.Errorf("Stat().Inflight = %d, want 10", got)
+	}
+
+	var nilLimiter *Limiter
+	if err := nilLimiter.Acquire(); err != nil {
+		t.Errorf("nil Acquire() error = %v", err)
+	}
+	nilLimiter.Release()
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultRevertDuration is used if the duration of the runtime level is not specified
+	DefaultRevertDuration = 30 * time.Minute
+	// MaxRevertDuration prevents the debug level from being left on for days
+	MaxRevertDuration = 24 * time.Hour
+)
+
+// LevelState is the current level of the agent log
+type LevelState struct {
+	Level        string     `json:"level"`
+	DefaultLevel string     `json:"defaultLevel"`
+	RevertAt     *time.Time `json:"revertAt,omitempty"`
+}
+
+var (
+	levelMutex   sync.Mutex
+	defaultLevel = logrus.InfoLevel
+	revertTimer  *time.Timer
+	revertAt     time.Time
+)
+
+// SetLevel changes the log level at runtime, the level is reverted to the startup level after duration
+func SetLevel(level string, duration time.Duration) (*LevelState, error) {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	if duration <= 0 {
+		duration = DefaultRevertDuration
+	}
+	if duration > MaxRevertDuration {
+		duration = MaxRevertDuration
+	}
+
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	stopRevertTimer()
+	logrus.SetLevel(lvl)
+	if lvl != defaultLevel {
+		revertAt = time.Now().Add(duration)
+		revertTimer = time.AfterFunc(duration, revertLevel)
+	}
+	logrus.Warningf("[log] log level changed to %s, default level: %s", lvl, defaultLevel)
+	return levelState(), nil
+}
+
+// GetLevelState returns the current level and when it will be reverted
+func GetLevelState() *LevelState {
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	return levelState()
+}
+
+func revertLevel() {
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	revertTimer = nil
+	logrus.SetLevel(defaultLevel)
+	logrus.Warningf("[log] log level reverted to %s", defaultLevel)
+}
+
+// setDefaultLevel is called at startup, it cancels the runtime level
+func setDefaultLevel(level logrus.Level) {
+	levelMutex.Lock()
+	defer levelMutex.Unlock()
+	stopRevertTimer()
+	defaultLevel = level
+	logrus.SetLevel(level)
+}
+
+func stopRevertTimer() {
+	if revertTimer != nil {
+		revertTimer.Stop()
+		revertTimer = nil
+	}
+}
+
+func levelState() *LevelState {
+	state := &LevelState{
+		Level:        logrus.GetLevel().String(),
+		DefaultLevel: defaultLevel.String(),
+	}
+	if revertTimer != nil {
+		at := revertAt
+		state.RevertAt = &at
+	}
+	return state
+}
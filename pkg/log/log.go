@@ -31,10 +31,9 @@ const DebugLevel = "debug"
 func InitLog(cfg *options.LogConfig) {
 	level, err := logrus.ParseLevel(cfg.Level)
 	if err != nil {
-		logrus.SetLevel(logrus.InfoLevel)
-	} else {
-		logrus.SetLevel(level)
+		level = logrus.InfoLevel
 	}
+	setDefaultLevel(level)
 
 	switch cfg.LogOutput {
 	case options.LogFileOutput:
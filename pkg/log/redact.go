@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import "regexp"
+
+// sensitiveNames are the names of the access key, the signature and other secrets
+const sensitiveNames = `ak|sk|sn|sd|license|token|password|secret|authorization`
+
+// sensitivePattern matches the values of the secrets in the logged requests,
+// eg: map[ak:xxx sn:yyy], "sk":"xxx", token=xxx
+var sensitivePattern = regexp.MustCompile(`(?i)\b(` + sensitiveNames + `)(["']?\s*[:=]\s*["']?)([^\s"'&,\]}]+)`)
+
+// sensitiveKeyPattern matches the param names of the secrets
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)^(` + sensitiveNames + `)$`)
+
+// sensitiveFlagPattern matches the values of the secret flags in the command lines, eg: --password xxx
+var sensitiveFlagPattern = regexp.MustCompile(`(?i)(--[\w-]*(?:password|secret|token)[\w-]*)(\s+)([^\s"'-][^\s"']*)`)
+
+// credentialPattern matches the credentials of the authorization header, eg: Bearer xxx
+var credentialPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[^\s"']+`)
+
+// Redact masks the secrets in the log content, so that it's safe to be collected
+func Redact(content []byte) []byte {
+	content = credentialPattern.ReplaceAll(content, []byte("${1} ******"))
+	content = sensitiveFlagPattern.ReplaceAll(content, []byte("${1}${2}******"))
+	return sensitivePattern.ReplaceAll(content, []byte("${1}${2}******"))
+}
+
+// RedactParams returns a copy of the request params with the secrets masked, the values of the
+// sensitive params are masked as a whole
+func RedactParams(params map[string]string) map[string]string {
+	if params == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(params))
+	for key, value := range params {
+		if sensitiveKeyPattern.MatchString(key) {
+			redacted[key] = "******"
+			continue
+		}
+		redacted[key] = string(Redact([]byte(value)))
+	}
+	return redacted
+}
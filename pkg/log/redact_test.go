@@ -0,0 +1,63 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{line: "request: &{Headers:map[ak:abc sn:def rid:1]}", want: "request: &{Headers:map[ak:****** sn:****** rid:1]}"},
+		{line: `{"ak":"abc","sk":"def","cid":"c1"}`, want: `{"ak":"******","sk":"******","cid":"c1"}`},
+		{line: "url?token=abc&rid=1", want: "url?token=******&rid=1"},
+		{line: "Authorization: Bearer xyz", want: "Authorization: ****** ******"},
+		{line: "kafka sink task", want: "kafka sink task"},
+		{line: "create mysql --db-password abc --port 3306", want: "create mysql --db-password ****** --port 3306"},
+		{line: "create mysql --password --port 3306", want: "create mysql --password --port 3306"},
+	}
+	for _, tt := range tests {
+		if got := string(Redact([]byte(tt.line))); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestRedactParams(t *testing.T) {
+	params := map[string]string{
+		"cmd":      "create network loss --password abc",
+		"flags":    `{"token":"abc","percent":"50"}`,
+		"password": "my secret",
+		"target":   "network",
+	}
+	want := map[string]string{
+		"cmd":      "create network loss --password ******",
+		"flags":    `{"token":"******","percent":"50"}`,
+		"password": "******",
+		"target":   "network",
+	}
+	if got := RedactParams(params); !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactParams() = %v, want %v", got, want)
+	}
+	if params["password"] != "my secret" {
+		t.Errorf("RedactParams() modified the params")
+	}
+}
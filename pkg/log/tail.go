@@ -0,0 +1,141 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// RidField is the log field of the request id, so the entries of one request can be filtered
+	RidField = "rid"
+	// DefaultTailSize is the default bytes read by one tail
+	DefaultTailSize = 1 << 20
+)
+
+// TailResult contains the matched lines, Offset is where the next tail should start to follow the log
+type TailResult struct {
+	Lines   []string `json:"lines"`
+	Offset  int64    `json:"offset"`
+	Rotated bool     `json:"rotated"`
+}
+
+// WithRid returns the log entry with the request id field
+func WithRid(rid string) *logrus.Entry {
+	return logrus.WithField(RidField, rid)
+}
+
+// RidMatcher matches the lines which are logged with the request id
+func RidMatcher(rid string) func(line string) bool {
+	plain := fmt.Sprintf("%s=%s", RidField, rid)
+	quoted := fmt.Sprintf("%s=%q", RidField, rid)
+	return func(line string) bool {
+		return containsField(line, plain) || strings.Contains(line, quoted)
+	}
+}
+
+// containsField checks the field is followed by a separator, so rid=1 does not match rid=10
+func containsField(line, field string) bool {
+	for start := 0; ; {
+		index := strings.Index(line[start:], field)
+		if index < 0 {
+			return false
+		}
+		end := start + index + len(field)
+		if end == len(line) || line[end] == ' ' {
+			return true
+		}
+		start = end
+	}
+}
+
+// Tail reads the complete lines of the file from offset, reads at most size bytes.
+// The last size bytes are read if offset is negative, and the file is read from the beginning
+// if offset is beyond the file size, which means the file has been rotated.
+func Tail(fileName string, offset, size int64, match func(line string) bool) (*TailResult, error) {
+	if size <= 0 {
+		size = DefaultTailSize
+	}
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	result := &TailResult{Lines: make([]string, 0)}
+	skipPartial := false
+	switch {
+	case offset < 0:
+		offset = info.Size() - size
+		if offset < 0 {
+			offset = 0
+		}
+		skipPartial = offset > 0 && !atLineStart(file, offset)
+	case offset > info.Size():
+		offset = 0
+		result.Rotated = true
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(io.LimitReader(file, size))
+	if err != nil {
+		return nil, err
+	}
+	// keep the incomplete last line for the next tail
+	end := bytes.LastIndexByte(content, '\n') + 1
+	if end == 0 && int64(len(content)) == size {
+		// the line is longer than size, split it, or the offset never advances
+		end = len(content)
+	}
+	content = content[:end]
+	result.Offset = offset + int64(end)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if skipPartial {
+			// the first line may be cut in the middle
+			skipPartial = false
+			continue
+		}
+		if match == nil || match(line) {
+			result.Lines = append(result.Lines, line)
+		}
+	}
+	return result, scanner.Err()
+}
+
+func atLineStart(file *os.File, offset int64) bool {
+	previous := make([]byte, 1)
+	if _, err := file.ReadAt(previous, offset-1); err != nil {
+		return false
+	}
+	return previous[0] == '\n'
+}
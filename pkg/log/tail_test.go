@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRidMatcher(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{`time="now" level=info msg="exec" rid=abc`, true},
+		{`time="now" level=info msg="exec" rid=abc uid=1`, true},
+		{`time="now" level=info msg="exec" rid="abc"`, true},
+		{`time="now" level=info msg="exec" rid=abcd`, false},
+		{`time="now" level=info msg="exec"`, false},
+	}
+	match := RidMatcher("abc")
+	for _, tt := range tests {
+		if got := match(tt.line); got != tt.want {
+			t.Errorf("RidMatcher(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestTail(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "agent.log")
+	content := "line1 rid=a\nline2 rid=b\nline3 rid=a\npartial"
+	if err := os.WriteFile(fileName, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		offset      int64
+		size        int64
+		match       func(string) bool
+		wantLines   []string
+		wantOffset  int64
+		wantRotated bool
+	}{
+		{"from beginning", 0, 0, nil, []string{"line1 rid=a", "line2 rid=b", "line3 rid=a"}, 36, false},
+		{"filter by rid", 0, 0, RidMatcher("a"), []string{"line1 rid=a", "line3 rid=a"}, 36, false},
+		{"follow", 12, 0, nil, []string{"line2 rid=b", "line3 rid=a"}, 36, false},
+		{"tail cut line", -1, 20, nil, []string{"line3 rid=a"}, 36, false},
+		{"tail at line start", -1, 31, nil, []string{"line2 rid=b", "line3 rid=a"}, 36, false},
+		{"rotated", 100, 0, nil, []string{"line1 rid=a", "line2 rid=b", "line3 rid=a"}, 36, true},
+		{"long line", 0, 8, nil, []string{"line1 ri"}, 8, false},
+		{"long line rest", 8, 8, nil, []string{"d=a"}, 12, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Tail(fileName, tt.offset, tt.size, tt.match)
+			if err != nil {
+				t.Fatalf("Tail() err: %v", err)
+			}
+			if !reflect.DeepEqual(result.Lines, tt.wantLines) {
+				t.Errorf("Tail() lines = %v, want %v", result.Lines, tt.wantLines)
+			}
+			if result.Offset != tt.wantOffset || result.Rotated != tt.wantRotated {
+				t.Errorf("Tail() offset = %d, rotated = %v, want %d, %v", result.Offset, result.Rotated, tt.wantOffset, tt.wantRotated)
+			}
+		})
+	}
+}
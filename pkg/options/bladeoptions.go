@@ -24,10 +24,11 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
-	"github.com/chaosblade-io/chaos-agent/pkg/bash"
+	"github.com/chaosblade-io/chaos-agent/pkg/executor"
 	"github.com/chaosblade-io/chaos-agent/pkg/tools"
 )
 
@@ -118,16 +119,21 @@ func parseVersionFromOutput(output string) (string, error) {
 
 // GetChaosBladeVersion
 func GetChaosBladeVersion() (string, error) {
-	if !tools.IsExist(BladeBinPath) {
+	return GetBladeVersionOf(BladeBinPath)
+}
+
+// GetBladeVersionOf returns the version of the blade binary
+func GetBladeVersionOf(bladeBin string) (string, error) {
+	if !tools.IsExist(bladeBin) {
 		return "", errors.New("blade bin file not exist")
 	}
 
-	result, errMsg, isSuccess := bash.ExecScript(context.TODO(), BladeBinPath, "version")
-	if !isSuccess {
-		return "", errors.New(errMsg)
+	result := executor.Run(context.TODO(), bladeBin, []string{"version"}, executor.Options{Timeout: 10 * time.Second})
+	if !result.Success() {
+		return "", errors.New(result.Error())
 	}
 
-	version, err := parseVersionFromOutput(result)
+	version, err := parseVersionFromOutput(result.Stdout)
 	if err != nil {
 		return "", err
 	}
@@ -67,11 +67,19 @@ const (
 	StartUpgradeMode = "upgrade"
 )
 
+const DefaultAdminSocket = "/var/run/chaos.sock"
+
+// sensitiveFlags are masked in the sanitized configuration
+var sensitiveFlags = map[string]bool{
+	"license": true,
+}
+
 var Opts *Options
 
 type Options struct {
-	LogConfig LogConfig
-	Help      bool
+	LogConfig   LogConfig
+	AuditConfig AuditConfig
+	Help        bool
 
 	Environment     string
 	IsVpc           bool
@@ -99,6 +107,24 @@ type Options struct {
 	// transport config
 	TransportConfig TransportConfig
 
+	// concurrency config of handlers
+	ConcurrencyConfig ConcurrencyConfig
+
+	// async job config
+	JobConfig JobConfig
+
+	// maximum duration of experiments
+	TTLConfig TTLConfig
+
+	// maximum concurrent experiments
+	BlastRadiusConfig BlastRadiusConfig
+
+	// results of the idempotency keys
+	IdempotencyConfig IdempotencyConfig
+
+	// agent self upgrade
+	UpgradeConfig UpgradeConfig
+
 	// application
 	ApplicationInstance string
 	ApplicationGroup    string
@@ -121,12 +147,54 @@ type Options struct {
 	ChaosAgentBinUrl string
 	ChaosAgentSHUrl  string
 	ChaosBladeTarUrl string
-	LitmusChartUrl   string
-	CertUrl          string
+	// sha256 checksum of the chaosblade tar package, required by the installation
+	ChaosBladeTarSha256 string
+	// ChaosBladeTarInsecure allows installing the chaosblade tar package of blade.tar.url without checksum
+	ChaosBladeTarInsecure bool
+	// base64 ed25519 public key file, the chaosblade tar package must be signed by it if set
+	ChaosBladeVerifyKey string
+	// ChaosBladeDownloadTimeout is the deadline of downloading the chaosblade tar package
+	ChaosBladeDownloadTimeout time.Duration
+	LitmusChartUrl            string
+	CertUrl                   string
+
+	// LitmusCatalogDir keeps the litmus experiment and rbac yaml, default is litmus in the agent directory
+	LitmusCatalogDir string
+	// LitmusCatalogImportDir is where the local bundles are imported from, local import is disabled if empty
+	LitmusCatalogImportDir string
+	// LitmusHubUrl is where the missing litmus yaml is fetched from, never fetched if empty
+	LitmusHubUrl string
 
 	// agent ip
 	LocalIp string
 
+	// unix socket of the local admin server
+	AdminSocket string
+
+	// safety policy file of the blade commands, not restricted if empty
+	PolicyFile string
+
+	// ScheduleFile keeps the local experiment schedules, default is schedules.json in the agent directory
+	ScheduleFile string
+
+	// RegistryFile keeps the running experiments, default is experiments.json in the agent directory
+	RegistryFile string
+
+	// BladeTimeout is the maximum execution time of one blade command
+	BladeTimeout time.Duration
+
+	// StatusDeadlines overrides how long the status of each experiment type is waited, eg: k8s=30s
+	StatusDeadlines map[string]string
+
+	// ReconcileInterval is how often the experiment status is reconciled with blade, 0 means disabled
+	ReconcileInterval time.Duration
+
+	// CapabilityInterval is how often the host capabilities are discovered again, 0 means only at startup
+	CapabilityInterval time.Duration
+
+	// ChaosbladeLegacyCmd accepts the cmd param of chaosblade requests, which is executed by shell
+	ChaosbladeLegacyCmd bool
+
 	Flags *pflag.FlagSet
 }
 
@@ -145,6 +213,72 @@ type LogConfig struct {
 	LogOutput string
 }
 
+type AuditConfig struct {
+	// audit file path, default is audit.log in the agent directory
+	FileName string
+	// maximum audit file size
+	MaxFileSize int
+	// maximum audit file count
+	MaxFileCount int
+}
+
+type ConcurrencyConfig struct {
+	// HandlerLimits is the maximum concurrent requests of each handler, unlimited if absent
+	HandlerLimits map[string]int
+	// QueueSize is the maximum waiting requests of each handler
+	QueueSize int
+	// QueueTimeout is the maximum time a request waits in queue
+	QueueTimeout time.Duration
+	// MaxBladeProcess is the maximum in-flight blade processes of the agent
+	MaxBladeProcess int
+}
+
+type JobConfig struct {
+	// Workers is the number of goroutines which run the async jobs
+	Workers int
+	// QueueSize is the maximum pending jobs
+	QueueSize int
+	// Retention is how long the finished jobs are kept
+	Retention time.Duration
+}
+
+type UpgradeConfig struct {
+	// VerifyKey is the base64 ed25519 public key file which the agent binary must be signed by,
+	// the upgrade is refused if empty
+	VerifyKey string
+	// SafePointTimeout is how long to wait for the in-flight experiment operations
+	SafePointTimeout time.Duration
+	// HealthTimeout is how long the new agent has to become healthy before rollback
+	HealthTimeout time.Duration
+}
+
+type TTLConfig struct {
+	// Default is the ttl of the experiments without timeout, 0 means no limit
+	Default time.Duration
+	// Max is the ceiling of the ttl, 0 means no ceiling
+	Max time.Duration
+	// Interval is how often the watchdog checks the expired experiments
+	Interval time.Duration
+}
+
+type BlastRadiusConfig struct {
+	// Host is the maximum concurrent experiments on the host, 0 means no limit
+	Host int
+	// Targets is the maximum concurrent experiments of each target type, eg: network=2,pod-network=1
+	Targets map[string]int
+	// Namespace is the maximum concurrent experiments in one k8s namespace, 0 means no limit
+	Namespace int
+	// Workload is the maximum concurrent experiments on the same k8s workload, 0 means no limit
+	Workload int
+}
+
+type IdempotencyConfig struct {
+	// File keeps the results of the idempotency keys, default is idempotency.json in the agent directory
+	File string
+	// Retention is how long the result of a key is kept
+	Retention time.Duration
+}
+
 type TransportConfig struct {
 	Environment string
 	// Endpoint is server address with port
@@ -184,6 +318,10 @@ func (o *Options) AddFlags() {
 	o.Flags.IntVar(&o.LogConfig.MaxFileCount, "log.count", 1, "log file count, default value is 1")
 	o.Flags.StringVar(&o.LogConfig.LogOutput, "log.output", LogFileOutput, "log output, file|stdout")
 
+	o.Flags.StringVar(&o.AuditConfig.FileName, "audit.file", "", "audit file path, default is audit.log in the agent directory")
+	o.Flags.IntVar(&o.AuditConfig.MaxFileSize, "audit.size", 50, "audit file size, unit: m")
+	o.Flags.IntVar(&o.AuditConfig.MaxFileCount, "audit.count", 10, "audit file count, default value is 10")
+
 	o.Flags.StringVar(&o.Environment, "environment", "prod", "environment: prod|pre|test|dev")
 	o.Flags.StringVar(&o.Namespace, "namespace", "default", "namespace where the host is located")
 	o.Flags.StringVar(&o.License, "license", "", "license")
@@ -196,6 +334,34 @@ func (o *Options) AddFlags() {
 	o.Flags.DurationVar(&o.TransportConfig.Timeout, "transport.timeout", 3*time.Second, "connect timeout with server")
 	o.Flags.BoolVar(&o.TransportConfig.Secure, "transport.secure", true, "transport in secure or not, default value is true")
 
+	o.Flags.StringToIntVar(&o.ConcurrencyConfig.HandlerLimits, "handler.concurrency", map[string]int{"chaosblade": 8, "litmuschaos": 4},
+		"the maximum concurrent requests of handlers, eg: chaosblade=8,litmuschaos=4")
+	o.Flags.IntVar(&o.ConcurrencyConfig.QueueSize, "handler.queue.size", 32, "the maximum waiting requests of each handler")
+	o.Flags.DurationVar(&o.ConcurrencyConfig.QueueTimeout, "handler.queue.timeout", 30*time.Second, "the maximum time a request waits in queue")
+	o.Flags.IntVar(&o.ConcurrencyConfig.MaxBladeProcess, "blade.max.process", 16, "the maximum in-flight blade processes")
+
+	o.Flags.DurationVar(&o.TTLConfig.Default, "experiment.ttl.default", 0, "the ttl of the experiments without timeout, 0 means no limit")
+	o.Flags.DurationVar(&o.TTLConfig.Max, "experiment.ttl.max", 24*time.Hour, "the maximum ttl of the experiments, 0 means no ceiling")
+	o.Flags.DurationVar(&o.TTLConfig.Interval, "experiment.ttl.interval", 10*time.Second, "how often the expired experiments are checked")
+	o.Flags.StringVar(&o.RegistryFile, "experiment.registry.file", "", "the file of the running experiments, default is experiments.json in the agent directory")
+
+	o.Flags.IntVar(&o.BlastRadiusConfig.Host, "radius.host", 0, "the maximum concurrent experiments on the host, 0 means no limit")
+	o.Flags.StringToIntVar(&o.BlastRadiusConfig.Targets, "radius.target", map[string]int{},
+		"the maximum concurrent experiments of each target type, eg: network=2,pod-network=1")
+	o.Flags.IntVar(&o.BlastRadiusConfig.Namespace, "radius.namespace", 0, "the maximum concurrent experiments in one k8s namespace, 0 means no limit")
+	o.Flags.IntVar(&o.BlastRadiusConfig.Workload, "radius.workload", 0, "the maximum concurrent experiments on the same k8s workload, 0 means no limit")
+
+	o.Flags.StringVar(&o.UpgradeConfig.VerifyKey, "agent.verify.key", "", "the ed25519 public key file which the agent binary must be signed by, upgrade is refused if empty")
+	o.Flags.DurationVar(&o.UpgradeConfig.SafePointTimeout, "upgrade.safepoint.timeout", 5*time.Minute, "how long to wait for the in-flight experiment operations before upgrade")
+	o.Flags.DurationVar(&o.UpgradeConfig.HealthTimeout, "upgrade.health.timeout", 2*time.Minute, "how long the new agent has to become healthy before rollback")
+
+	o.Flags.StringVar(&o.IdempotencyConfig.File, "idempotency.file", "", "the file of the idempotency key results, default is idempotency.json in the agent directory")
+	o.Flags.DurationVar(&o.IdempotencyConfig.Retention, "idempotency.retention", 24*time.Hour, "how long the result of an idempotency key is kept")
+
+	o.Flags.IntVar(&o.JobConfig.Workers, "job.workers", 8, "the number of workers which run the async jobs")
+	o.Flags.IntVar(&o.JobConfig.QueueSize, "job.queue.size", 128, "the maximum pending async jobs")
+	o.Flags.DurationVar(&o.JobConfig.Retention, "job.retention", time.Hour, "how long the finished async jobs are kept")
+
 	o.Flags.StringVar(&o.ApplicationInstance, AppInstanceKeyName, DefaultApplicationInstance, "application instance name")
 	o.Flags.StringVar(&o.ApplicationGroup, AppGroupKeyName, DefaultApplicationGroup, "application group name")
 	o.Flags.StringVar(&o.StartupMode, "startup.mode", StartConsoleMode, "startup mode")
@@ -209,12 +375,30 @@ func (o *Options) AddFlags() {
 	o.Flags.StringVar(&o.ChaosAgentBinUrl, "agent.bin.url", "", "the download url of chaos-agent binary")
 	o.Flags.StringVar(&o.ChaosAgentSHUrl, "agent.sh.url", "", "the download url of chaos-agent start shell")
 	o.Flags.StringVar(&o.ChaosBladeTarUrl, "blade.tar.url", "", "the download url of chaosblade tar package")
+	o.Flags.StringVar(&o.ChaosBladeTarSha256, "blade.tar.sha256", "", "the sha256 checksum of chaosblade tar package")
+	o.Flags.BoolVar(&o.ChaosBladeTarInsecure, "blade.tar.insecure", false, "install the chaosblade tar package of blade.tar.url without the sha256 checksum, insecure")
+	o.Flags.StringVar(&o.ChaosBladeVerifyKey, "blade.verify.key", "", "the ed25519 public key file which the chaosblade tar package must be signed by")
+	o.Flags.DurationVar(&o.ChaosBladeDownloadTimeout, "blade.download.timeout", 10*time.Minute, "the deadline of downloading the chaosblade tar package")
 	o.Flags.StringVar(&o.LitmusChartUrl, "litmus.chart.url", "", "the chart repositories of litmusChaos")
+	o.Flags.StringVar(&o.LitmusCatalogDir, "litmus.catalog.dir", "", "the local catalog of litmus experiments, default is litmus in the agent directory")
+	o.Flags.StringVar(&o.LitmusCatalogImportDir, "litmus.catalog.import.dir", "",
+		"the directory of the litmus bundles which can be imported by the file name, empty to import by url only")
+	o.Flags.StringVar(&o.LitmusHubUrl, "litmus.hub.url", "https://hub.litmuschaos.io/api/chaos",
+		"where the litmus experiments missing in the catalog are fetched from, empty for the air-gapped clusters")
 	o.Flags.StringVar(&o.CertUrl, "cert.url", "", "the download url of cert")
 
 	o.Flags.StringVar(&o.Port, "port", "19527", "the agent server port")
 
 	o.Flags.StringVar(&o.LocalIp, "localIp", "", "specify the agent IP address (useful when host has multiple IPs)")
+	o.Flags.StringVar(&o.AdminSocket, "admin.socket", DefaultAdminSocket, "the unix socket of the local admin server")
+	o.Flags.DurationVar(&o.BladeTimeout, "blade.timeout", 60*time.Second, "the maximum execution time of one blade command")
+	o.Flags.StringToStringVar(&o.StatusDeadlines, "status.deadline", map[string]string{},
+		"how long the status of each experiment type is waited, eg: create=1m,prepare=1m,jvm=1m,cplus=2m,k8s=10s,litmus=2m")
+	o.Flags.DurationVar(&o.ReconcileInterval, "reconcile.interval", time.Minute, "how often the experiment status is reconciled with blade, 0 means disabled")
+	o.Flags.DurationVar(&o.CapabilityInterval, "capability.interval", 10*time.Minute, "how often the host capabilities are discovered again, 0 means only at startup")
+	o.Flags.BoolVar(&o.ChaosbladeLegacyCmd, "chaosblade.legacy.cmd", false, "accept the legacy cmd param of chaosblade requests, which is executed by shell")
+	o.Flags.StringVar(&o.PolicyFile, "policy.file", "", "the safety policy file of the blade commands, not restricted if empty")
+	o.Flags.StringVar(&o.ScheduleFile, "schedule.file", "", "the local experiment schedule file, default is schedules.json in the agent directory")
 
 	o.Flags.BoolVarP(&o.Help, "help", "h", false, "Print Help text")
 }
@@ -244,7 +428,10 @@ func (o *Options) SetOthersByFlags() {
 	o.InitApplicationInfo(o.ApplicationInstance, o.ApplicationGroup)
 
 	var err error
-	if o.ChaosbladeVersion, err = GetChaosBladeVersion(); err != nil {
+	if !tools.IsExist(BladeBinPath) {
+		// chaosblade can be installed later by the installBlade request
+		logrus.Warningf("%s not found, chaosblade experiments are unavailable until it is installed", BladeBinPath)
+	} else if o.ChaosbladeVersion, err = GetChaosBladeVersion(); err != nil {
 		logrus.Errorf("Get chaosblade version failed, err: %s", err.Error())
 		os.Exit(1)
 	}
@@ -272,6 +459,19 @@ func (o *Options) InitApplicationInfo(appInstance string, appGroup string) {
 	}
 }
 
+// SanitizedConfig returns the effective flag values, the sensitive values are masked
+func (o *Options) SanitizedConfig() map[string]string {
+	config := make(map[string]string)
+	o.Flags.VisitAll(func(flag *pflag.Flag) {
+		value := flag.Value.String()
+		if sensitiveFlags[flag.Name] && value != "" {
+			value = "******"
+		}
+		config[flag.Name] = value
+	})
+	return config
+}
+
 func (o *Options) SetUid(uid string) {
 	o.Uid = uid
 }
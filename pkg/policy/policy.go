@@ -0,0 +1,383 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+const (
+	CreateOperation  = "create"
+	PrepareOperation = "prepare"
+	DestroyOperation = "destroy"
+	RevokeOperation  = "revoke"
+
+	// TimeoutFlag is the blade flag of the experiment duration, unit: second
+	TimeoutFlag = "timeout"
+)
+
+// processFlags are the blade flags which select processes
+var processFlags = []string{"process", "process-cmd"}
+
+// Policy is the operator-owned safety policy, it's evaluated before the blade commands
+//
+//	maxDuration: 1h
+//	protectedProcesses: [sshd, kubelet, chaos_agent]
+//	rules:
+//	  - name: disk-fill-limit
+//	    target: disk
+//	    action: fill
+//	    flags:
+//	      percent: ">80"
+//	  - name: kube-system
+//	    target: k8s
+//	    flags:
+//	      namespace: kube-system
+type Policy struct {
+	// MaxDuration requires the created experiments have a timeout flag not greater than it
+	MaxDuration Duration `json:"maxDuration,omitempty"`
+	// ProtectedProcesses cannot be selected by the process flags
+	ProtectedProcesses []string `json:"protectedProcesses,omitempty"`
+	// Rules deny the matched commands
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// Rule denies the command if all of the conditions are matched, the empty condition matches any
+type Rule struct {
+	Name string `json:"name"`
+	// Operations default is create
+	Operations []string `json:"operations,omitempty"`
+	// Target and Action are glob patterns, eg: network, pod-*
+	Target string `json:"target,omitempty"`
+	Action string `json:"action,omitempty"`
+	// Flags is the flag name to condition, the condition is a glob pattern or a number comparison: >80, >=80, <10, <=10
+	Flags map[string]string `json:"flags,omitempty"`
+}
+
+// Duration is a time.Duration which is written as 30m, 1h in the policy file
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	value, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("duration must be a string, eg: 30m")
+	}
+	d.Duration, err = time.ParseDuration(value)
+	return err
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// Command is the parsed blade command line, eg: create network loss --percent 50 --interface eth0
+type Command struct {
+	Operation string
+	// Subjects are the positional arguments after the operation, eg: [network loss], [k8s pod-network loss]
+	Subjects []string
+	Flags    map[string]string
+}
+
+// Target returns the first subject
+func (c *Command) Target() string {
+	if len(c.Subjects) == 0 {
+		return ""
+	}
+	return c.Subjects[0]
+}
+
+// Action returns the last subject
+func (c *Command) Action() string {
+	if len(c.Subjects) < 2 {
+		return ""
+	}
+	return c.Subjects[len(c.Subjects)-1]
+}
+
+// ParseCommand parses the blade arguments, the flag without value is treated as "true".
+// The operation aliases are normalized, eg: c is create.
+func ParseCommand(args []string) *Command {
+	command := &Command{Flags: make(map[string]string)}
+	if len(args) == 0 {
+		return command
+	}
+	command.Operation = normalizeOperation(args[0])
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if !isFlag(arg) {
+			command.Subjects = append(command.Subjects, arg)
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		if index := strings.Index(name, "="); index >= 0 {
+			command.Flags[name[:index]] = name[index+1:]
+			continue
+		}
+		if i+1 < len(args) && !isFlag(args[i+1]) {
+			command.Flags[name] = args[i+1]
+			i++
+			continue
+		}
+		command.Flags[name] = "true"
+	}
+	return command
+}
+
+func normalizeOperation(operation string) string {
+	switch {
+	case options.CreateOperation[operation]:
+		return CreateOperation
+	case options.PrepareOperation[operation]:
+		return PrepareOperation
+	case options.DestroyOperation[operation]:
+		return DestroyOperation
+	case options.RevokeOperation[operation]:
+		return RevokeOperation
+	}
+	return operation
+}
+
+// isFlag returns true for --name and the short flag -n, so the values starting with -, eg: -1,
+// -Xmx512m, are not treated as flags
+func isFlag(arg string) bool {
+	if strings.HasPrefix(arg, "--") {
+		return len(arg) > 2
+	}
+	return len(arg) == 2 && arg[0] == '-' && unicode.IsLetter(rune(arg[1]))
+}
+
+// Evaluate returns the reason if the command is denied, or empty string if allowed
+func (p *Policy) Evaluate(command *Command) string {
+	if reason := p.checkProcesses(command); reason != "" {
+		return reason
+	}
+	if reason := p.checkDuration(command); reason != "" {
+		return reason
+	}
+	for _, rule := range p.Rules {
+		if rule.match(command) {
+			return fmt.Sprintf("rule %s", rule.Name)
+		}
+	}
+	return ""
+}
+
+func (p *Policy) checkProcesses(command *Command) string {
+	if command.Operation != CreateOperation && command.Operation != PrepareOperation {
+		return ""
+	}
+	for _, flag := range processFlags {
+		for _, process := range tools.SplitValues(command.Flags[flag]) {
+			if contains(p.ProtectedProcesses, process) {
+				return fmt.Sprintf("process %s is protected", process)
+			}
+		}
+	}
+	return ""
+}
+
+func (p *Policy) checkDuration(command *Command) string {
+	if command.Operation != CreateOperation || p.MaxDuration.Duration <= 0 {
+		return ""
+	}
+	value, ok := command.Flags[TimeoutFlag]
+	if !ok {
+		return fmt.Sprintf("--%s is required, maximum is %s", TimeoutFlag, p.MaxDuration.Duration)
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		return fmt.Sprintf("--%s %s is illegal", TimeoutFlag, value)
+	}
+	if time.Duration(seconds)*time.Second > p.MaxDuration.Duration {
+		return fmt.Sprintf("--%s %s exceeds the maximum %s", TimeoutFlag, value, p.MaxDuration.Duration)
+	}
+	return ""
+}
+
+func (r *Rule) match(command *Command) bool {
+	operations := r.Operations
+	if len(operations) == 0 {
+		operations = []string{CreateOperation}
+	}
+	if !contains(operations, command.Operation) {
+		return false
+	}
+	if !globMatch(r.Target, command.Target()) || !globMatch(r.Action, command.Action()) {
+		return false
+	}
+	for name, condition := range r.Flags {
+		value, ok := command.Flags[name]
+		if !ok || !matchCondition(condition, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchCondition returns true if any of the comma separated values matches
+func matchCondition(condition, value string) bool {
+	for _, v := range tools.SplitValues(value) {
+		if compare, ok := matchComparison(condition, v); ok {
+			if compare {
+				return true
+			}
+			continue
+		}
+		if globMatch(condition, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchComparison returns false as the second value if the condition is not a comparison
+func matchComparison(condition, value string) (bool, bool) {
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if !strings.HasPrefix(condition, op) {
+			continue
+		}
+		limit, err := strconv.ParseFloat(strings.TrimSpace(condition[len(op):]), 64)
+		if err != nil {
+			return false, false
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false, true
+		}
+		switch op {
+		case ">=":
+			return number >= limit, true
+		case "<=":
+			return number <= limit, true
+		case ">":
+			return number > limit, true
+		default:
+			return number < limit, true
+		}
+	}
+	return false, false
+}
+
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	mutex    sync.Mutex
+	fileName string
+	modTime  time.Time
+	current  *Policy
+)
+
+// Init sets the policy file, the commands are not restricted if the file name is empty
+func Init(policyFile string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	fileName = policyFile
+	current = nil
+	modTime = time.Time{}
+	if fileName == "" {
+		return nil
+	}
+	_, err := loadLocked()
+	return err
+}
+
+// Check evaluates the blade arguments with the policy, the policy file is reloaded if it's modified.
+// It returns the reason if denied. The command is denied if the policy file cannot be loaded.
+func Check(args []string) string {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if fileName == "" {
+		return ""
+	}
+	p, err := loadLocked()
+	if err != nil {
+		logrus.Warningf("[policy] load policy file %s failed, err: %v", fileName, err)
+		return fmt.Sprintf("policy file is invalid, %s", err.Error())
+	}
+	return p.Evaluate(ParseCommand(args))
+}
+
+// shellMetacharacters chain, substitute or redirect the commands in a shell command line
+var shellMetacharacters = []string{";", "&", "|", "`", "$(", ">", "<", "\n"}
+
+// CheckShell returns the reason if the command line executed by shell contains shell metacharacters
+// while a policy is enabled, the policy only sees the words of the line and could be bypassed.
+func CheckShell(line string) string {
+	mutex.Lock()
+	enabled := fileName != ""
+	mutex.Unlock()
+	if !enabled {
+		return ""
+	}
+	for _, metacharacter := range shellMetacharacters {
+		if strings.Contains(line, metacharacter) {
+			return fmt.Sprintf("shell metacharacter %q is not allowed in the legacy cmd under the policy", metacharacter)
+		}
+	}
+	return ""
+}
+
+func loadLocked() (*Policy, error) {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && info.ModTime().Equal(modTime) {
+		return current, nil
+	}
+	bytes, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := yaml.UnmarshalStrict(bytes, &p); err != nil {
+		return nil, err
+	}
+	current, modTime = &p, info.ModTime()
+	logrus.Infof("[policy] policy file %s loaded, rules: %d", fileName, len(p.Rules))
+	return current, nil
+}
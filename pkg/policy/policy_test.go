@@ -0,0 +1,171 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPolicy = `
+maxDuration: 1h
+protectedProcesses: [sshd, kubelet]
+rules:
+  - name: disk-fill-limit
+    target: disk
+    action: fill
+    flags:
+      percent: ">80"
+  - name: management-interface
+    target: network
+    flags:
+      interface: eth0
+  - name: kube-system
+    operations: [create, prepare]
+    target: k8s
+    flags:
+      namespace: kube-system
+`
+
+func TestPolicy_Evaluate(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(fileName, []byte(testPolicy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Init(fileName); err != nil {
+		t.Fatalf("Init() err: %v", err)
+	}
+	defer Init("")
+
+	tests := []struct {
+		cmd  string
+		deny string
+	}{
+		{"create disk fill --percent 70 --timeout 60", ""},
+		{"create disk fill --percent 90 --timeout 60", "rule disk-fill-limit"},
+		{"create disk fill --percent=81 --timeout=60", "rule disk-fill-limit"},
+		{"create network loss --interface eth0 --percent 50 --timeout 60", "rule management-interface"},
+		{"create network loss --interface eth1 --percent 50 --timeout 60", ""},
+		{"create k8s pod-network loss --namespace kube-system --timeout 60", "rule kube-system"},
+		{"create k8s pod-network loss --namespace default,kube-system --timeout 60", "rule kube-system"},
+		{"create k8s pod-network loss --namespace default --timeout 60", ""},
+		{"create cpu fullload --timeout 7200", "exceeds the maximum"},
+		{"create cpu fullload", "is required"},
+		{"create process kill --process sshd --timeout 60", "process sshd is protected"},
+		{"create process stop --process-cmd java,kubelet --timeout 60", "process kubelet is protected"},
+		{"c disk fill --percent 90 --timeout 60", "rule disk-fill-limit"},
+		{"c cpu fullload", "is required"},
+		{"c process kill --process sshd --timeout 60", "process sshd is protected"},
+		{"p k8s pod-network --namespace kube-system", "rule kube-system"},
+		{"p jvm --process sshd", "process sshd is protected"},
+		{"destroy 7c1f7afc281482c8", ""},
+		{"d 7c1f7afc281482c8", ""},
+		{"status --type create", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			got := Check(strings.Fields(tt.cmd))
+			if (tt.deny == "") != (got == "") || !strings.Contains(got, tt.deny) {
+				t.Errorf("Check() = %q, want %q", got, tt.deny)
+			}
+		})
+	}
+}
+
+func TestCheck_Reload(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(fileName, []byte("rules: []"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Init(fileName); err != nil {
+		t.Fatalf("Init() err: %v", err)
+	}
+	defer Init("")
+	args := strings.Fields("create cpu fullload")
+	if got := Check(args); got != "" {
+		t.Fatalf("Check() = %q, want allowed", got)
+	}
+
+	if err := os.WriteFile(fileName, []byte("rules: [{name: cpu, target: cpu}]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(fileName, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if got := Check(args); got != "rule cpu" {
+		t.Errorf("Check() after reload = %q, want %q", got, "rule cpu")
+	}
+
+	if err := os.WriteFile(fileName, []byte("unknown: true"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	future = future.Add(time.Minute)
+	if err := os.Chtimes(fileName, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if got := Check(args); !strings.Contains(got, "policy file is invalid") {
+		t.Errorf("Check() with invalid file = %q, want denied", got)
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	command := ParseCommand(strings.Fields("c time travel --offset -5m --jvm-opts -Xmx512m -d --uid abc"))
+	if command.Operation != CreateOperation {
+		t.Errorf("ParseCommand() operation = %s, want %s", command.Operation, CreateOperation)
+	}
+	want := map[string]string{"offset": "-5m", "jvm-opts": "-Xmx512m", "d": "true", "uid": "abc"}
+	if !reflect.DeepEqual(command.Flags, want) {
+		t.Errorf("ParseCommand() flags = %v, want %v", command.Flags, want)
+	}
+}
+
+func TestCheckShell(t *testing.T) {
+	line := "create cpu fullload; create network loss --interface eth0"
+	if got := CheckShell(line); got != "" {
+		t.Fatalf("CheckShell() without policy = %q, want allowed", got)
+	}
+	fileName := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(fileName, []byte("rules: []"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Init(fileName); err != nil {
+		t.Fatalf("Init() err: %v", err)
+	}
+	defer Init("")
+	for _, line := range []string{
+		line,
+		"create cpu fullload && reboot",
+		"create cpu fullload | sh",
+		"create cpu fullload --cpu-count `nproc`",
+		"create cpu fullload --cpu-count $(nproc)",
+		"create cpu fullload > /etc/passwd",
+		"create cpu fullload < /dev/null",
+		"create cpu fullload\nreboot",
+	} {
+		if got := CheckShell(line); got == "" {
+			t.Errorf("CheckShell(%q) = allowed, want denied", line)
+		}
+	}
+	if got := CheckShell("create cpu fullload --cpu-percent 60 --timeout 60"); got != "" {
+		t.Errorf("CheckShell() = %q, want allowed", got)
+	}
+}
@@ -0,0 +1,318 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+// the status of one check, only Fail fails the report
+const (
+	Pass = "pass"
+	Fail = "fail"
+	// Warn means the check is not conclusive, eg: the cgroup root is not found but it's not required
+	Warn = "warn"
+	// Skip means the check cannot be done on this agent, eg: no kubernetes client
+	Skip = "skip"
+)
+
+// cgroupRoot is the default mount point of cgroups
+const cgroupRoot = "/sys/fs/cgroup"
+
+// Check is the result of one check
+type Check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Report is the result of all checks, nothing is injected by the checks
+type Report struct {
+	Command string  `json:"command"`
+	Passed  bool    `json:"passed"`
+	Checks  []Check `json:"checks"`
+}
+
+// Add appends the check, the report fails if the check fails
+func (r *Report) Add(name, status, detail string) {
+	r.Checks = append(r.Checks, Check{Name: name, Status: status, Detail: detail})
+	if status == Fail {
+		r.Passed = false
+	}
+}
+
+// PodCounter returns the number of pods matched by the label selector or the names in the namespace
+type PodCounter func(ctx context.Context, namespace, selector string, names []string) (int, error)
+
+// Checker checks whether the target of the experiment exists and the required tools are present
+type Checker struct {
+	// LookPath finds the command, default is exec.LookPath
+	LookPath func(file string) (string, error)
+	// Pods is nil if the agent is not in a kubernetes cluster
+	Pods PodCounter
+}
+
+func NewChecker(pods PodCounter) *Checker {
+	return &Checker{LookPath: exec.LookPath, Pods: pods}
+}
+
+// hostFlags are the flags of the processes, interfaces and paths, which are checked on the host
+var hostFlags = []string{"pid", "process", "process-cmd", "interface"}
+
+// pathFlags are the flags of the files or directories
+var pathFlags = []string{"path", "filepath", "cgroup-root"}
+
+// requiredTools are the commands required by the scope, the target or the target action
+var requiredTools = map[string][]string{
+	"docker":            {"docker"},
+	"cri":               {"crictl"},
+	"network delay":     {"tc"},
+	"network loss":      {"tc"},
+	"network duplicate": {"tc"},
+	"network corrupt":   {"tc"},
+	"network reorder":   {"tc"},
+	"network drop":      {"iptables"},
+	"network dns":       {"iptables"},
+}
+
+// RequiredTools returns the commands required by the experiment
+func RequiredTools(exp *experiment.Experiment) []string {
+	commands := make([]string, 0)
+	for _, key := range []string{exp.Scope, exp.Target, exp.Target + " " + exp.Action} {
+		commands = append(commands, requiredTools[key]...)
+	}
+	return commands
+}
+
+// Run checks the experiment on the host, the report passes if no check fails
+func (c *Checker) Run(ctx context.Context, exp *experiment.Experiment) *Report {
+	report := &Report{Command: experiment.CommandLine(exp.Args()), Passed: true, Checks: make([]Check, 0)}
+	for _, command := range RequiredTools(exp) {
+		if path, err := c.LookPath(command); err != nil {
+			report.Add("tool "+command, Fail, fmt.Sprintf("%s is not found", command))
+		} else {
+			report.Add("tool "+command, Pass, path)
+		}
+	}
+	if isHostScope(exp.Scope) {
+		pids := c.checkProcesses(report, exp.Flags)
+		if name, ok := exp.Flags["interface"]; ok {
+			checkInterface(report, name)
+		}
+		for _, flag := range pathFlags {
+			if path, ok := exp.Flags[flag]; ok && path != "" {
+				checkPath(report, flag, path)
+			}
+		}
+		if exp.Target == "jvm" {
+			checkJvm(report, pids)
+		}
+	} else {
+		skipHostChecks(report, exp)
+	}
+	if exp.Target == "cpu" || exp.Target == "mem" {
+		if _, ok := exp.Flags["cgroup-root"]; !ok {
+			if tools.IsExist(cgroupRoot) {
+				report.Add("cgroup", Pass, cgroupRoot)
+			} else {
+				report.Add("cgroup", Warn, fmt.Sprintf("%s is not found", cgroupRoot))
+			}
+		}
+	}
+	if id, ok := exp.Flags["container-id"]; ok {
+		c.checkContainer(ctx, report, exp.Scope, id)
+	}
+	if exp.Scope == "k8s" {
+		c.checkPods(ctx, report, exp.Flags)
+	}
+	return report
+}
+
+// isHostScope returns true if the experiment runs on the host, not in the pods or containers
+func isHostScope(scope string) bool {
+	return scope == "" || scope == "host"
+}
+
+// skipHostChecks reports the host checks as skipped, the processes, interfaces and paths of the
+// pods or containers are not visible on the host
+func skipHostChecks(report *Report, exp *experiment.Experiment) {
+	detail := fmt.Sprintf("it's in the %s scope, not checked on the host", exp.Scope)
+	for _, flag := range append(hostFlags, pathFlags...) {
+		if value, ok := exp.Flags[flag]; ok && value != "" {
+			report.Add(flag+" "+value, Skip, detail)
+		}
+	}
+	if exp.Target == "jvm" {
+		report.Add("jvm", Skip, detail)
+	}
+}
+
+// checkProcesses checks the pid and process flags, returns the matched pids
+func (c *Checker) checkProcesses(report *Report, flags map[string]string) []int {
+	pids := make([]int, 0)
+	if value, ok := flags["pid"]; ok {
+		for _, item := range strings.Split(value, ",") {
+			pid, err := strconv.Atoi(strings.TrimSpace(item))
+			switch {
+			case err != nil:
+				report.Add("pid "+item, Fail, "pid must be a number")
+			case !tools.ProcessAlive(pid):
+				report.Add("pid "+item, Fail, fmt.Sprintf("process %d is not found", pid))
+			default:
+				report.Add("pid "+item, Pass, tools.ProcessCmdline(pid))
+				pids = append(pids, pid)
+			}
+		}
+	}
+	for _, flag := range []string{"process", "process-cmd"} {
+		value, ok := flags[flag]
+		if !ok {
+			continue
+		}
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			matched := tools.FindProcesses(name)
+			if len(matched) == 0 {
+				report.Add("process "+name, Fail, fmt.Sprintf("no process matches %s", name))
+				continue
+			}
+			report.Add("process "+name, Pass, fmt.Sprintf("pids %v", matched))
+			pids = append(pids, matched...)
+		}
+	}
+	return pids
+}
+
+func checkInterface(report *Report, name string) {
+	if _, err := net.InterfaceByName(name); err != nil {
+		names := make([]string, 0)
+		if interfaces, err := net.Interfaces(); err == nil {
+			for _, i := range interfaces {
+				names = append(names, i.Name)
+			}
+		}
+		report.Add("interface "+name, Fail, fmt.Sprintf("interface is not found, available: %s", strings.Join(names, ", ")))
+		return
+	}
+	report.Add("interface "+name, Pass, "found")
+}
+
+func checkPath(report *Report, flag, path string) {
+	if _, err := os.Stat(path); err != nil {
+		report.Add(flag+" "+path, Fail, err.Error())
+		return
+	}
+	report.Add(flag+" "+path, Pass, "found")
+}
+
+// checkContainer inspects the container by the cli of the runtime
+func (c *Checker) checkContainer(ctx context.Context, report *Report, scope, id string) {
+	cli := "docker"
+	if scope == "cri" {
+		cli = "crictl"
+	}
+	path, err := c.LookPath(cli)
+	if err != nil {
+		report.Add("container "+id, Skip, fmt.Sprintf("%s is not found", cli))
+		return
+	}
+	output, err := exec.CommandContext(ctx, path, "inspect", id).CombinedOutput()
+	if err != nil {
+		report.Add("container "+id, Fail, fmt.Sprintf("container is not found, %s", strings.TrimSpace(string(output))))
+		return
+	}
+	report.Add("container "+id, Pass, "found")
+}
+
+// checkJvm checks whether the java processes can be attached: the process is java, the attach
+// mechanism is not disabled, and the agent runs as root or the same user
+func checkJvm(report *Report, pids []int) {
+	if len(pids) == 0 {
+		report.Add("jvm", Fail, "no java process is specified by pid or process")
+		return
+	}
+	for _, pid := range pids {
+		name := fmt.Sprintf("jvm attach %d", pid)
+		cmdline := tools.ProcessCmdline(pid)
+		switch {
+		case !strings.Contains(cmdline, "java"):
+			report.Add(name, Fail, "process is not java")
+		case strings.Contains(cmdline, "-XX:+DisableAttachMechanism"):
+			report.Add(name, Fail, "attach mechanism is disabled by -XX:+DisableAttachMechanism")
+		default:
+			if uid, ok := processUid(pid); ok && os.Geteuid() != 0 && os.Geteuid() != uid {
+				report.Add(name, Fail, fmt.Sprintf("agent runs as uid %d, but the process runs as uid %d", os.Geteuid(), uid))
+				continue
+			}
+			report.Add(name, Pass, "attachable")
+		}
+	}
+}
+
+// processUid reads the real uid from /proc/pid/status
+func processUid(pid int) (int, bool) {
+	content, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, false
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 && fields[0] == "Uid:" {
+			uid, err := strconv.Atoi(fields[1])
+			return uid, err == nil
+		}
+	}
+	return 0, false
+}
+
+// checkPods checks the pods selected by the labels or the names in the namespaces
+func (c *Checker) checkPods(ctx context.Context, report *Report, flags map[string]string) {
+	if c.Pods == nil {
+		report.Add("pods", Skip, "agent is not in a kubernetes cluster")
+		return
+	}
+	selector, names := flags["labels"], tools.SplitValues(flags["names"])
+	if selector == "" && len(names) == 0 {
+		return
+	}
+	namespaces := tools.SplitValues(flags["namespace"])
+	if len(namespaces) == 0 {
+		namespaces = []string{"default"}
+	}
+	for _, namespace := range namespaces {
+		name := fmt.Sprintf("pods in %s", namespace)
+		count, err := c.Pods(ctx, namespace, selector, names)
+		switch {
+		case err != nil:
+			report.Add(name, Fail, err.Error())
+		case count == 0:
+			report.Add(name, Fail, fmt.Sprintf("no pod matches labels %q names %v", selector, names))
+		default:
+			report.Add(name, Pass, fmt.Sprintf("%d pods matched", count))
+		}
+	}
+}
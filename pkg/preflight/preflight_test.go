@@ -0,0 +1,112 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package preflight
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+)
+
+func TestRun(t *testing.T) {
+	// built at runtime, or the command line of the test runner may contain it
+	missing := "no-such-process-" + strconv.Itoa(os.Getpid())
+	lookPath := func(file string) (string, error) {
+		if file == "tc" {
+			return "/sbin/tc", nil
+		}
+		return "", errors.New("not found")
+	}
+	pods := func(ctx context.Context, namespace, selector string, names []string) (int, error) {
+		if namespace == "empty" {
+			return 0, nil
+		}
+		return 2, nil
+	}
+	tests := []struct {
+		name       string
+		exp        experiment.Experiment
+		pods       PodCounter
+		wantPassed bool
+		wantStatus map[string]string
+	}{
+		{
+			name:       "network delay",
+			exp:        experiment.Experiment{Target: "network", Action: "delay", Flags: map[string]string{"interface": "lo"}},
+			wantPassed: true,
+			wantStatus: map[string]string{"tool tc": Pass, "interface lo": Pass},
+		},
+		{
+			name:       "missing interface and iptables",
+			exp:        experiment.Experiment{Target: "network", Action: "drop", Flags: map[string]string{"interface": "no-such-eth"}},
+			wantStatus: map[string]string{"tool iptables": Fail, "interface no-such-eth": Fail},
+		},
+		{
+			name:       "pid",
+			exp:        experiment.Experiment{Target: "process", Action: "stop", Flags: map[string]string{"pid": strconv.Itoa(os.Getpid())}},
+			wantPassed: true,
+			wantStatus: map[string]string{"pid " + strconv.Itoa(os.Getpid()): Pass},
+		},
+		{
+			name:       "process not found",
+			exp:        experiment.Experiment{Target: "process", Action: "kill", Flags: map[string]string{"process": missing}},
+			wantStatus: map[string]string{"process " + missing: Fail},
+		},
+		{
+			name:       "pods outside cluster",
+			exp:        experiment.Experiment{Scope: "k8s", Target: "pod-pod", Action: "delete", Flags: map[string]string{"labels": "app=order"}},
+			wantPassed: true,
+			wantStatus: map[string]string{"pods": Skip},
+		},
+		{
+			name:       "jvm in pods",
+			exp:        experiment.Experiment{Scope: "k8s", Target: "jvm", Action: "delay", Flags: map[string]string{"names": "order-0", "process": "java", "namespace": "default"}},
+			pods:       pods,
+			wantPassed: true,
+			wantStatus: map[string]string{"jvm": Skip, "process java": Skip, "pods in default": Pass},
+		},
+		{
+			name:       "pods not matched",
+			exp:        experiment.Experiment{Scope: "k8s", Target: "pod-pod", Action: "delete", Flags: map[string]string{"labels": "app=order", "namespace": "default,empty"}},
+			pods:       pods,
+			wantStatus: map[string]string{"pods in default": Pass, "pods in empty": Fail},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.exp.Operation = experiment.CreateOperation
+			checker := &Checker{LookPath: lookPath, Pods: tt.pods}
+			report := checker.Run(context.Background(), &tt.exp)
+			if report.Passed != tt.wantPassed {
+				t.Errorf("Passed = %t, want %t, checks: %+v", report.Passed, tt.wantPassed, report.Checks)
+			}
+			got := make(map[string]string, len(report.Checks))
+			for _, check := range report.Checks {
+				got[check.Name] = check.Status
+			}
+			for name, status := range tt.wantStatus {
+				if got[name] != status {
+					t.Errorf("check %s = %q, want %q, checks: %+v", name, got[name], status, report.Checks)
+				}
+			}
+		})
+	}
+}
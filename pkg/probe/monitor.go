@@ -0,0 +1,124 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+// BreachFunc is called once if a probe breaches the steady state during the experiment, evidences
+// are the consecutive failures of the probe
+type BreachFunc func(uid string, evidences []Evidence)
+
+type watch struct {
+	probes []Probe
+	cancel context.CancelFunc
+}
+
+// Monitor evaluates the probes of the running experiments
+type Monitor struct {
+	mutex   sync.Mutex
+	watches map[string]*watch
+}
+
+func NewMonitor() *Monitor {
+	return &Monitor{watches: make(map[string]*watch)}
+}
+
+// Watch checks each probe at its interval until the experiment is stopped or a probe breaches
+func (m *Monitor) Watch(uid string, probes []Probe, breach BreachFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.mutex.Lock()
+	if previous, ok := m.watches[uid]; ok {
+		previous.cancel()
+	}
+	m.watches[uid] = &watch{probes: probes, cancel: cancel}
+	m.mutex.Unlock()
+
+	var once sync.Once
+	for i := range probes {
+		go func(probe Probe) {
+			defer tools.PanicPrintStack()
+			evidences, breached := watchProbe(ctx, probe)
+			if !breached {
+				return
+			}
+			once.Do(func() {
+				logrus.Warningf("[probe] probe %s of experiment %s breached, evidences: %+v", probe.Name, uid, evidences)
+				cancel()
+				breach(uid, evidences)
+			})
+		}(probes[i])
+	}
+	logrus.Infof("[probe] watch experiment %s, probes: %d", uid, len(probes))
+}
+
+// Probes returns the probes watched for the experiment
+func (m *Monitor) Probes(uid string) ([]Probe, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	w, ok := m.watches[uid]
+	if !ok {
+		return nil, false
+	}
+	return w.probes, true
+}
+
+// Stop stops watching the experiment, returns the probes for the check after the experiment
+func (m *Monitor) Stop(uid string) ([]Probe, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	w, ok := m.watches[uid]
+	if !ok {
+		return nil, false
+	}
+	delete(m.watches, uid)
+	w.cancel()
+	return w.probes, true
+}
+
+// watchProbe returns the consecutive failures if the failure threshold is reached, or false if it's stopped
+func watchProbe(ctx context.Context, probe Probe) ([]Evidence, bool) {
+	ticker := time.NewTicker(probe.Interval.Duration)
+	defer ticker.Stop()
+	failures := make([]Evidence, 0, probe.FailureThreshold)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-ticker.C:
+		}
+		evidence := probe.Check(ctx)
+		if ctx.Err() != nil {
+			return nil, false
+		}
+		if evidence.Success {
+			failures = failures[:0]
+			continue
+		}
+		failures = append(failures, evidence)
+		if len(failures) >= probe.FailureThreshold {
+			return failures, true
+		}
+	}
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/executor"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+const (
+	HttpProbe    = "http"
+	TcpProbe     = "tcp"
+	ProcessProbe = "process"
+	CommandProbe = "command"
+
+	defaultTimeout  = 5 * time.Second
+	defaultInterval = 5 * time.Second
+	// minInterval keeps the probes from flooding the target
+	minInterval = time.Second
+)
+
+// Probe checks the steady state of the system before, during and after the experiment, eg:
+//
+//	{"name": "order-api", "type": "http", "url": "http://127.0.0.1:8080/health", "maxLatency": "500ms"}
+//	{"name": "mysql", "type": "tcp", "address": "10.0.0.3:3306", "failureThreshold": 3}
+//	{"name": "nginx", "type": "process", "process": "nginx"}
+//	{"name": "queue", "type": "command", "command": "test $(redis-cli llen jobs) -lt 1000"}
+type Probe struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// http: the url is requested by the method, default is GET, the status must be in expectStatus,
+	// default is 2xx and 3xx, and the latency must not exceed maxLatency if it's set
+	Url          string          `json:"url,omitempty"`
+	Method       string          `json:"method,omitempty"`
+	ExpectStatus []int           `json:"expectStatus,omitempty"`
+	MaxLatency   policy.Duration `json:"maxLatency,omitempty"`
+
+	// tcp: the address is connected, host:port
+	Address string `json:"address,omitempty"`
+
+	// process: the process of the pid or the name is alive
+	Process string `json:"process,omitempty"`
+
+	// command: the command is executed by shell, the exit code must be expectExitCode, default is 0
+	Command        string `json:"command,omitempty"`
+	ExpectExitCode int    `json:"expectExitCode,omitempty"`
+
+	// Timeout of one check, default is 5s
+	Timeout policy.Duration `json:"timeout,omitempty"`
+	// Interval of the checks during the experiment, default is 5s
+	Interval policy.Duration `json:"interval,omitempty"`
+	// FailureThreshold is how many consecutive failures breach the steady state, default is 1
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+}
+
+// Evidence is the result of one check
+type Evidence struct {
+	Probe   string `json:"probe"`
+	Type    string `json:"type"`
+	Success bool   `json:"success"`
+	// unix millis
+	Time      int64  `json:"time"`
+	LatencyMs int64  `json:"latencyMs"`
+	Detail    string `json:"detail"`
+}
+
+// Parse reads the json array of probes and fills the defaults
+func Parse(content string) ([]Probe, error) {
+	var probes []Probe
+	if err := json.Unmarshal([]byte(content), &probes); err != nil {
+		return nil, fmt.Errorf("probes must be a json array, %s", err.Error())
+	}
+	names := make(map[string]bool, len(probes))
+	for i := range probes {
+		probe := &probes[i]
+		if probe.Name == "" {
+			probe.Name = fmt.Sprintf("%s-%d", probe.Type, i+1)
+		}
+		if names[probe.Name] {
+			return nil, fmt.Errorf("duplicate probe name %s", probe.Name)
+		}
+		names[probe.Name] = true
+		if err := probe.validate(); err != nil {
+			return nil, fmt.Errorf("probe %s, %s", probe.Name, err.Error())
+		}
+	}
+	return probes, nil
+}
+
+func (p *Probe) validate() error {
+	switch p.Type {
+	case HttpProbe:
+		if !strings.HasPrefix(p.Url, "http://") && !strings.HasPrefix(p.Url, "https://") {
+			return fmt.Errorf("url must be http or https")
+		}
+	case TcpProbe:
+		if _, _, err := net.SplitHostPort(p.Address); err != nil {
+			return fmt.Errorf("address must be host:port")
+		}
+	case ProcessProbe:
+		if p.Process == "" {
+			return fmt.Errorf("process is required")
+		}
+	case CommandProbe:
+		if p.Command == "" {
+			return fmt.Errorf("command is required")
+		}
+	default:
+		return fmt.Errorf("type must be %s, %s, %s or %s", HttpProbe, TcpProbe, ProcessProbe, CommandProbe)
+	}
+	if p.Timeout.Duration <= 0 {
+		p.Timeout.Duration = defaultTimeout
+	}
+	if p.Interval.Duration <= 0 {
+		p.Interval.Duration = defaultInterval
+	}
+	if p.Interval.Duration < minInterval {
+		p.Interval.Duration = minInterval
+	}
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = 1
+	}
+	return nil
+}
+
+// Check runs the probe once
+func (p *Probe) Check(ctx context.Context) Evidence {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout.Duration)
+	defer cancel()
+	start := time.Now()
+	var detail string
+	var err error
+	switch p.Type {
+	case HttpProbe:
+		detail, err = p.checkHttp(ctx)
+	case TcpProbe:
+		detail, err = p.checkTcp(ctx)
+	case ProcessProbe:
+		detail, err = p.checkProcess()
+	case CommandProbe:
+		detail, err = p.checkCommand(ctx)
+	}
+	evidence := Evidence{
+		Probe:     p.Name,
+		Type:      p.Type,
+		Success:   err == nil,
+		Time:      start.UnixMilli(),
+		LatencyMs: time.Since(start).Milliseconds(),
+		Detail:    detail,
+	}
+	if err != nil {
+		evidence.Detail = err.Error()
+	}
+	return evidence
+}
+
+// CheckAll runs all probes once, returns the evidences and whether all succeeded
+func CheckAll(ctx context.Context, probes []Probe) ([]Evidence, bool) {
+	evidences := make([]Evidence, 0, len(probes))
+	ok := true
+	for i := range probes {
+		evidence := probes[i].Check(ctx)
+		ok = ok && evidence.Success
+		evidences = append(evidences, evidence)
+	}
+	return evidences, ok
+}
+
+func (p *Probe) checkHttp(ctx context.Context) (string, error) {
+	method := p.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	request, err := http.NewRequestWithContext(ctx, method, p.Url, nil)
+	if err != nil {
+		return "", err
+	}
+	start := time.Now()
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	response.Body.Close()
+	latency := time.Since(start)
+	if !p.expectStatus(response.StatusCode) {
+		return "", fmt.Errorf("status %d is not expected", response.StatusCode)
+	}
+	if p.MaxLatency.Duration > 0 && latency > p.MaxLatency.Duration {
+		return "", fmt.Errorf("latency %s exceeds %s", latency, p.MaxLatency.Duration)
+	}
+	return fmt.Sprintf("status %d, latency %s", response.StatusCode, latency), nil
+}
+
+func (p *Probe) expectStatus(code int) bool {
+	if len(p.ExpectStatus) == 0 {
+		return code >= 200 && code < 400
+	}
+	for _, expected := range p.ExpectStatus {
+		if code == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Probe) checkTcp(ctx context.Context) (string, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", p.Address)
+	if err != nil {
+		return "", err
+	}
+	conn.Close()
+	return fmt.Sprintf("%s connected", p.Address), nil
+}
+
+// checkProcess finds the process by the pid, or by the name or the command line
+func (p *Probe) checkProcess() (string, error) {
+	if pid, err := strconv.Atoi(p.Process); err == nil {
+		if !tools.ProcessAlive(pid) {
+			return "", fmt.Errorf("process %d is not alive", pid)
+		}
+		return fmt.Sprintf("process %d is alive", pid), nil
+	}
+	pids := tools.FindProcesses(p.Process)
+	if len(pids) == 0 {
+		return "", fmt.Errorf("process %s is not alive", p.Process)
+	}
+	return fmt.Sprintf("process %s is alive, pids %v", p.Process, pids), nil
+}
+
+func (p *Probe) checkCommand(ctx context.Context) (string, error) {
+	result := executor.Shell(ctx, p.Command, executor.Options{Timeout: p.Timeout.Duration, MaxOutput: 4 << 10})
+	if result.Err != nil {
+		return "", result.Err
+	}
+	if result.TimedOut {
+		return "", fmt.Errorf("timeout after %s", p.Timeout.Duration)
+	}
+	if result.ExitCode != p.ExpectExitCode {
+		return "", fmt.Errorf("exit code %d is not %d, stderr: %s", result.ExitCode, p.ExpectExitCode, strings.TrimSpace(result.Stderr))
+	}
+	return fmt.Sprintf("exit code %d", result.ExitCode), nil
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "valid", content: `[{"type": "http", "url": "http://127.0.0.1/health"}, {"type": "tcp", "address": "127.0.0.1:80"}]`},
+		{name: "not array", content: `{"type": "http"}`, wantErr: true},
+		{name: "unknown type", content: `[{"type": "ping"}]`, wantErr: true},
+		{name: "bad url", content: `[{"type": "http", "url": "127.0.0.1/health"}]`, wantErr: true},
+		{name: "bad address", content: `[{"type": "tcp", "address": "127.0.0.1"}]`, wantErr: true},
+		{name: "duplicate", content: `[{"name": "a", "type": "process", "process": "sshd"}, {"name": "a", "type": "process", "process": "nginx"}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.content); (err != nil) != tt.wantErr {
+				t.Errorf("Parse() err = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen err = %v", err)
+	}
+	closed := listener.Addr().String()
+	listener.Close()
+
+	tests := []struct {
+		name  string
+		probe string
+		want  bool
+	}{
+		{name: "http ok", probe: fmt.Sprintf(`{"type": "http", "url": "%s/health"}`, server.URL), want: true},
+		{name: "http status", probe: fmt.Sprintf(`{"type": "http", "url": "%s/error"}`, server.URL)},
+		{name: "http expected status", probe: fmt.Sprintf(`{"type": "http", "url": "%s/error", "expectStatus": [500]}`, server.URL), want: true},
+		{name: "http latency", probe: fmt.Sprintf(`{"type": "http", "url": "%s/slow", "maxLatency": "10ms"}`, server.URL)},
+		{name: "tcp ok", probe: fmt.Sprintf(`{"type": "tcp", "address": "%s"}`, server.Listener.Addr().String()), want: true},
+		{name: "tcp refused", probe: fmt.Sprintf(`{"type": "tcp", "address": "%s"}`, closed)},
+		{name: "command ok", probe: `{"type": "command", "command": "exit 0"}`, want: true},
+		{name: "command exit code", probe: `{"type": "command", "command": "exit 3"}`},
+		{name: "command expected exit code", probe: `{"type": "command", "command": "exit 3", "expectExitCode": 3}`, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probes, err := Parse("[" + tt.probe + "]")
+			if err != nil {
+				t.Fatalf("Parse() err = %v", err)
+			}
+			if evidence := probes[0].Check(context.Background()); evidence.Success != tt.want {
+				t.Errorf("Check() = %+v, want success %t", evidence, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitorBreach(t *testing.T) {
+	probes, err := Parse(`[{"name": "down", "type": "command", "command": "exit 1", "interval": "1s"}]`)
+	if err != nil {
+		t.Fatalf("Parse() err = %v", err)
+	}
+	monitor := NewMonitor()
+	breached := make(chan []Evidence, 1)
+	monitor.Watch("uid", probes, func(uid string, evidences []Evidence) {
+		breached <- evidences
+	})
+	select {
+	case evidences := <-breached:
+		if len(evidences) != 1 || evidences[0].Probe != "down" || evidences[0].Success {
+			t.Errorf("evidences = %+v", evidences)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("probe is not breached")
+	}
+	if _, ok := monitor.Stop("uid"); !ok {
+		t.Errorf("Stop() = false")
+	}
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package progress
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+const (
+	PhaseEvent  = "phase"
+	OutputEvent = "output"
+	ResultEvent = "result"
+
+	// MaxEvents is the maximum events kept for the subscribers which connect later
+	MaxEvents = 1000
+	// Retention is how long the stream is kept after finished or idle
+	Retention = 10 * time.Minute
+	// subscriberBuffer is the channel size of a subscriber, the slow subscriber is closed
+	subscriberBuffer = 256
+)
+
+// Event is one progress of an operation, Seq starts from 1 in each stream
+type Event struct {
+	Seq     int64               `json:"seq"`
+	Time    time.Time           `json:"time"`
+	Type    string              `json:"type"`
+	Message string              `json:"message,omitempty"`
+	Result  *transport.Response `json:"result,omitempty"`
+}
+
+type stream struct {
+	events      []Event
+	seq         int64
+	done        bool
+	updated     time.Time
+	subscribers map[chan Event]struct{}
+}
+
+var (
+	mutex   sync.Mutex
+	streams = make(map[string]*stream)
+)
+
+// getLocked returns the stream of key, creates it if absent, and removes the expired streams
+func getLocked(key string) *stream {
+	now := time.Now()
+	for k, s := range streams {
+		if len(s.subscribers) == 0 && now.Sub(s.updated) > Retention {
+			delete(streams, k)
+		}
+	}
+	s, ok := streams[key]
+	if !ok {
+		s = &stream{updated: now, subscribers: make(map[chan Event]struct{})}
+		streams[key] = s
+	}
+	return s
+}
+
+func emit(key string, event Event) {
+	if key == "" {
+		return
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	s := getLocked(key)
+	if s.done {
+		return
+	}
+	s.seq++
+	event.Seq = s.seq
+	event.Time = time.Now()
+	s.updated = event.Time
+	s.events = append(s.events, event)
+	if len(s.events) > MaxEvents {
+		s.events = s.events[len(s.events)-MaxEvents:]
+	}
+	for ch := range s.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// the subscriber can reconnect from the last seq it received
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+	if event.Type == ResultEvent {
+		s.done = true
+		for ch := range s.subscribers {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe returns the events after seq from, and the channel of the following events.
+// The channel is closed after the result event, or if the subscriber is too slow.
+func Subscribe(key string, from int64) ([]Event, <-chan Event, func()) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	s := getLocked(key)
+	past := make([]Event, 0)
+	for _, event := range s.events {
+		if event.Seq > from {
+			past = append(past, event)
+		}
+	}
+	ch := make(chan Event, subscriberBuffer)
+	if s.done {
+		close(ch)
+		return past, ch, func() {}
+	}
+	s.subscribers[ch] = struct{}{}
+	cancel := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if _, ok := s.subscribers[ch]; ok {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+	return past, ch, cancel
+}
+
+// Reporter emits the progress to the streams of request id and job id, the nil reporter does nothing
+type Reporter struct {
+	keys []string
+}
+
+// NewReporter returns nil if all keys are empty
+func NewReporter(keys ...string) *Reporter {
+	r := &Reporter{}
+	for _, key := range keys {
+		if key != "" {
+			r.keys = append(r.keys, key)
+		}
+	}
+	if len(r.keys) == 0 {
+		return nil
+	}
+	return r
+}
+
+// With returns a reporter which also emits to the key
+func (r *Reporter) With(key string) *Reporter {
+	if r == nil {
+		return NewReporter(key)
+	}
+	return NewReporter(append(append([]string{}, r.keys...), key)...)
+}
+
+func (r *Reporter) Phase(format string, args ...interface{}) {
+	r.emit(Event{Type: PhaseEvent, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *Reporter) Output(line string) {
+	r.emit(Event{Type: OutputEvent, Message: line})
+}
+
+// Finish emits the result and closes the streams
+func (r *Reporter) Finish(result *transport.Response) {
+	r.emit(Event{Type: ResultEvent, Result: result})
+}
+
+func (r *Reporter) emit(event Event) {
+	if r == nil {
+		return
+	}
+	for _, key := range r.keys {
+		emit(key, event)
+	}
+}
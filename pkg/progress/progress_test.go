@@ -0,0 +1,67 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package progress
+
+import (
+	"testing"
+
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+func TestSubscribe(t *testing.T) {
+	reporter := NewReporter("rid-1", "", "job-1")
+	reporter.Phase("exec started: %s", "create cpu load")
+
+	past, ch, cancel := Subscribe("job-1", 0)
+	defer cancel()
+	if len(past) != 1 || past[0].Seq != 1 || past[0].Message != "exec started: create cpu load" {
+		t.Fatalf("Subscribe() past = %+v", past)
+	}
+
+	reporter.Output("line")
+	reporter.Finish(transport.ReturnSuccessWithResult("uid"))
+	reporter.Phase("after finished")
+
+	var events []Event
+	for event := range ch {
+		events = append(events, event)
+	}
+	if len(events) != 2 || events[0].Type != OutputEvent || events[1].Type != ResultEvent || events[1].Seq != 3 {
+		t.Errorf("events = %+v", events)
+	}
+
+	// reconnect from the last seq received
+	past, ch, _ = Subscribe("rid-1", 2)
+	if len(past) != 1 || past[0].Type != ResultEvent {
+		t.Errorf("Subscribe() past after finished = %+v", past)
+	}
+	if _, ok := <-ch; ok {
+		t.Errorf("channel of finished stream is not closed")
+	}
+}
+
+func TestNilReporter(t *testing.T) {
+	var reporter *Reporter
+	reporter.Phase("ignored")
+	reporter.Finish(nil)
+	if NewReporter("", "") != nil {
+		t.Errorf("NewReporter() without keys should be nil")
+	}
+	if r := reporter.With("job-2"); r == nil || len(r.keys) != 1 {
+		t.Errorf("With() = %+v", r)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package radius
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+// containerScopes are the first subject of the commands whose target is the second one,
+// eg: create k8s pod-network delay
+var containerScopes = map[string]bool{"k8s": true, "docker": true, "cri": true}
+
+// Limits caps the concurrent experiments, 0 means unlimited
+type Limits struct {
+	// Host is the maximum experiments on this agent
+	Host int
+	// Targets is the maximum experiments of each target type, eg: network=2, pod-network=1
+	Targets map[string]int
+	// Namespace is the maximum k8s experiments in one namespace
+	Namespace int
+	// Workload is the maximum k8s experiments on the same pods, which are selected by the same
+	// labels or names in the namespace
+	Workload int
+}
+
+// Enabled returns false if nothing is limited
+func (l *Limits) Enabled() bool {
+	return l.Host > 0 || len(l.Targets) > 0 || l.Namespace > 0 || l.Workload > 0
+}
+
+// Scope is what an experiment is counted by the limits
+type Scope struct {
+	Target     string
+	Namespaces []string
+	Workloads  []string
+}
+
+// ScopeOf returns the scope of the blade create arguments, eg: create k8s pod-network delay --namespace default --labels app=order
+func ScopeOf(args []string) Scope {
+	command := policy.ParseCommand(args)
+	scope := Scope{Target: command.Target()}
+	if len(command.Subjects) > 1 && containerScopes[command.Subjects[0]] {
+		scope.Target = command.Subjects[1]
+	}
+	if len(command.Subjects) == 0 || command.Subjects[0] != "k8s" {
+		return scope
+	}
+	namespaces := tools.SplitValues(command.Flags["namespace"])
+	if len(namespaces) == 0 {
+		namespaces = []string{"default"}
+	}
+	selector := "*"
+	if labels := command.Flags["labels"]; labels != "" {
+		selector = "labels:" + labels
+	} else if names := tools.SplitValues(command.Flags["names"]); len(names) > 0 {
+		sort.Strings(names)
+		selector = "names:" + strings.Join(names, ",")
+	}
+	for _, namespace := range namespaces {
+		scope.Namespaces = append(scope.Namespaces, namespace)
+		scope.Workloads = append(scope.Workloads, namespace+"/"+selector)
+	}
+	return scope
+}
+
+// Check returns the reason if the next experiment exceeds the limits with the running ones, or empty string
+func (l *Limits) Check(running []Scope, next Scope) string {
+	if l.Host > 0 && len(running) >= l.Host {
+		return fmt.Sprintf("%d experiments are running on the host, the limit is %d", len(running), l.Host)
+	}
+	if limit, ok := l.Targets[next.Target]; ok && limit > 0 {
+		count := 0
+		for _, scope := range running {
+			if scope.Target == next.Target {
+				count++
+			}
+		}
+		if count >= limit {
+			return fmt.Sprintf("%d %s experiments are running, the limit is %d", count, next.Target, limit)
+		}
+	}
+	if l.Namespace > 0 {
+		for _, namespace := range next.Namespaces {
+			if count := countOf(running, namespace, func(s Scope) []string { return s.Namespaces }); count >= l.Namespace {
+				return fmt.Sprintf("%d experiments are running in namespace %s, the limit is %d", count, namespace, l.Namespace)
+			}
+		}
+	}
+	if l.Workload > 0 {
+		for _, workload := range next.Workloads {
+			if count := countOf(running, workload, func(s Scope) []string { return s.Workloads }); count >= l.Workload {
+				return fmt.Sprintf("%d experiments are running on workload %s, the limit is %d", count, workload, l.Workload)
+			}
+		}
+	}
+	return ""
+}
+
+// countOf returns how many running experiments have the value
+func countOf(running []Scope, value string, values func(Scope) []string) int {
+	count := 0
+	for _, scope := range running {
+		for _, v := range values(scope) {
+			if v == value {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package radius
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func scopeOf(cmdline string) Scope {
+	return ScopeOf(strings.Fields(cmdline))
+}
+
+func TestScopeOf(t *testing.T) {
+	tests := []struct {
+		cmdline string
+		want    Scope
+	}{
+		{cmdline: "create network delay --time 100 --interface eth0", want: Scope{Target: "network"}},
+		{cmdline: "create docker cpu fullload --container-id abc", want: Scope{Target: "cpu"}},
+		{
+			cmdline: "create k8s pod-network delay --namespace a,b --labels app=order",
+			want: Scope{
+				Target:     "pod-network",
+				Namespaces: []string{"a", "b"},
+				Workloads:  []string{"a/labels:app=order", "b/labels:app=order"},
+			},
+		},
+		{
+			cmdline: "create k8s pod-pod delete --names web-2,web-1",
+			want:    Scope{Target: "pod-pod", Namespaces: []string{"default"}, Workloads: []string{"default/names:web-1,web-2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmdline, func(t *testing.T) {
+			if got := scopeOf(tt.cmdline); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ScopeOf() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitsCheck(t *testing.T) {
+	running := []Scope{
+		scopeOf("create network delay --time 100"),
+		scopeOf("create k8s pod-network loss --namespace shop --labels app=order"),
+	}
+	tests := []struct {
+		name    string
+		limits  Limits
+		next    string
+		wantErr bool
+	}{
+		{name: "unlimited", next: "create network loss"},
+		{name: "host", limits: Limits{Host: 2}, next: "create cpu fullload", wantErr: true},
+		{name: "target", limits: Limits{Targets: map[string]int{"network": 1}}, next: "create network loss", wantErr: true},
+		{name: "other target", limits: Limits{Targets: map[string]int{"network": 1}}, next: "create cpu fullload"},
+		{name: "namespace", limits: Limits{Namespace: 1}, next: "create k8s pod-cpu fullload --namespace shop --labels app=cart", wantErr: true},
+		{name: "workload", limits: Limits{Workload: 1}, next: "create k8s pod-cpu fullload --namespace shop --labels app=order", wantErr: true},
+		{name: "other workload", limits: Limits{Workload: 1}, next: "create k8s pod-cpu fullload --namespace shop --labels app=cart"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reason := tt.limits.Check(running, scopeOf(tt.next)); (reason != "") != tt.wantErr {
+				t.Errorf("Check() = %q, wantErr %t", reason, tt.wantErr)
+			}
+		})
+	}
+}
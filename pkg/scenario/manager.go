@@ -0,0 +1,370 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+const (
+	// CancelledStatus is the scenario or the step stopped by cancel
+	CancelledStatus = "Cancelled"
+	// RolledBackStatus is the applied step whose experiment is destroyed
+	RolledBackStatus = "RolledBack"
+
+	// rollbackAttempts is how many times the experiment of a step is destroyed before giving up
+	rollbackAttempts = 3
+	rollbackInterval = time.Second
+)
+
+// Executor applies the experiments of the steps and undoes them
+type Executor interface {
+	// Apply returns the uid of the created experiment
+	Apply(step Step) (uid string, err error)
+	Undo(step Step, uid string) error
+}
+
+// StepResult is the execution of one step
+type StepResult struct {
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	Uid           string     `json:"uid,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	RollbackError string     `json:"rollbackError,omitempty"`
+	StartTime     *time.Time `json:"startTime,omitempty"`
+	EndTime       *time.Time `json:"endTime,omitempty"`
+}
+
+// Execution is a run of the scenario, Status is Running, Success, Error or Cancelled
+type Execution struct {
+	Id         string       `json:"id"`
+	RequestId  string       `json:"rid,omitempty"`
+	Name       string       `json:"name"`
+	Status     string       `json:"status"`
+	Error      string       `json:"error,omitempty"`
+	Steps      []StepResult `json:"steps"`
+	SubmitTime time.Time    `json:"submitTime"`
+	EndTime    *time.Time   `json:"endTime,omitempty"`
+}
+
+// DoneFunc is called with the copy of the execution when it's finished
+type DoneFunc func(execution *Execution)
+
+type execution struct {
+	Execution
+	cancel context.CancelFunc
+}
+
+// Manager runs the scenarios and keeps the finished executions for retention
+type Manager struct {
+	mutex      sync.Mutex
+	executions map[string]*execution
+	retention  time.Duration
+}
+
+func NewManager(retention time.Duration) *Manager {
+	return &Manager{
+		executions: make(map[string]*execution),
+		retention:  retention,
+	}
+}
+
+// Start runs the scenario in background, the copy of the execution is returned immediately
+func (m *Manager) Start(scenario *Scenario, rid string, executor Executor, done DoneFunc) *Execution {
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &execution{
+		Execution: Execution{
+			Id:         tools.GetUUID(),
+			RequestId:  rid,
+			Name:       scenario.Name,
+			Status:     status.Running,
+			Steps:      make([]StepResult, len(scenario.Steps)),
+			SubmitTime: time.Now(),
+		},
+		cancel: cancel,
+	}
+	for i, step := range scenario.Steps {
+		e.Steps[i] = StepResult{Name: step.Name, Status: status.Pending}
+	}
+	m.mutex.Lock()
+	m.cleanLocked()
+	m.executions[e.Id] = e
+	snapshot := e.copyLocked()
+	m.mutex.Unlock()
+
+	go func() {
+		defer tools.PanicPrintStack()
+		defer cancel()
+		m.run(ctx, e, scenario, executor)
+		if done != nil {
+			if finished, ok := m.Get(e.Id); ok {
+				done(finished)
+			}
+		}
+	}()
+	return snapshot
+}
+
+// Cancel stops the running execution, the applied steps are rolled back. It returns false if the
+// execution is not found or finished.
+func (m *Manager) Cancel(id string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	e, ok := m.executions[id]
+	if !ok || e.EndTime != nil {
+		return false
+	}
+	e.cancel()
+	return true
+}
+
+// Get returns the copy of the execution
+func (m *Manager) Get(id string) (*Execution, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	e, ok := m.executions[id]
+	if !ok {
+		return nil, false
+	}
+	return e.copyLocked(), true
+}
+
+// List returns the copies of all executions ordered by submit time
+func (m *Manager) List() []*Execution {
+	m.mutex.Lock()
+	m.cleanLocked()
+	executions := make([]*Execution, 0, len(m.executions))
+	for _, e := range m.executions {
+		executions = append(executions, e.copyLocked())
+	}
+	m.mutex.Unlock()
+	sort.Slice(executions, func(i, j int) bool {
+		return executions[i].SubmitTime.Before(executions[j].SubmitTime)
+	})
+	return executions
+}
+
+// run executes the steps in order, then rolls back the applied steps in reverse order if any step
+// aborted the scenario, it's cancelled or the experiments are not kept
+func (m *Manager) run(ctx context.Context, e *execution, scenario *Scenario, executor Executor) {
+	applied := make([]int, 0, len(scenario.Steps))
+	aborted, failed := false, make([]string, 0)
+	for i, step := range scenario.Steps {
+		if ctx.Err() != nil {
+			break
+		}
+		m.updateStep(e, i, func(result *StepResult) {
+			now := time.Now()
+			result.Status, result.StartTime = status.Running, &now
+		})
+		var uid string
+		var err error
+		if step.IsWait() {
+			err = sleep(ctx, step.Wait.Duration)
+		} else {
+			uid, err = apply(ctx, executor, step)
+			if uid != "" {
+				applied = append(applied, i)
+			}
+		}
+		m.updateStep(e, i, func(result *StepResult) {
+			now := time.Now()
+			result.Uid, result.EndTime = uid, &now
+			switch {
+			case err == nil:
+				result.Status = status.Success
+			case ctx.Err() != nil:
+				result.Status, result.Error = CancelledStatus, err.Error()
+			default:
+				result.Status, result.Error = status.Error, err.Error()
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			logrus.Warningf("[scenario] step %s of %s failed, onFailure: %s, err: %v", step.Name, scenario.Name, step.OnFailure, err)
+			failed = append(failed, step.Name)
+			if step.OnFailure == AbortOnFailure {
+				aborted = true
+				break
+			}
+		}
+	}
+
+	cancelled := ctx.Err() != nil
+	var rollbackFailed []string
+	if aborted || cancelled || !scenario.Keep {
+		rollbackFailed = m.rollback(e, scenario, executor, applied)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := time.Now()
+	e.EndTime = &now
+	switch {
+	case cancelled:
+		e.Status = CancelledStatus
+	case aborted:
+		e.Status, e.Error = status.Error, fmt.Sprintf("step %s failed", failed[len(failed)-1])
+	case len(rollbackFailed) > 0:
+		e.Status = status.Error
+	default:
+		e.Status = status.Success
+		if len(failed) > 0 {
+			e.Error = fmt.Sprintf("steps %s failed and continued", strings.Join(failed, ", "))
+		}
+	}
+	if len(rollbackFailed) > 0 {
+		message := fmt.Sprintf("rollback of steps %s failed", strings.Join(rollbackFailed, ", "))
+		if e.Error != "" {
+			message = e.Error + ", " + message
+		}
+		e.Error = message
+	}
+	logrus.Infof("[scenario] scenario %s finished, id: %s, status: %s, err: %s", e.Name, e.Id, e.Status, e.Error)
+}
+
+// rollback undoes the applied steps in reverse order, it's not cancellable and each step is retried,
+// returns the names of the steps which are not rolled back
+func (m *Manager) rollback(e *execution, scenario *Scenario, executor Executor, applied []int) []string {
+	failed := make([]string, 0)
+	for i := len(applied) - 1; i >= 0; i-- {
+		index := applied[i]
+		step := scenario.Steps[index]
+		uid := m.stepUid(e, index)
+		var err error
+		for attempt := 1; attempt <= rollbackAttempts; attempt++ {
+			if err = undo(executor, step, uid); err == nil {
+				break
+			}
+			logrus.Warningf("[scenario] rollback step %s of %s failed, uid: %s, attempt: %d, err: %v",
+				step.Name, scenario.Name, uid, attempt, err)
+			if attempt < rollbackAttempts {
+				time.Sleep(rollbackInterval)
+			}
+		}
+		m.updateStep(e, index, func(result *StepResult) {
+			if err != nil {
+				result.RollbackError = err.Error()
+				return
+			}
+			result.Status = RolledBackStatus
+		})
+		if err != nil {
+			failed = append(failed, step.Name)
+		}
+	}
+	return failed
+}
+
+// apply waits for the experiment of the step until the timeout or cancel. The step fails then, but the
+// in-flight command is still waited for, so the experiment created by it is rolled back too.
+func apply(ctx context.Context, executor Executor, step Step) (string, error) {
+	type result struct {
+		uid string
+		err error
+	}
+	results := make(chan result, 1)
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				results <- result{err: fmt.Errorf("apply panic: %v", err)}
+			}
+		}()
+		uid, err := executor.Apply(step)
+		results <- result{uid: uid, err: err}
+	}()
+
+	var timeout <-chan time.Time
+	if step.Timeout.Duration > 0 {
+		timer := time.NewTimer(step.Timeout.Duration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var err error
+	select {
+	case r := <-results:
+		return r.uid, r.err
+	case <-timeout:
+		err = fmt.Errorf("timeout after %s", step.Timeout.Duration)
+	case <-ctx.Done():
+		err = errors.New("cancelled")
+	}
+	r := <-results
+	return r.uid, err
+}
+
+func undo(executor Executor, step Step, uid string) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("undo panic: %v", recovered)
+		}
+	}()
+	return executor.Undo(step, uid)
+}
+
+// sleep returns an error if it's cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return errors.New("cancelled")
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (m *Manager) updateStep(e *execution, index int, fn func(result *StepResult)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	fn(&e.Steps[index])
+}
+
+func (m *Manager) stepUid(e *execution, index int) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return e.Steps[index].Uid
+}
+
+func (e *execution) copyLocked() *Execution {
+	snapshot := e.Execution
+	snapshot.Steps = append([]StepResult{}, e.Steps...)
+	return &snapshot
+}
+
+// cleanLocked removes the executions finished before retention
+func (m *Manager) cleanLocked() {
+	if m.retention <= 0 {
+		return
+	}
+	expired := time.Now().Add(-m.retention)
+	for id, e := range m.executions {
+		if e.EndTime != nil && e.EndTime.Before(expired) {
+			delete(m.executions, id)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+)
+
+const (
+	// AbortOnFailure stops the scenario and rolls back the applied steps if the step fails
+	AbortOnFailure = "abort"
+	// ContinueOnFailure goes on with the next step if the step fails
+	ContinueOnFailure = "continue"
+)
+
+// Scenario is the ordered steps, the experiments of the applied steps are destroyed in reverse order
+// once the scenario is finished, failed or cancelled, eg:
+//
+//	{"name": "jvm-then-cpu", "steps": [
+//	  {"name": "prepare-jvm", "experiment": {"operation": "prepare", "target": "jvm", "flags": {"pid": "1234"}}},
+//	  {"name": "exception", "experiment": {"operation": "create", "target": "jvm", "action": "throwCustomException", ...}},
+//	  {"name": "observe", "wait": "60s"},
+//	  {"name": "cpu", "experiment": {"operation": "create", "target": "cpu", "action": "fullload"}, "onFailure": "continue"}
+//	]}
+type Scenario struct {
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+	// Keep leaves the experiments in effect after all steps succeeded, they are still rolled back on failure
+	Keep bool `json:"keep,omitempty"`
+}
+
+// Step is an experiment or a wait
+type Step struct {
+	Name       string                 `json:"name"`
+	Experiment *experiment.Experiment `json:"experiment,omitempty"`
+	Wait       policy.Duration        `json:"wait,omitempty"`
+	// Timeout of applying the experiment, the default is the blade timeout
+	Timeout policy.Duration `json:"timeout,omitempty"`
+	// OnFailure is abort or continue, default is abort
+	OnFailure string `json:"onFailure,omitempty"`
+}
+
+// IsWait returns true if the step only waits
+func (s *Step) IsWait() bool {
+	return s.Experiment == nil
+}
+
+// Parse reads the scenario json, the default step names and failure policies are filled
+func Parse(content string) (*Scenario, error) {
+	var scenario Scenario
+	if err := json.Unmarshal([]byte(content), &scenario); err != nil {
+		return nil, fmt.Errorf("scenario must be a json object, %s", err.Error())
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// Validate checks the steps and fills the defaults, the experiments are checked by the executor
+func (s *Scenario) Validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("scenario has no steps")
+	}
+	names := make(map[string]bool, len(s.Steps))
+	for i := range s.Steps {
+		step := &s.Steps[i]
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step-%d", i+1)
+		}
+		if names[step.Name] {
+			return fmt.Errorf("duplicate step name %s", step.Name)
+		}
+		names[step.Name] = true
+		if step.OnFailure == "" {
+			step.OnFailure = AbortOnFailure
+		}
+		if step.OnFailure != AbortOnFailure && step.OnFailure != ContinueOnFailure {
+			return fmt.Errorf("onFailure of step %s must be %s or %s", step.Name, AbortOnFailure, ContinueOnFailure)
+		}
+		if step.Timeout.Duration < 0 || step.Wait.Duration < 0 {
+			return fmt.Errorf("timeout and wait of step %s cannot be negative", step.Name)
+		}
+		if step.IsWait() {
+			if step.Wait.Duration == 0 {
+				return fmt.Errorf("step %s has neither experiment nor wait", step.Name)
+			}
+			continue
+		}
+		if step.Wait.Duration != 0 {
+			return fmt.Errorf("step %s cannot have both experiment and wait", step.Name)
+		}
+		switch step.Experiment.Operation {
+		case experiment.CreateOperation, experiment.PrepareOperation:
+		default:
+			return fmt.Errorf("operation of step %s must be %s or %s", step.Name,
+				experiment.CreateOperation, experiment.PrepareOperation)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
+)
+
+// fakeExecutor fails the steps in fail, and records the applied and undone steps in order
+type fakeExecutor struct {
+	mutex   sync.Mutex
+	fail    map[string]bool
+	delay   map[string]time.Duration
+	applied []string
+	undone  []string
+}
+
+func (f *fakeExecutor) Apply(step Step) (string, error) {
+	time.Sleep(f.delay[step.Name])
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.fail[step.Name] {
+		return "", errors.New("failed")
+	}
+	f.applied = append(f.applied, step.Name)
+	return "uid-" + step.Name, nil
+}
+
+func (f *fakeExecutor) Undo(step Step, uid string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.undone = append(f.undone, step.Name)
+	return nil
+}
+
+func newScenario(t *testing.T, keep bool, steps ...string) *Scenario {
+	content := fmt.Sprintf(`{"name": "test", "keep": %t, "steps": [%s]}`, keep, strings.Join(steps, ","))
+	scenario, err := Parse(content)
+	if err != nil {
+		t.Fatalf("Parse() err = %v", err)
+	}
+	return scenario
+}
+
+func experimentStep(name, extra string) string {
+	return fmt.Sprintf(`{"name": %q, "experiment": {"operation": "create", "target": "cpu", "action": "fullload"}%s}`, name, extra)
+}
+
+func wait(t *testing.T, m *Manager, id string) *Execution {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if e, ok := m.Get(id); ok && e.EndTime != nil {
+			return e
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("execution %s is not finished", id)
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "valid", content: `{"steps": [` + experimentStep("a", "") + `, {"wait": "1s"}]}`},
+		{name: "empty", content: `{"steps": []}`, wantErr: true},
+		{name: "duplicate", content: `{"steps": [` + experimentStep("a", "") + `,` + experimentStep("a", "") + `]}`, wantErr: true},
+		{name: "destroy", content: `{"steps": [{"experiment": {"operation": "destroy", "uid": "x"}}]}`, wantErr: true},
+		{name: "nothing", content: `{"steps": [{"name": "a"}]}`, wantErr: true},
+		{name: "policy", content: `{"steps": [` + experimentStep("a", `, "onFailure": "retry"`) + `]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.content); (err != nil) != tt.wantErr {
+				t.Errorf("Parse() err = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManagerRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		keep        bool
+		steps       []string
+		fail        []string
+		wantStatus  string
+		wantApplied []string
+		wantUndone  []string
+	}{
+		{
+			name:        "teardown in reverse order",
+			steps:       []string{experimentStep("a", ""), `{"wait": "10ms"}`, experimentStep("b", "")},
+			wantStatus:  status.Success,
+			wantApplied: []string{"a", "b"},
+			wantUndone:  []string{"b", "a"},
+		},
+		{
+			name:        "keep",
+			keep:        true,
+			steps:       []string{experimentStep("a", ""), experimentStep("b", "")},
+			wantStatus:  status.Success,
+			wantApplied: []string{"a", "b"},
+		},
+		{
+			name:        "abort rolls back",
+			keep:        true,
+			steps:       []string{experimentStep("a", ""), experimentStep("b", ""), experimentStep("c", "")},
+			fail:        []string{"b"},
+			wantStatus:  status.Error,
+			wantApplied: []string{"a"},
+			wantUndone:  []string{"a"},
+		},
+		{
+			name:        "continue",
+			keep:        true,
+			steps:       []string{experimentStep("a", `, "onFailure": "continue"`), experimentStep("b", "")},
+			fail:        []string{"a"},
+			wantStatus:  status.Success,
+			wantApplied: []string{"b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &fakeExecutor{fail: make(map[string]bool)}
+			for _, name := range tt.fail {
+				executor.fail[name] = true
+			}
+			m := NewManager(time.Hour)
+			e := wait(t, m, m.Start(newScenario(t, tt.keep, tt.steps...), "", executor, nil).Id)
+			if e.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s, err: %s", e.Status, tt.wantStatus, e.Error)
+			}
+			if !reflect.DeepEqual(executor.applied, tt.wantApplied) {
+				t.Errorf("applied = %v, want %v", executor.applied, tt.wantApplied)
+			}
+			if !reflect.DeepEqual(executor.undone, tt.wantUndone) {
+				t.Errorf("undone = %v, want %v", executor.undone, tt.wantUndone)
+			}
+		})
+	}
+}
+
+func TestManagerCancel(t *testing.T) {
+	executor := &fakeExecutor{}
+	m := NewManager(time.Hour)
+	scenario := newScenario(t, true, experimentStep("a", ""), `{"name": "observe", "wait": "1m"}`, experimentStep("b", ""))
+	started := m.Start(scenario, "", executor, nil)
+	time.Sleep(50 * time.Millisecond)
+	if !m.Cancel(started.Id) {
+		t.Fatalf("Cancel() = false")
+	}
+	e := wait(t, m, started.Id)
+	if e.Status != CancelledStatus {
+		t.Errorf("status = %s, want %s", e.Status, CancelledStatus)
+	}
+	if !reflect.DeepEqual(executor.undone, []string{"a"}) {
+		t.Errorf("undone = %v, want [a]", executor.undone)
+	}
+	if m.Cancel(started.Id) {
+		t.Errorf("Cancel() of finished execution = true")
+	}
+}
+
+func TestStepTimeoutRollsBackLateExperiment(t *testing.T) {
+	executor := &fakeExecutor{delay: map[string]time.Duration{"slow": 100 * time.Millisecond}}
+	m := NewManager(time.Hour)
+	scenario := newScenario(t, true, experimentStep("slow", `, "timeout": "10ms"`))
+	e := wait(t, m, m.Start(scenario, "", executor, nil).Id)
+	if e.Status != status.Error {
+		t.Errorf("status = %s, want %s", e.Status, status.Error)
+	}
+	if !reflect.DeepEqual(executor.undone, []string{"slow"}) {
+		t.Errorf("undone = %v, want [slow]", executor.undone)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronHorizon limits how far Next looks ahead, eg: 0 0 30 2 * never matches
+const cronHorizon = 5 * 365 * 24 * time.Hour
+
+// cronField is the range and the names of one field of the cron expression
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is sunday too
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Cron is the standard 5 fields cron expression in local time: minute hour day-of-month month day-of-week,
+// each field is *, a value, a range, a list or a step, eg: 0 14 * * mon-fri, */15 9-18 * * 1,3,5
+type Cron struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64
+	// the day matches both day fields if either is *, otherwise any of them
+	domStar, dowStar bool
+}
+
+// ParseCron parses the 5 fields cron expression
+func ParseCron(expr string) (*Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q must have 5 fields: minute hour day-of-month month day-of-week", expr)
+	}
+	cron := &Cron{expr: strings.Join(fields, " ")}
+	var err error
+	if cron.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if cron.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if cron.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if cron.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if cron.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if cron.dow&(1<<7) != 0 {
+		cron.dow |= 1
+	}
+	cron.domStar, cron.dowStar = fields[2] == "*", fields[4] == "*"
+	return cron, nil
+}
+
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Match returns true if the minute of t matches
+func (c *Cron) Match(t time.Time) bool {
+	return has(c.month, int(t.Month())) && c.matchDay(t) && has(c.hour, t.Hour()) && has(c.minute, t.Minute())
+}
+
+// Next returns the first matched minute after t, or zero time if it's not matched in 5 years
+func (c *Cron) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := next.Add(cronHorizon)
+	for next.Before(limit) {
+		year, month, day := next.Date()
+		switch {
+		case !has(c.month, int(month)):
+			next = time.Date(year, month+1, 1, 0, 0, 0, 0, next.Location())
+		case !c.matchDay(next):
+			next = time.Date(year, month, day+1, 0, 0, 0, 0, next.Location())
+		case !has(c.hour, next.Hour()):
+			next = time.Date(year, month, day, next.Hour()+1, 0, 0, 0, next.Location())
+		case !has(c.minute, next.Minute()):
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Cron) matchDay(t time.Time) bool {
+	dom, dow := has(c.dom, t.Day()), has(c.dow, int(t.Weekday()))
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
+
+// parse returns the bits of the matched values
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		low, high, step := f.min, f.max, 1
+		rangeExpr := part
+		if index := strings.Index(part, "/"); index >= 0 {
+			value, err := strconv.Atoi(part[index+1:])
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("illegal step %q of %s", part, f.name)
+			}
+			step, rangeExpr = value, part[:index]
+		}
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if low, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			switch {
+			case len(bounds) == 2:
+				if high, err = f.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			case step == 1:
+				high = low
+			}
+			if low > high {
+				return 0, fmt.Errorf("illegal range %q of %s", rangeExpr, f.name)
+			}
+		}
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(expr string) (int, error) {
+	if value, ok := f.names[strings.ToLower(expr)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(expr)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("illegal %s %q, must be in [%d, %d]", f.name, expr, f.min, f.max)
+	}
+	return value, nil
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+// namePattern restricts the names of schedules and blackout windows
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Schedule creates the experiment when the cron is matched, and destroys it after the duration, eg:
+//
+//	name: gameday-latency
+//	cron: 0 14 * * mon-fri
+//	duration: 15m
+//	jitter: 1m
+//	experiment: {operation: create, target: network, action: delay, flags: {time: "200", interface: eth0}}
+type Schedule struct {
+	Name string `json:"name"`
+	Cron string `json:"cron"`
+	// Duration is how long the experiment is kept in each run
+	Duration policy.Duration `json:"duration"`
+	// Jitter is the maximum random delay of each run, so the agents of a group don't start together
+	Jitter     policy.Duration       `json:"jitter,omitempty"`
+	Experiment experiment.Experiment `json:"experiment"`
+	Disabled   bool                  `json:"disabled,omitempty"`
+	// unix millis
+	UpdateTime int64 `json:"updateTime"`
+}
+
+// Validate checks the schedule, the experiment is checked by the handler
+func (s *Schedule) Validate() error {
+	if !namePattern.MatchString(s.Name) {
+		return fmt.Errorf("illegal schedule name %q", s.Name)
+	}
+	if _, err := ParseCron(s.Cron); err != nil {
+		return err
+	}
+	if s.Duration.Duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	if s.Jitter.Duration < 0 {
+		return fmt.Errorf("jitter cannot be negative")
+	}
+	if s.Experiment.Operation != experiment.CreateOperation {
+		return fmt.Errorf("operation of the scheduled experiment must be %s", experiment.CreateOperation)
+	}
+	return nil
+}
+
+// Window is the recurring blackout window, no run of any schedule overlaps it, eg: 0 9 * * mon, 2h
+type Window struct {
+	Name     string          `json:"name"`
+	Cron     string          `json:"cron"`
+	Duration policy.Duration `json:"duration"`
+}
+
+func (w *Window) Validate() error {
+	if !namePattern.MatchString(w.Name) {
+		return fmt.Errorf("illegal window name %q", w.Name)
+	}
+	if _, err := ParseCron(w.Cron); err != nil {
+		return err
+	}
+	if w.Duration.Duration <= 0 {
+		return fmt.Errorf("duration of window %s must be positive", w.Name)
+	}
+	return nil
+}
+
+// Blackout returns the name of the first window which overlaps [start, end)
+func Blackout(windows []Window, start, end time.Time) (string, bool) {
+	for _, window := range windows {
+		cron, err := ParseCron(window.Cron)
+		if err != nil {
+			continue
+		}
+		// the window which begins in (start - duration, end) overlaps
+		begin := cron.Next(start.Add(-window.Duration.Duration))
+		if !begin.IsZero() && begin.Before(end) {
+			return window.Name, true
+		}
+	}
+	return "", false
+}
+
+// document is the content of the schedule file
+type document struct {
+	Schedules []Schedule `json:"schedules"`
+	Blackouts []Window   `json:"blackouts"`
+}
+
+// Store keeps the schedules and the blackout windows in a local file, so they survive restarts
+// and don't depend on the server
+type Store struct {
+	mutex     sync.Mutex
+	file      string
+	schedules map[string]Schedule
+	blackouts []Window
+}
+
+// NewStore loads the schedule file. The broken file is kept aside as .corrupt and the store starts empty,
+// so the agent is still able to start.
+func NewStore(file string) *Store {
+	store := &Store{file: file, schedules: make(map[string]Schedule), blackouts: make([]Window, 0)}
+	var doc document
+	loaded, err := tools.LoadJsonFile(file, &doc)
+	if err != nil {
+		logrus.Errorf("[schedule] load schedule file %s failed, err: %v", file, err)
+		return store
+	}
+	if !loaded {
+		return store
+	}
+	for _, schedule := range doc.Schedules {
+		store.schedules[schedule.Name] = schedule
+	}
+	if doc.Blackouts != nil {
+		store.blackouts = doc.Blackouts
+	}
+	logrus.Infof("[schedule] schedule file %s loaded, schedules: %d, blackouts: %d", file, len(doc.Schedules), len(doc.Blackouts))
+	return store
+}
+
+// Put adds or replaces the schedule with the same name
+func (s *Store) Put(schedule Schedule) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	previous, existed := s.schedules[schedule.Name]
+	s.schedules[schedule.Name] = schedule
+	if err := s.saveLocked(); err != nil {
+		if existed {
+			s.schedules[schedule.Name] = previous
+		} else {
+			delete(s.schedules, schedule.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+// Remove returns false if the schedule is not found
+func (s *Store) Remove(name string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	previous, ok := s.schedules[name]
+	if !ok {
+		return false, nil
+	}
+	delete(s.schedules, name)
+	if err := s.saveLocked(); err != nil {
+		s.schedules[name] = previous
+		return true, err
+	}
+	return true, nil
+}
+
+func (s *Store) Get(name string) (Schedule, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	schedule, ok := s.schedules[name]
+	return schedule, ok
+}
+
+// List returns the schedules sorted by name
+func (s *Store) List() []Schedule {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	schedules := make([]Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		schedules = append(schedules, schedule)
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Name < schedules[j].Name
+	})
+	return schedules
+}
+
+// SetBlackouts replaces all of the blackout windows
+func (s *Store) SetBlackouts(windows []Window) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	previous := s.blackouts
+	s.blackouts = windows
+	if err := s.saveLocked(); err != nil {
+		s.blackouts = previous
+		return err
+	}
+	return nil
+}
+
+func (s *Store) Blackouts() []Window {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Window{}, s.blackouts...)
+}
+
+// saveLocked writes a temporary file and renames it, the schedule file is never half written
+func (s *Store) saveLocked() error {
+	doc := document{Schedules: make([]Schedule, 0, len(s.schedules)), Blackouts: s.blackouts}
+	for _, schedule := range s.schedules {
+		doc.Schedules = append(doc.Schedules, schedule)
+	}
+	sort.Slice(doc.Schedules, func(i, j int) bool {
+		return doc.Schedules[i].Name < doc.Schedules[j].Name
+	})
+	bytes, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return tools.WriteFileAtomic(s.file, bytes, 0o600)
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedule
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+)
+
+func date(day, hour, minute int) time.Time {
+	// 2024-01-01 is monday
+	return time.Date(2024, 1, day, hour, minute, 0, 0, time.Local)
+}
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{expr: "0 14 * * mon-fri"},
+		{expr: "*/15 9-18 1,15 * 7"},
+		{expr: "0 0 * jan-mar */2"},
+		{expr: "0 14 * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "0 18-9 * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "0 0 * * funday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseCron(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCron() err = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		// friday 14:00 is passed, the next weekday is monday
+		{expr: "0 14 * * mon-fri", after: date(5, 14, 0), want: date(8, 14, 0)},
+		{expr: "0 14 * * mon-fri", after: date(2, 13, 59), want: date(2, 14, 0)},
+		{expr: "*/15 * * * *", after: date(1, 10, 7), want: date(1, 10, 15)},
+		// sunday as 7
+		{expr: "30 2 * * 7", after: date(1, 0, 0), want: date(7, 2, 30)},
+		// day of month or day of week if both are restricted
+		{expr: "0 0 20 * sat", after: date(1, 0, 0), want: date(6, 0, 0)},
+		{expr: "0 0 30 2 *", after: date(1, 0, 0), want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron() err = %v", err)
+			}
+			if got := cron.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next() = %s, want %s", got, tt.want)
+			}
+			if !tt.want.IsZero() && !cron.Match(tt.want) {
+				t.Errorf("Match(%s) = false", tt.want)
+			}
+		})
+	}
+}
+
+func TestBlackout(t *testing.T) {
+	windows := []Window{
+		{Name: "monday-release", Cron: "0 9 * * mon", Duration: policy.Duration{Duration: 2 * time.Hour}},
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{name: "before", start: date(1, 8, 0), end: date(1, 9, 0)},
+		{name: "overlap begin", start: date(1, 8, 50), end: date(1, 9, 5), want: true},
+		{name: "inside", start: date(1, 10, 0), end: date(1, 10, 15), want: true},
+		{name: "after", start: date(1, 11, 0), end: date(1, 11, 15)},
+		{name: "tuesday", start: date(2, 9, 30), end: date(2, 9, 45)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := Blackout(windows, tt.start, tt.end); got != tt.want {
+				t.Errorf("Blackout() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schedules.json")
+	store := NewStore(file)
+	schedule := Schedule{
+		Name:       "gameday",
+		Cron:       "0 14 * * mon-fri",
+		Duration:   policy.Duration{Duration: 15 * time.Minute},
+		Experiment: experiment.Experiment{Operation: experiment.CreateOperation, Target: "cpu", Action: "fullload"},
+	}
+	if err := schedule.Validate(); err != nil {
+		t.Fatalf("Validate() err = %v", err)
+	}
+	if err := store.Put(schedule); err != nil {
+		t.Fatalf("Put() err = %v", err)
+	}
+	window := Window{Name: "freeze", Cron: "0 0 * * *", Duration: policy.Duration{Duration: time.Hour}}
+	if err := store.SetBlackouts([]Window{window}); err != nil {
+		t.Fatalf("SetBlackouts() err = %v", err)
+	}
+
+	loaded := NewStore(file)
+	got, ok := loaded.Get("gameday")
+	if !ok || got.Cron != schedule.Cron || got.Duration != schedule.Duration || got.Experiment.Target != "cpu" {
+		t.Errorf("Get() = %+v, %t", got, ok)
+	}
+	if blackouts := loaded.Blackouts(); len(blackouts) != 1 || blackouts[0].Name != "freeze" {
+		t.Errorf("Blackouts() = %+v", blackouts)
+	}
+	if removed, err := loaded.Remove("gameday"); !removed || err != nil {
+		t.Errorf("Remove() = %t, %v", removed, err)
+	}
+	if schedules := NewStore(file).List(); len(schedules) != 0 {
+		t.Errorf("List() = %+v, want empty", schedules)
+	}
+}
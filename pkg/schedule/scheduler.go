@@ -0,0 +1,195 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedule
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+// SkippedStatus is reported if the run is not started, eg: it overlaps a blackout window
+const SkippedStatus = "Skipped"
+
+// Runner creates and destroys the experiments of the schedules
+type Runner interface {
+	Create(schedule Schedule) (uid string, err error)
+	Destroy(schedule Schedule, uid string) error
+}
+
+// Run is one execution of a schedule, it's reported at each status change: Skipped, Running, Destroyed or Error
+type Run struct {
+	Schedule string `json:"schedule"`
+	// Fire is the matched minute of the cron, unix millis
+	Fire   int64  `json:"fire"`
+	Uid    string `json:"uid,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReportFunc receives the runs
+type ReportFunc func(run Run)
+
+// Scheduler checks the schedules at every minute and runs the matched ones
+type Scheduler struct {
+	store  *Store
+	runner Runner
+	report ReportFunc
+	stopCh chan struct{}
+
+	mutex sync.Mutex
+	// active is the names of the schedules whose run is not finished
+	active map[string]bool
+}
+
+func NewScheduler(store *Store, runner Runner, report ReportFunc) *Scheduler {
+	return &Scheduler{
+		store:  store,
+		runner: runner,
+		report: report,
+		stopCh: make(chan struct{}),
+		active: make(map[string]bool),
+	}
+}
+
+func (s *Scheduler) Start() error {
+	go func() {
+		defer tools.PanicPrintStack()
+		for {
+			now := time.Now()
+			timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+			select {
+			case <-s.stopCh:
+				timer.Stop()
+				return
+			case fire := <-timer.C:
+				s.trigger(fire.Truncate(time.Minute))
+			}
+		}
+	}()
+	logrus.Infof("[schedule] start successfully, schedules: %d", len(s.store.List()))
+	return nil
+}
+
+// Shutdown stops the scheduler, the experiments of the active runs are destroyed by their timeout flag
+func (s *Scheduler) Shutdown() {
+	close(s.stopCh)
+}
+
+// Due returns the enabled schedules whose cron matches the minute
+func (s *Scheduler) Due(minute time.Time) []Schedule {
+	due := make([]Schedule, 0)
+	for _, schedule := range s.store.List() {
+		if schedule.Disabled {
+			continue
+		}
+		cron, err := ParseCron(schedule.Cron)
+		if err != nil {
+			logrus.Warningf("[schedule] illegal cron of schedule %s, err: %v", schedule.Name, err)
+			continue
+		}
+		if cron.Match(minute) {
+			due = append(due, schedule)
+		}
+	}
+	return due
+}
+
+func (s *Scheduler) trigger(minute time.Time) {
+	for _, schedule := range s.Due(minute) {
+		run := Run{Schedule: schedule.Name, Fire: minute.UnixMilli()}
+		if !s.activate(schedule.Name) {
+			run.Status, run.Error = SkippedStatus, "the previous run is still in effect"
+			s.report(run)
+			continue
+		}
+		go func(schedule Schedule) {
+			defer tools.PanicPrintStack()
+			defer s.deactivate(schedule.Name)
+			s.run(schedule, run)
+		}(schedule)
+	}
+}
+
+// run creates the experiment after the jitter, and destroys it after the duration
+func (s *Scheduler) run(schedule Schedule, run Run) {
+	if jitter := schedule.Jitter.Duration; jitter > 0 {
+		if !s.sleep(time.Duration(rand.Int63n(int64(jitter)))) {
+			return
+		}
+	}
+	start := time.Now()
+	if window, ok := Blackout(s.store.Blackouts(), start, start.Add(schedule.Duration.Duration)); ok {
+		run.Status, run.Error = SkippedStatus, fmt.Sprintf("blackout window %s", window)
+		s.report(run)
+		return
+	}
+	logrus.Infof("[schedule] run schedule %s, experiment: %v", schedule.Name, schedule.Experiment.Args())
+	uid, err := s.runner.Create(schedule)
+	if err != nil {
+		run.Status, run.Error = status.Error, err.Error()
+		s.report(run)
+		return
+	}
+	run.Uid, run.Status = uid, status.Running
+	s.report(run)
+
+	if !s.sleep(schedule.Duration.Duration) {
+		return
+	}
+	if err := s.runner.Destroy(schedule, uid); err != nil {
+		run.Status, run.Error = status.Error, err.Error()
+	} else {
+		run.Status = status.Destroyed
+	}
+	s.report(run)
+}
+
+// sleep returns false if the scheduler is stopped
+func (s *Scheduler) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-s.stopCh:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// activate returns false if the schedule is already active
+func (s *Scheduler) activate(name string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.active[name] {
+		return false
+	}
+	s.active[name] = true
+	return true
+}
+
+func (s *Scheduler) deactivate(name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.active, name)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Record is the common fields of the records of blade status
+type Record struct {
+	Uid         string `json:"Uid"`
+	Status      string `json:"Status"`
+	Error       string `json:"Error"`
+	ProgramType string `json:"ProgramType,omitempty"`
+}
+
+// QueryFunc returns the records of blade status --type kind, keyed by uid
+type QueryFunc func(ctx context.Context, kind string) (map[string]Record, error)
+
+// BladeSource reads the status from the blade records of one kind (create or prepare). The records of
+// all experiments are read by one blade process and shared by the waiters within maxAge.
+type BladeSource struct {
+	kind   string
+	query  QueryFunc
+	maxAge time.Duration
+
+	mutex   sync.Mutex
+	records map[string]Record
+	updated time.Time
+}
+
+func NewBladeSource(kind string, query QueryFunc, maxAge time.Duration) *BladeSource {
+	return &BladeSource{
+		kind:   kind,
+		query:  query,
+		maxAge: maxAge,
+	}
+}
+
+// Records returns the records which are read within maxAge
+func (s *BladeSource) Records(ctx context.Context) (map[string]Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.records != nil && time.Since(s.updated) < s.maxAge {
+		return s.records, nil
+	}
+	records, err := s.query(ctx, s.kind)
+	if err != nil {
+		return nil, err
+	}
+	s.records, s.updated = records, time.Now()
+	return records, nil
+}
+
+// Record returns the record of uid, false if it's not found
+func (s *BladeSource) Record(ctx context.Context, uid string) (Record, bool, error) {
+	records, err := s.Records(ctx)
+	if err != nil {
+		return Record{}, false, err
+	}
+	record, ok := records[uid]
+	return record, ok, nil
+}
+
+func (s *BladeSource) Status(ctx context.Context, uid string) (Result, error) {
+	record, ok, err := s.Record(ctx, uid)
+	if err != nil {
+		return Result{}, err
+	}
+	if !ok {
+		return Result{Status: Unknown, Error: "record not found"}, nil
+	}
+	return Result{Status: Normalize(record.Status), Error: record.Error, Raw: record.Status}, nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"context"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// ChaosbladeResource is the custom resource of chaosblade-operator, which is named by the experiment uid
+var ChaosbladeResource = schema.GroupVersionResource{Group: "chaosblade.io", Version: "v1alpha1", Resource: "chaosblades"}
+
+// CRSource reads and watches the status of the chaosblade custom resources
+type CRSource struct {
+	client dynamic.Interface
+}
+
+func NewCRSource(client dynamic.Interface) *CRSource {
+	return &CRSource{
+		client: client,
+	}
+}
+
+func (s *CRSource) Status(ctx context.Context, uid string) (Result, error) {
+	obj, err := s.client.Resource(ChaosbladeResource).Get(ctx, uid, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return Result{Status: Unknown, Error: "custom resource not found"}, nil
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return crResult(obj), nil
+}
+
+// Watch lists the custom resource and watches the changes from the listed version
+func (s *CRSource) Watch(ctx context.Context, uid string) (<-chan Result, error) {
+	resource := s.client.Resource(ChaosbladeResource)
+	selector := "metadata.name=" + uid
+	list, err := resource.List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := resource.Watch(ctx, metav1.ListOptions{FieldSelector: selector, ResourceVersion: list.GetResourceVersion()})
+	if err != nil {
+		return nil, err
+	}
+	results := make(chan Result, 1)
+	go func() {
+		defer close(results)
+		defer watcher.Stop()
+		send := func(result Result) bool {
+			select {
+			case results <- result:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for i := range list.Items {
+			if !send(crResult(&list.Items[i])) {
+				return
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.ResultChan():
+				if !ok {
+					return
+				}
+				obj, isObj := event.Object.(*unstructured.Unstructured)
+				if !isObj {
+					continue
+				}
+				result := crResult(obj)
+				if event.Type == watch.Deleted {
+					result.Status, result.Raw = Destroyed, Destroyed
+				}
+				if !send(result) {
+					return
+				}
+			}
+		}
+	}()
+	return results, nil
+}
+
+// crResult returns the phase of the custom resource with the errors of the experiment and resource statuses
+func crResult(obj *unstructured.Unstructured) Result {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	result := Result{Status: Pending, Raw: phase}
+	if phase != "" {
+		result.Status = Normalize(phase)
+	}
+	errs := make([]string, 0)
+	expStatuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "expStatuses")
+	for _, exp := range expStatuses {
+		expStatus, ok := exp.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if msg, ok := expStatus["error"].(string); ok && msg != "" {
+			errs = append(errs, msg)
+		}
+		resStatuses, _, _ := unstructured.NestedSlice(expStatus, "resStatuses")
+		for _, res := range resStatuses {
+			resStatus, ok := res.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if msg, ok := resStatus["error"].(string); ok && msg != "" {
+				errs = append(errs, msg)
+			}
+		}
+	}
+	result.Error = strings.Join(errs, "; ")
+	return result
+}
@@ -0,0 +1,258 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// the unified status of experiments and preparations of all backends
+const (
+	// Pending means it's accepted but not in effect yet
+	Pending = "Pending"
+	// Running means it's in effect
+	Running = "Running"
+	// Success means it's finished or injected successfully
+	Success = "Success"
+	Error   = "Error"
+	// Destroyed means it's destroyed or revoked
+	Destroyed = "Destroyed"
+	// Unknown means the status cannot be read, eg: the record is not found
+	Unknown = "Unknown"
+)
+
+// backendStatus maps the lower case status of blade records and the phase of chaosblade
+// custom resources to the unified status
+var backendStatus = map[string]string{
+	"created":     Pending,
+	"initialized": Pending,
+	"updating":    Pending,
+	"running":     Running,
+	"destroying":  Running,
+	"success":     Success,
+	"error":       Error,
+	"destroyed":   Destroyed,
+	"revoked":     Destroyed,
+}
+
+// Normalize returns the unified status of the backend status
+func Normalize(backend string) string {
+	if status, ok := backendStatus[strings.ToLower(backend)]; ok {
+		return status
+	}
+	return Unknown
+}
+
+// Result is the status and the error of an experiment
+type Result struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Raw is the status of the backend, eg: Created of the blade records, Running of the custom resources
+	Raw string `json:"raw,omitempty"`
+}
+
+// RawStatus returns the status of the backend for the legacy reports, or the unified status if the
+// backend status is not read
+func (r Result) RawStatus() string {
+	if r.Raw != "" {
+		return r.Raw
+	}
+	return r.Status
+}
+
+// Source reads the status of an experiment from one backend
+type Source interface {
+	Status(ctx context.Context, uid string) (Result, error)
+}
+
+// Watcher is the Source which pushes the status changes, the channel is closed once the watch ends
+type Watcher interface {
+	Watch(ctx context.Context, uid string) (<-chan Result, error)
+}
+
+// fallbackSource reads the status from fallback if primary fails
+type fallbackSource struct {
+	primary  Source
+	fallback Source
+}
+
+// WithFallback returns the Source which reads and watches the primary, the status is read from the
+// fallback once the primary fails. It's polled if the watch of the primary fails.
+func WithFallback(primary, fallback Source) Source {
+	return &fallbackSource{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+func (s *fallbackSource) Status(ctx context.Context, uid string) (Result, error) {
+	result, err := s.primary.Status(ctx, uid)
+	if err == nil {
+		return result, nil
+	}
+	logrus.Debugf("[status] read status of %s failed, read the fallback instead, err: %v", uid, err)
+	return s.fallback.Status(ctx, uid)
+}
+
+func (s *fallbackSource) Watch(ctx context.Context, uid string) (<-chan Result, error) {
+	watcher, ok := s.primary.(Watcher)
+	if !ok {
+		return nil, fmt.Errorf("watch is not supported")
+	}
+	return watcher.Watch(ctx, uid)
+}
+
+const (
+	minPollInterval = 500 * time.Millisecond
+	maxPollInterval = 5 * time.Second
+)
+
+// DefaultDeadlines are the deadlines keyed by the experiment type, which is the operation
+// (create, prepare) or the more specific type (jvm, cplus, k8s, litmus)
+var DefaultDeadlines = map[string]time.Duration{
+	"create":  time.Minute,
+	"prepare": time.Minute,
+	"jvm":     time.Minute,
+	"cplus":   2 * time.Minute,
+	"k8s":     10 * time.Second,
+	"litmus":  2 * time.Minute,
+}
+
+// defaultDeadline is the deadline of the types which are not configured
+const defaultDeadline = time.Minute
+
+// Tracker waits for the status of experiments, it watches the Watcher sources and polls the others
+// with backoff until the status is not pending or the deadline of the experiment type is exceeded
+type Tracker struct {
+	deadlines   map[string]time.Duration
+	minInterval time.Duration
+	maxInterval time.Duration
+}
+
+func NewTracker(deadlines map[string]time.Duration) *Tracker {
+	return &Tracker{
+		deadlines:   deadlines,
+		minInterval: minPollInterval,
+		maxInterval: maxPollInterval,
+	}
+}
+
+var (
+	tracker = NewTracker(DefaultDeadlines)
+	mutex   sync.RWMutex
+)
+
+// Init overrides the default deadlines, eg: {"k8s": "30s"}
+func Init(deadlines map[string]string) error {
+	merged := make(map[string]time.Duration, len(DefaultDeadlines)+len(deadlines))
+	for kind, deadline := range DefaultDeadlines {
+		merged[kind] = deadline
+	}
+	for kind, value := range deadlines {
+		deadline, err := time.ParseDuration(value)
+		if err != nil || deadline <= 0 {
+			return fmt.Errorf("illegal status deadline of %s: %s", kind, value)
+		}
+		merged[kind] = deadline
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	tracker = NewTracker(merged)
+	return nil
+}
+
+// Wait waits for the status by the default tracker
+func Wait(ctx context.Context, kind, uid string, source Source, pending ...string) Result {
+	mutex.RLock()
+	t := tracker
+	mutex.RUnlock()
+	return t.Wait(ctx, kind, uid, source, pending...)
+}
+
+// Deadline returns the deadline of the experiment type
+func (t *Tracker) Deadline(kind string) time.Duration {
+	if deadline, ok := t.deadlines[kind]; ok {
+		return deadline
+	}
+	return defaultDeadline
+}
+
+// Wait returns the first status which is not in pending, Unknown is always pending. The last status
+// is returned with an error once the deadline of kind is exceeded.
+func (t *Tracker) Wait(ctx context.Context, kind, uid string, source Source, pending ...string) Result {
+	deadline := t.Deadline(kind)
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+	isPending := func(status string) bool {
+		if status == Unknown {
+			return true
+		}
+		for _, p := range pending {
+			if status == p {
+				return true
+			}
+		}
+		return false
+	}
+
+	last := Result{Status: Unknown}
+	if watcher, ok := source.(Watcher); ok {
+		events, err := watcher.Watch(ctx, uid)
+		if err != nil {
+			logrus.Debugf("[status] watch %s failed, poll instead, err: %v", uid, err)
+		} else {
+			for result := range events {
+				last = result
+				if !isPending(result.Status) {
+					return result
+				}
+			}
+		}
+	}
+
+	interval := t.minInterval
+	for ctx.Err() == nil {
+		result, err := source.Status(ctx, uid)
+		if err != nil {
+			logrus.Debugf("[status] query status of %s failed, err: %v", uid, err)
+		} else {
+			last = result
+			if !isPending(result.Status) {
+				return result
+			}
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+		}
+		if interval *= 2; interval > t.maxInterval {
+			interval = t.maxInterval
+		}
+	}
+	logrus.Warningf("[status] status of %s is still %s after %s", uid, last.Status, deadline)
+	return Result{
+		Status: last.Status,
+		Error:  fmt.Sprintf("status is still %s after %s", last.Status, deadline),
+		Raw:    last.Raw,
+	}
+}
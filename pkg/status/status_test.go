@@ -0,0 +1,196 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Created":     Pending,
+		"Initialized": Pending,
+		"Running":     Running,
+		"Success":     Success,
+		"Error":       Error,
+		"Revoked":     Destroyed,
+		"Destroyed":   Destroyed,
+		"":            Unknown,
+		"other":       Unknown,
+	}
+	for backend, want := range tests {
+		if got := Normalize(backend); got != want {
+			t.Errorf("Normalize(%q) = %s, want %s", backend, got, want)
+		}
+	}
+}
+
+// sequenceSource returns the statuses in order, the last one is repeated
+type sequenceSource struct {
+	mutex    sync.Mutex
+	statuses []string
+	calls    int
+}
+
+func (s *sequenceSource) Status(ctx context.Context, uid string) (Result, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	index := s.calls
+	if index >= len(s.statuses) {
+		index = len(s.statuses) - 1
+	}
+	s.calls++
+	return Result{Status: s.statuses[index]}, nil
+}
+
+// watchSource pushes the statuses by watch, and never answers the polling
+type watchSource struct {
+	statuses []string
+}
+
+func (s *watchSource) Status(ctx context.Context, uid string) (Result, error) {
+	return Result{Status: Unknown}, nil
+}
+
+func (s *watchSource) Watch(ctx context.Context, uid string) (<-chan Result, error) {
+	results := make(chan Result, len(s.statuses))
+	for _, status := range s.statuses {
+		results <- Result{Status: status}
+	}
+	close(results)
+	return results, nil
+}
+
+// failedSource fails to read and watch, eg: the custom resources are forbidden
+type failedSource struct{}
+
+func (failedSource) Status(ctx context.Context, uid string) (Result, error) {
+	return Result{}, errors.New("forbidden")
+}
+
+func (failedSource) Watch(ctx context.Context, uid string) (<-chan Result, error) {
+	return nil, errors.New("forbidden")
+}
+
+func newTestTracker(deadline time.Duration) *Tracker {
+	tracker := NewTracker(map[string]time.Duration{"test": deadline})
+	tracker.minInterval, tracker.maxInterval = 10*time.Millisecond, 20*time.Millisecond
+	return tracker
+}
+
+func TestTrackerWait(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  Source
+		pending []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "poll until running",
+			source:  &sequenceSource{statuses: []string{Unknown, Pending, Running}},
+			pending: []string{Pending},
+			want:    Running,
+		},
+		{
+			name:    "running is pending for uninstall",
+			source:  &sequenceSource{statuses: []string{Running, Destroyed}},
+			pending: []string{Pending, Running},
+			want:    Destroyed,
+		},
+		{
+			name:    "deadline exceeded",
+			source:  &sequenceSource{statuses: []string{Pending}},
+			pending: []string{Pending},
+			want:    Pending,
+			wantErr: true,
+		},
+		{
+			name:    "watch",
+			source:  &watchSource{statuses: []string{Pending, Error}},
+			pending: []string{Pending},
+			want:    Error,
+		},
+		{
+			name:    "fallback",
+			source:  WithFallback(failedSource{}, &sequenceSource{statuses: []string{Pending, Running}}),
+			pending: []string{Pending},
+			want:    Running,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestTracker(300*time.Millisecond).Wait(context.Background(), "test", "uid", tt.source, tt.pending...)
+			if result.Status != tt.want || (result.Error != "") != tt.wantErr {
+				t.Errorf("Wait() = %+v, want status %s, error %t", result, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBladeSourceSharesQuery(t *testing.T) {
+	queries := 0
+	source := NewBladeSource("create", func(ctx context.Context, kind string) (map[string]Record, error) {
+		queries++
+		return map[string]Record{
+			"a": {Uid: "a", Status: "Success"},
+			"b": {Uid: "b", Status: "Error", Error: "process not found"},
+		}, nil
+	}, time.Minute)
+
+	if result, _ := source.Status(context.Background(), "a"); result.Status != Success || result.RawStatus() != "Success" {
+		t.Errorf("Status(a) = %+v, want %s", result, Success)
+	}
+	if result, _ := source.Status(context.Background(), "b"); result.Status != Error || result.Error != "process not found" {
+		t.Errorf("Status(b) = %+v, want %s with error", result, Error)
+	}
+	if result, _ := source.Status(context.Background(), "c"); result.Status != Unknown {
+		t.Errorf("Status(c) = %+v, want %s", result, Unknown)
+	}
+	if queries != 1 {
+		t.Errorf("blade is queried %d times, want 1", queries)
+	}
+}
+
+func TestCRResult(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase": "Error",
+			"expStatuses": []interface{}{
+				map[string]interface{}{
+					"error": "",
+					"resStatuses": []interface{}{
+						map[string]interface{}{"state": "Error", "error": "container not found"},
+						map[string]interface{}{"state": "Success"},
+					},
+				},
+			},
+		},
+	}}
+	if got := crResult(obj); got.Status != Error || got.Error != "container not found" {
+		t.Errorf("crResult() = %+v, want Error with container not found", got)
+	}
+	if got := crResult(&unstructured.Unstructured{Object: map[string]interface{}{}}); got.Status != Pending {
+		t.Errorf("crResult() without status = %+v, want %s", got, Pending)
+	}
+}
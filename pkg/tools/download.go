@@ -17,6 +17,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -24,6 +25,11 @@ import (
 )
 
 func Download(destFileFullPath, url string) error {
+	return DownloadWithContext(context.Background(), destFileFullPath, url)
+}
+
+// DownloadWithContext downloads the url to the file, it's aborted once ctx is done
+func DownloadWithContext(ctx context.Context, destFileFullPath, url string) error {
 	// 1. create destination path
 	file, err := os.Create(destFileFullPath)
 	if err != nil {
@@ -33,7 +39,11 @@ func Download(destFileFullPath, url string) error {
 	defer file.Close()
 
 	// 2. get body from url
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -39,7 +39,18 @@ const (
 	DarwinOperator  = "darwin"
 	WindowsOperator = "windows"
 )
-const AgentLog = "agent.log"
+const (
+	AgentLog = "agent.log"
+	AuditLog = "audit.log"
+	// ScheduleFile keeps the local experiment schedules
+	ScheduleFile = "schedules.json"
+	// RegistryFile keeps the running experiments
+	RegistryFile = "experiments.json"
+	// IdempotencyFile keeps the results of the idempotency keys
+	IdempotencyFile = "idempotency.json"
+	// LitmusCatalogDir keeps the litmus experiment and rbac yaml
+	LitmusCatalogDir = "litmus"
+)
 
 var Constant *Constants
 
@@ -98,6 +109,27 @@ func GetAgentLogFilePath() string {
 	return chaosLogFilePath
 }
 
+// GetAuditLogFilePath
+func GetAuditLogFilePath() string {
+	return path.Join(GetCurrentDirectory(), AuditLog)
+}
+
+func GetScheduleFilePath() string {
+	return path.Join(GetCurrentDirectory(), ScheduleFile)
+}
+
+func GetRegistryFilePath() string {
+	return path.Join(GetCurrentDirectory(), RegistryFile)
+}
+
+func GetIdempotencyFilePath() string {
+	return path.Join(GetCurrentDirectory(), IdempotencyFile)
+}
+
+func GetLitmusCatalogPath() string {
+	return path.Join(GetCurrentDirectory(), LitmusCatalogDir)
+}
+
 // GetMetricDirectory
 func GetMetricDirectory() string {
 	if metricPath != "" {
@@ -21,11 +21,15 @@ import (
 	"bytes"
 	"compress/gzip"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"runtime"
 	"strings"
 )
 
@@ -35,7 +39,8 @@ func IsExist(fileName string) bool {
 	return err == nil || os.IsExist(err)
 }
 
-// DeCompressTgz
+// DeCompressTgz extracts the tar.gz file into destPath. The directories, regular files and links
+// are kept with their modes, the entries and the links which escape from destPath are rejected.
 func DeCompressTgz(tarFile, destPath string) error {
 	file, err := os.Open(tarFile)
 	if err != nil {
@@ -44,42 +49,133 @@ func DeCompressTgz(tarFile, destPath string) error {
 	defer file.Close()
 	reader, err := gzip.NewReader(file)
 	if err != nil {
-		return nil
+		return err
 	}
 	defer reader.Close()
+	if err := os.MkdirAll(destPath, 0o755); err != nil {
+		return err
+	}
+	root, err := filepath.EvalSymlinks(destPath)
+	if err != nil {
+		return err
+	}
 	tarReader := tar.NewReader(reader)
 	for {
-		next, err := tarReader.Next()
+		header, err := tarReader.Next()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
 			return err
 		}
-		fileName := destPath + "/" + next.Name
-		err = createFile(fileName, tarReader)
+		if err := extractEntry(root, header, tarReader); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractEntry(root string, header *tar.Header, reader *tar.Reader) error {
+	if header.Typeflag == tar.TypeXGlobalHeader {
+		return nil
+	}
+	name, ok := cleanTarName(header.Name)
+	if !ok {
+		return fmt.Errorf("illegal file name in tar: %s", header.Name)
+	}
+	// the entries are never written through the links, so that the links are checked by their names
+	if err := checkNoLinks(root, filepath.Dir(name)); err != nil {
+		return err
+	}
+	fileName := filepath.Join(root, name)
+	mode := os.FileMode(header.Mode).Perm()
+	if header.Typeflag == tar.TypeDir {
+		if err := os.MkdirAll(fileName, 0o755); err != nil {
+			return err
+		}
+		return os.Chmod(fileName, mode|0o700)
+	}
+	if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+		return err
+	}
+	switch header.Typeflag {
+	case tar.TypeReg:
+		return createFile(fileName, reader, mode)
+	case tar.TypeSymlink:
+		// the unclean targets like a/../.. are resolved through the links by the system
+		target := header.Linkname
+		if filepath.IsAbs(target) || path.Clean(target) != strings.TrimSuffix(target, "/") ||
+			!isInside(root, filepath.Join(filepath.Dir(fileName), filepath.FromSlash(target))) {
+			return fmt.Errorf("link %s in tar points outside, target: %s", header.Name, header.Linkname)
+		}
+		os.Remove(fileName)
+		return os.Symlink(target, fileName)
+	case tar.TypeLink:
+		target, ok := cleanTarName(header.Linkname)
+		if !ok {
+			return fmt.Errorf("link %s in tar points outside, target: %s", header.Name, header.Linkname)
+		}
+		if err := checkNoLinks(root, filepath.Dir(target)); err != nil {
+			return err
+		}
+		os.Remove(fileName)
+		return os.Link(filepath.Join(root, target), fileName)
+	default:
+		return fmt.Errorf("unsupported type %q of %s in tar", header.Typeflag, header.Name)
+	}
+}
+
+// cleanTarName returns the relative path of the tar entry, ok is false if it escapes
+func cleanTarName(name string) (string, bool) {
+	name = path.Clean(name)
+	if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	return filepath.FromSlash(name), true
+}
+
+// checkNoLinks returns an error if any existing directory of dir under root is a link
+func checkNoLinks(root, dir string) error {
+	current := root
+	for _, part := range strings.Split(dir, string(filepath.Separator)) {
+		if part == "" || part == "." {
+			continue
+		}
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			return nil
+		}
 		if err != nil {
 			return err
 		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s in tar is a link, the entries through it are refused", part)
+		}
 	}
 	return nil
 }
 
-func createFile(fileName string, reader *tar.Reader) error {
-	err := os.MkdirAll(string([]rune(fileName)[0:strings.LastIndex(fileName, "/")]), 0o755)
+func isInside(root, fileName string) bool {
+	rel, err := filepath.Rel(root, fileName)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func createFile(fileName string, reader io.Reader, mode os.FileMode) error {
+	os.Remove(fileName)
+	fw, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
 	if err != nil {
 		return err
 	}
-	if fileName[len(fileName)-1:] == "/" {
-		return nil
+	if _, err := io.Copy(fw, reader); err != nil {
+		fw.Close()
+		return err
 	}
-	fw, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY, 0o755)
-	if err != nil {
+	if err := fw.Close(); err != nil {
 		return err
 	}
-	defer fw.Close()
-	_, err = io.Copy(fw, reader)
-	return err
+	// the mode of the created file is masked by umask
+	return os.Chmod(fileName, mode)
 }
 
 // 校验 md5
@@ -95,6 +191,24 @@ func CheckMd5(filePath, md5sum string) (bool, error) {
 	return false, errors.New("md5 not equal")
 }
 
+// CheckSha256 returns true if the sha256 checksum of file equals sha256sum
+func CheckSha256(filePath, sha256sum string) (bool, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return false, err
+	}
+	if fmt.Sprintf("%x", h.Sum(nil)) != strings.ToLower(sha256sum) {
+		return false, errors.New("sha256 not equal")
+	}
+	return true, nil
+}
+
 // 获取文件 md5
 func Md5sum(filePath string) (string, error) {
 	f, err := os.Open(filePath)
@@ -146,3 +260,67 @@ func DecompressByGzip(body []byte) (string, error) {
 	zr.Close()
 	return buf.String(), nil
 }
+
+// WriteFileAtomic writes the content to a unique temporary file in the same directory, syncs and
+// renames it to fileName, so that the readers never see a partial file, even after a crash.
+// The directory is created if absent.
+func WriteFileAtomic(fileName string, content []byte, perm os.FileMode) error {
+	dir := filepath.Dir(fileName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), fileName); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir persists the directory entries, the directories can't be synced on windows
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// LoadJsonFile unmarshals the json file into v, loaded is false if the file doesn't exist.
+// The broken file is moved to .corrupt so that it's not overwritten, and an error is returned.
+func LoadJsonFile(fileName string, v interface{}) (loaded bool, err error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		os.Rename(fileName, fileName+".corrupt")
+		return false, fmt.Errorf("%s is broken, moved to .corrupt, %s", fileName, err.Error())
+	}
+	return true, nil
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "sub", "state.json")
+	if err := WriteFileAtomic(file, []byte("first"), 0o600); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+	if err := WriteFileAtomic(file, []byte("second"), 0o600); err != nil {
+		t.Fatalf("WriteFileAtomic() error = %v", err)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil || string(content) != "second" {
+		t.Fatalf("content = %q, %v, want second", content, err)
+	}
+	if info, _ := os.Stat(file); runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(file))
+	if len(entries) != 1 {
+		t.Errorf("entries = %d, want only the target file", len(entries))
+	}
+}
+
+func writeTgz(t *testing.T, headers []*tar.Header) string {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, header := range headers {
+		content := header.Linkname
+		if header.Typeflag == tar.TypeReg {
+			header.Linkname, header.Size = "", int64(len(content))
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tw.Close()
+	zw.Close()
+	file := filepath.Join(t.TempDir(), "package.tar.gz")
+	if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestDeCompressTgz(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("links are not supported")
+	}
+	// the content of the regular files is passed by Linkname
+	tgz := writeTgz(t, []*tar.Header{
+		{Name: "chaosblade/", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "chaosblade/blade", Typeflag: tar.TypeReg, Mode: 0o755, Linkname: "blade"},
+		{Name: "chaosblade/yaml/spec.yaml", Typeflag: tar.TypeReg, Mode: 0o644, Linkname: "spec"},
+		{Name: "chaosblade/bin/current", Typeflag: tar.TypeSymlink, Linkname: "../yaml"},
+		{Name: "chaosblade/bin/blade", Typeflag: tar.TypeLink, Linkname: "chaosblade/blade"},
+	})
+	dest := t.TempDir()
+	if err := DeCompressTgz(tgz, dest); err != nil {
+		t.Fatalf("DeCompressTgz() error = %v", err)
+	}
+	modes := map[string]os.FileMode{"chaosblade/blade": 0o755, "chaosblade/yaml/spec.yaml": 0o644}
+	for name, want := range modes {
+		if info, err := os.Stat(filepath.Join(dest, name)); err != nil || info.Mode().Perm() != want {
+			t.Errorf("mode of %s = %v, %v, want %v", name, info.Mode().Perm(), err, want)
+		}
+	}
+	if target, err := os.Readlink(filepath.Join(dest, "chaosblade/bin/current")); err != nil || target != "../yaml" {
+		t.Errorf("symlink = %s, %v, want ../yaml", target, err)
+	}
+	if content, err := os.ReadFile(filepath.Join(dest, "chaosblade/bin/blade")); err != nil || string(content) != "blade" {
+		t.Errorf("hard link content = %s, %v, want blade", content, err)
+	}
+
+	escapes := map[string][]*tar.Header{
+		"path":             {{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0o644}},
+		"relative symlink": {{Name: "etc", Typeflag: tar.TypeSymlink, Linkname: "../../etc"}},
+		"absolute symlink": {{Name: "etc", Typeflag: tar.TypeSymlink, Linkname: "/etc"}},
+		"hard link":        {{Name: "passwd", Typeflag: tar.TypeLink, Linkname: "../../etc/passwd"}},
+		"through symlink": {
+			{Name: "self", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "self/self/self/etc", Typeflag: tar.TypeSymlink, Linkname: "../../../etc"},
+		},
+		"dangling symlink": {
+			{Name: "dir", Typeflag: tar.TypeSymlink, Linkname: "missing"},
+			{Name: "dir/file", Typeflag: tar.TypeReg, Mode: 0o644},
+		},
+		"unclean symlink": {
+			{Name: "self", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "etc", Typeflag: tar.TypeSymlink, Linkname: "self/../../etc"},
+		},
+		"device": {{Name: "null", Typeflag: tar.TypeChar, Mode: 0o666}},
+	}
+	for name, headers := range escapes {
+		t.Run(name, func(t *testing.T) {
+			if err := DeCompressTgz(writeTgz(t, headers), t.TempDir()); err == nil {
+				t.Errorf("DeCompressTgz() = nil, want error")
+			} else {
+				t.Log(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procDir is where the processes are read, only linux is supported
+const procDir = "/proc"
+
+// FindProcesses returns the pids of the processes whose name is name or whose command line contains it,
+// the agent itself is excluded
+func FindProcesses(name string) []int {
+	pids := make([]int, 0)
+	dirs, err := filepath.Glob(filepath.Join(procDir, "[0-9]*"))
+	if err != nil {
+		return pids
+	}
+	self := os.Getpid()
+	for _, dir := range dirs {
+		pid, err := strconv.Atoi(filepath.Base(dir))
+		if err != nil || pid == self {
+			continue
+		}
+		if comm, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil && strings.TrimSpace(string(comm)) == name {
+			pids = append(pids, pid)
+			continue
+		}
+		if cmdline := ProcessCmdline(pid); cmdline != "" && strings.Contains(cmdline, name) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// ProcessAlive returns true if the process of the pid exists
+func ProcessAlive(pid int) bool {
+	return IsExist(filepath.Join(procDir, strconv.Itoa(pid)))
+}
+
+// ProcessCmdline returns the command line of the process joined by spaces, or empty string if not found
+func ProcessCmdline(pid int) string {
+	cmdline, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " "))
+}
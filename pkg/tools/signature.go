@@ -0,0 +1,50 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// VerifySignature verifies the base64 ed25519 signature of file by the base64 public key in keyFile
+func VerifySignature(file, signature, keyFile string) error {
+	keyBytes, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("read verify key failed, %v", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(keyBytes)))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("%s is not a base64 ed25519 public key", keyFile)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("signature is not base64 encoded, %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), data, sig) {
+		return errors.New("verify signature failed")
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// safePointCheckInterval is how often the in-flight operations are checked while waiting for the safe point
+const safePointCheckInterval = 200 * time.Millisecond
+
+var (
+	mutex     sync.Mutex
+	inFlight  int
+	upgrading bool
+)
+
+// Enter marks an experiment operation in flight, release must be called once it's finished.
+// It's refused while the agent is upgrading.
+func Enter() (release func(), ok bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if upgrading {
+		return nil, false
+	}
+	inFlight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+			inFlight--
+		})
+	}, true
+}
+
+// EnterWait is Enter which waits up to timeout for the upgrade in progress, it's used by the
+// operations which should not be dropped, eg: destroying the experiments
+func EnterWait(timeout time.Duration) (release func(), ok bool) {
+	deadline := time.Now().Add(timeout)
+	for {
+		if release, ok := Enter(); ok {
+			return release, true
+		}
+		if !time.Now().Before(deadline) {
+			return nil, false
+		}
+		time.Sleep(safePointCheckInterval)
+	}
+}
+
+// NeedWait returns true if the agent is upgrading
+func NeedWait() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return upgrading
+}
+
+// InFlight returns the number of in-flight experiment operations
+func InFlight() int {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return inFlight
+}
+
+// Begin refuses the new experiment operations and waits until the in-flight ones are finished,
+// the operations are accepted again if the safe point is not reached in timeout
+func Begin(timeout time.Duration) error {
+	mutex.Lock()
+	if upgrading {
+		mutex.Unlock()
+		return fmt.Errorf("agent is upgrading")
+	}
+	upgrading = true
+	mutex.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		remaining := InFlight()
+		if remaining == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			End()
+			return fmt.Errorf("%d experiment operations are still in flight after %s", remaining, timeout)
+		}
+		time.Sleep(safePointCheckInterval)
+	}
+}
+
+// End accepts the experiment operations again
+func End() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	upgrading = false
+}
@@ -0,0 +1,360 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+)
+
+const (
+	// GuardCommand is the hidden command which runs the upgrade guardian
+	GuardCommand = "upgrade-guard"
+
+	// StatusSwitching means the binary is switched and the new agent is not confirmed yet
+	StatusSwitching = "switching"
+	// StatusConfirmed means the new agent is healthy
+	StatusConfirmed = "confirmed"
+	// StatusRolledBack means the new agent is not healthy in time and the old binary is restored
+	StatusRolledBack = "rolledBack"
+
+	newSuffix    = ".new"
+	backupSuffix = ".bak"
+	failedSuffix = ".failed"
+	stateSuffix  = ".upgrade"
+	logSuffix    = ".log"
+
+	// exitTimeout is how long the guardian waits for the old agent to exit
+	exitTimeout   = time.Minute
+	checkInterval = time.Second
+)
+
+// the steps reported by the upgrade callback
+const (
+	StepSucceeded  = 0
+	StepFailed     = 1
+	StepRolledBack = 2
+	StepDownloaded = 3
+	StepSafePoint  = 4
+	StepSwitched   = 5
+)
+
+// running is 1 while an upgrade is in progress
+var running int32
+
+// State is shared by the old agent, the guardian and the new agent through the state file
+type State struct {
+	Status     string        `json:"status"`
+	OldVersion string        `json:"oldVersion"`
+	NewVersion string        `json:"newVersion"`
+	Binary     string        `json:"binary"`
+	Args       []string      `json:"args"`
+	OldPid     int           `json:"oldPid"`
+	Timeout    time.Duration `json:"timeout"`
+	Message    string        `json:"message,omitempty"`
+}
+
+// StateFile returns the state file of the upgrade of binary
+func StateFile(binary string) string {
+	return binary + stateSuffix
+}
+
+func LoadState(file string) (*State, error) {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(bytes, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Save writes the state atomically
+func (s *State) Save(file string) error {
+	bytes, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return tools.WriteFileAtomic(file, bytes, 0o600)
+}
+
+// Package is the new agent binary
+type Package struct {
+	Url     string
+	Sha256  string
+	Version string
+	// Signature is the base64 ed25519 signature of the binary
+	Signature string
+}
+
+// Upgrader replaces the agent binary. The old agent downloads and verifies the new binary, waits for the safe
+// point, switches the binaries and starts the guardian with the old binary, then exits. The guardian starts
+// the new agent and rolls back if the new agent doesn't confirm itself in time.
+type Upgrader struct {
+	binary string
+	config options.UpgradeConfig
+}
+
+func NewUpgrader(binary string, config options.UpgradeConfig) *Upgrader {
+	return &Upgrader{
+		binary: binary,
+		config: config,
+	}
+}
+
+// Start checks the package and upgrades asynchronously, each step is reported by report
+func (u *Upgrader) Start(pkg Package, report func(step int, message string)) error {
+	if !supported {
+		return errors.New("self upgrade is not supported on this platform")
+	}
+	if u.binary == "" {
+		return errors.New("agent binary path is unknown")
+	}
+	if u.config.VerifyKey == "" {
+		return errors.New("agent.verify.key is not configured, unsigned upgrade is refused")
+	}
+	if pkg.Url == "" || pkg.Sha256 == "" || pkg.Signature == "" {
+		return errors.New("url, sha256 and signature of the agent binary are required")
+	}
+	if !atomic.CompareAndSwapInt32(&running, 0, 1) {
+		return errors.New("another upgrade is in progress")
+	}
+	go func() {
+		defer tools.PanicPrintStack()
+		if err := u.upgrade(pkg, report); err != nil {
+			atomic.StoreInt32(&running, 0)
+			logrus.Warningf("[upgrade] upgrade agent to %s failed, err: %v", pkg.Version, err)
+			report(StepFailed, err.Error())
+		}
+	}()
+	return nil
+}
+
+func (u *Upgrader) upgrade(pkg Package, report func(step int, message string)) error {
+	newFile := u.binary + newSuffix
+	defer os.Remove(newFile)
+	logrus.Infof("[upgrade] download agent %s from %s", pkg.Version, pkg.Url)
+	if err := tools.Download(newFile, pkg.Url); err != nil {
+		return fmt.Errorf("download agent binary failed, %v", err)
+	}
+	if _, err := tools.CheckSha256(newFile, pkg.Sha256); err != nil {
+		return fmt.Errorf("verify agent binary failed, %v", err)
+	}
+	if err := tools.VerifySignature(newFile, pkg.Signature, u.config.VerifyKey); err != nil {
+		return fmt.Errorf("verify agent binary failed, %v", err)
+	}
+	if err := os.Chmod(newFile, 0o755); err != nil {
+		return err
+	}
+	report(StepDownloaded, fmt.Sprintf("agent %s is downloaded and verified", pkg.Version))
+
+	if err := Begin(u.config.SafePointTimeout); err != nil {
+		return fmt.Errorf("wait for safe point failed, %v", err)
+	}
+	switched := false
+	defer func() {
+		if !switched {
+			End()
+		}
+	}()
+	report(StepSafePoint, "no experiment operation in flight")
+
+	backup := u.binary + backupSuffix
+	if err := os.Rename(u.binary, backup); err != nil {
+		return fmt.Errorf("backup agent binary failed, %v", err)
+	}
+	if err := os.Rename(newFile, u.binary); err != nil {
+		u.restore(backup)
+		return fmt.Errorf("switch agent binary failed, %v", err)
+	}
+	state := &State{
+		Status:     StatusSwitching,
+		OldVersion: options.Opts.Version,
+		NewVersion: pkg.Version,
+		Binary:     u.binary,
+		Args:       os.Args[1:],
+		OldPid:     os.Getpid(),
+		Timeout:    u.config.HealthTimeout,
+	}
+	file := StateFile(u.binary)
+	if err := state.Save(file); err != nil {
+		u.restore(backup)
+		return fmt.Errorf("save upgrade state failed, %v", err)
+	}
+	// the old binary is known good, so the guardian runs with it
+	if _, err := startDetached(backup, []string{GuardCommand, file}); err != nil {
+		u.restore(backup)
+		os.Remove(file)
+		return fmt.Errorf("start upgrade guardian failed, %v", err)
+	}
+	switched = true
+	report(StepSwitched, fmt.Sprintf("agent binary is switched to %s, restarting", pkg.Version))
+	logrus.Warningf("[upgrade] agent binary is switched to %s, exit for restarting", pkg.Version)
+	terminate()
+	return nil
+}
+
+// restore moves the backup binary back
+func (u *Upgrader) restore(backup string) {
+	if err := os.Rename(backup, u.binary); err != nil {
+		logrus.Errorf("[upgrade] restore agent binary from %s failed, err: %v", backup, err)
+	}
+}
+
+// Guard runs in the guardian process, it waits for the old agent to exit, starts the new agent and
+// rolls back if the new agent doesn't confirm itself in time. Returns the exit code.
+func Guard(file string) int {
+	if logFile, err := os.OpenFile(file+logSuffix, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600); err == nil {
+		logrus.SetOutput(logFile)
+		defer logFile.Close()
+	}
+	state, err := LoadState(file)
+	if err != nil {
+		logrus.Errorf("[upgrade guard] load upgrade state failed, err: %v", err)
+		return 1
+	}
+
+	// wait for the old agent to exit
+	deadline := time.Now().Add(exitTimeout)
+	for processAlive(state.OldPid) {
+		if time.Now().After(deadline) {
+			logrus.Warningf("[upgrade guard] agent %d doesn't exit in %s, kill it", state.OldPid, exitTimeout)
+			killProcess(state.OldPid)
+			break
+		}
+		time.Sleep(checkInterval)
+	}
+
+	cmd, err := startDetached(state.Binary, UpgradeArgs(state.Args))
+	if err != nil {
+		return rollback(state, file, fmt.Sprintf("start new agent failed, %v", err))
+	}
+	logrus.Infof("[upgrade guard] new agent %s started, pid: %d", state.NewVersion, cmd.Process.Pid)
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	timeout := time.After(state.Timeout)
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-exited:
+			return rollback(state, file, "new agent exited before healthy")
+		case <-timeout:
+			killProcess(cmd.Process.Pid)
+			return rollback(state, file, fmt.Sprintf("new agent is not healthy in %s", state.Timeout))
+		case <-ticker.C:
+			if current, err := LoadState(file); err == nil && current.Status == StatusConfirmed {
+				logrus.Infof("[upgrade guard] new agent %s is healthy", state.NewVersion)
+				os.Remove(file)
+				return 0
+			}
+		}
+	}
+}
+
+// rollback restores the old binary and starts it, the old agent reports the rollback once it's started
+func rollback(state *State, file, message string) int {
+	logrus.Warningf("[upgrade guard] roll back to %s, reason: %s", state.OldVersion, message)
+	if err := os.Rename(state.Binary, state.Binary+failedSuffix); err != nil {
+		logrus.Warningf("[upgrade guard] move failed binary away failed, err: %v", err)
+	}
+	if err := os.Rename(state.Binary+backupSuffix, state.Binary); err != nil {
+		logrus.Errorf("[upgrade guard] restore old binary failed, err: %v", err)
+		return 1
+	}
+	state.Status = StatusRolledBack
+	state.Message = message
+	if err := state.Save(file); err != nil {
+		logrus.Warningf("[upgrade guard] save upgrade state failed, err: %v", err)
+	}
+	if _, err := startDetached(state.Binary, UpgradeArgs(state.Args)); err != nil {
+		logrus.Errorf("[upgrade guard] start old agent failed, err: %v", err)
+		return 1
+	}
+	return 1
+}
+
+// Resume finishes the upgrade which restarted this agent. The new agent confirms itself once ready,
+// the agent started by rollback reports the failure.
+func Resume(binary string, ready func() bool, report func(state *State)) {
+	file := StateFile(binary)
+	state, err := LoadState(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logrus.Warningf("[upgrade] load upgrade state failed, err: %v", err)
+		}
+		return
+	}
+	if state.Status != StatusSwitching {
+		os.Remove(file)
+		if state.Status == StatusRolledBack {
+			report(state)
+		}
+		return
+	}
+	go func() {
+		defer tools.PanicPrintStack()
+		deadline := time.Now().Add(state.Timeout)
+		for !ready() {
+			if time.Now().After(deadline) {
+				logrus.Warningf("[upgrade] agent %s is not ready in %s", state.NewVersion, state.Timeout)
+				return
+			}
+			time.Sleep(checkInterval)
+		}
+		state.Status = StatusConfirmed
+		if err := state.Save(file); err != nil {
+			logrus.Warningf("[upgrade] confirm upgrade failed, err: %v", err)
+			return
+		}
+		logrus.Infof("[upgrade] upgrade from %s to %s is confirmed", state.OldVersion, state.NewVersion)
+		report(state)
+	}()
+}
+
+// UpgradeArgs returns the agent arguments with the upgrade startup mode
+func UpgradeArgs(args []string) []string {
+	upgradeArgs := make([]string, 0, len(args)+1)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--startup.mode" {
+			i++
+			continue
+		}
+		if strings.HasPrefix(arg, "--startup.mode=") {
+			continue
+		}
+		upgradeArgs = append(upgradeArgs, arg)
+	}
+	return append(upgradeArgs, "--startup.mode="+options.StartUpgradeMode)
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSafePoint(t *testing.T) {
+	release, ok := Enter()
+	if !ok {
+		t.Fatalf("Enter() refused before upgrade")
+	}
+	if err := Begin(300 * time.Millisecond); err == nil {
+		t.Fatalf("Begin() with an operation in flight, want error")
+	}
+	if NeedWait() {
+		t.Fatalf("NeedWait() after Begin() timeout, want false")
+	}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		release()
+		// released twice is counted once
+		release()
+	}()
+	if err := Begin(time.Second); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if _, ok := Enter(); ok {
+		t.Errorf("Enter() accepted while upgrading")
+	}
+	if InFlight() != 0 {
+		t.Errorf("InFlight() = %d, want 0", InFlight())
+	}
+	End()
+	release, ok = Enter()
+	if !ok {
+		t.Fatalf("Enter() refused after End()")
+	}
+	release()
+}
+
+func TestEnterWait(t *testing.T) {
+	if err := Begin(time.Second); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if _, ok := EnterWait(300 * time.Millisecond); ok {
+		t.Fatalf("EnterWait() accepted while upgrading")
+	}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		End()
+	}()
+	release, ok := EnterWait(time.Second)
+	if !ok {
+		t.Fatalf("EnterWait() refused after End()")
+	}
+	release()
+}
+
+func TestUpgradeArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "no startup mode",
+			args: []string{"--port", "19527"},
+			want: []string{"--port", "19527", "--startup.mode=upgrade"},
+		},
+		{
+			name: "startup mode with equal sign",
+			args: []string{"--startup.mode=console", "--port", "19527"},
+			want: []string{"--port", "19527", "--startup.mode=upgrade"},
+		},
+		{
+			name: "startup mode with separate value",
+			args: []string{"--startup.mode", "crontab", "--port", "19527"},
+			want: []string{"--port", "19527", "--startup.mode=upgrade"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UpgradeArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UpgradeArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResume(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "chaos_agent")
+	file := StateFile(binary)
+	state := &State{Status: StatusSwitching, OldVersion: "1.0.0", NewVersion: "1.1.0", Timeout: time.Second}
+	if err := state.Save(file); err != nil {
+		t.Fatal(err)
+	}
+
+	reported := make(chan *State, 1)
+	Resume(binary, func() bool { return true }, func(state *State) { reported <- state })
+	select {
+	case got := <-reported:
+		if got.Status != StatusConfirmed {
+			t.Errorf("reported status = %s, want %s", got.Status, StatusConfirmed)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("upgrade is not confirmed")
+	}
+	saved, err := LoadState(file)
+	if err != nil || saved.Status != StatusConfirmed {
+		t.Errorf("saved state = %+v, %v, want confirmed", saved, err)
+	}
+}
@@ -0,0 +1,49 @@
+//go:build !windows
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const supported = true
+
+// startDetached starts the process in a new session, so it survives the exit of the agent
+func startDetached(name string, args []string) (*exec.Cmd, error) {
+	cmd := exec.Command(name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	return cmd, cmd.Start()
+}
+
+func processAlive(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
+
+func killProcess(pid int) {
+	syscall.Kill(pid, syscall.SIGKILL)
+}
+
+// terminate stops the agent gracefully, the shutdown hooks are run
+func terminate() {
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		os.Exit(0)
+	}
+}
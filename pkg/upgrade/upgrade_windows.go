@@ -0,0 +1,38 @@
+//go:build windows
+
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgrade
+
+import (
+	"errors"
+	"os/exec"
+)
+
+const supported = false
+
+func startDetached(name string, args []string) (*exec.Cmd, error) {
+	return nil, errors.New("not supported on windows")
+}
+
+func processAlive(pid int) bool {
+	return false
+}
+
+func killProcess(pid int) {}
+
+func terminate() {}
@@ -28,6 +28,7 @@ const (
 	Cid        = "cid"
 	Pid        = "pid"
 	Uid        = "uid"
+	Rid        = "rid"
 )
 
 const (
@@ -125,6 +126,9 @@ const (
 	HttpHandlerJavaAgentInstall   = "chaos/javaAgentInstall"
 	HttpHandlerJavaAgentUninstall = "chaos/javaAgentUninstall"
 	HttpHandlerAgentEvent         = "chaos/AgentEvent"
+	HttpHandlerDiagnose           = "chaos/AgentDiagnose"
+	HttpHandlerUpgradeCallback    = "chaos/AgentUpgradeCallback"
+	HttpHandlerPreparation        = "chaos/AgentPreparation"
 
 	// k8s metric
 	HttpHandlerK8sVirtualNode = "chaos/k8sVirtualNode"
@@ -153,6 +157,8 @@ const (
 	API_JAVA_INSTALL     = "javaInstall"
 	API_JAVA_UNINSTALL   = "javaUninstall"
 	API_EVENT            = "event"
+	API_DIAGNOSE         = "diagnose"
+	API_PREPARATION      = "preparation"
 )
 
 type Uri struct {
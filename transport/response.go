@@ -32,13 +32,19 @@ type Response struct {
 const (
 	OK = 200
 
-	InvalidTimestamp   = 401
-	Forbidden          = 403
-	HandlerNotFound    = 404
-	TokenNotFound      = 405
-	ParameterEmpty     = 406
-	ParameterLess      = 407
-	ParameterTypeError = 408
+	InvalidTimestamp    = 401
+	Forbidden           = 403
+	HandlerNotFound     = 404
+	TokenNotFound       = 405
+	ParameterEmpty      = 406
+	ParameterLess       = 407
+	ParameterTypeError  = 408
+	JobNotFound         = 409
+	ScheduleNotFound    = 410
+	ScenarioNotFound    = 411
+	IdempotencyConflict = 412
+	RequestNotFound     = 413
+	DuplicateRequest    = 414
 
 	ServerError          = 500
 	ServiceNotOpened     = 501
@@ -49,22 +55,39 @@ const (
 	ServiceNotSupport    = 506
 	CtlFileNotFound      = 507
 	CtlExecFailed        = 508
+	HandlerBusy          = 509
+	QueueTimeout         = 510
+	Upgrading            = 511
+	UpgradeFailed        = 512
+	CommandCancelled     = 513
 
 	ChaosbladeFileNotFound = 600
 	ResultUnmarshalFailed  = 601
 	Helm3ExecError         = 602
+	PolicyDenied           = 603
+	ExperimentInvalid      = 604
+	BladeInstallFailed     = 605
+	ProbeFailed            = 606
+	BlastRadiusExceeded    = 607
+	LitmusCatalogFailed    = 608
 )
 
 var Errors = map[int32]string{
 	OK: "success",
 
-	InvalidTimestamp:   "invalid timestamp",
-	Forbidden:          "forbidden, err: %s",
-	HandlerNotFound:    "request handler not found",
-	TokenNotFound:      "access token not found",
-	ParameterEmpty:     "`%s`: parameter is empty",
-	ParameterLess:      "`%s`: parameter less",
-	ParameterTypeError: "`%s` parameter data error",
+	InvalidTimestamp:    "invalid timestamp",
+	Forbidden:           "forbidden, err: %s",
+	HandlerNotFound:     "request handler not found",
+	TokenNotFound:       "access token not found",
+	ParameterEmpty:      "`%s`: parameter is empty",
+	ParameterLess:       "`%s`: parameter less",
+	ParameterTypeError:  "`%s` parameter data error",
+	JobNotFound:         "`%s`: job not found",
+	ScheduleNotFound:    "`%s`: schedule not found",
+	ScenarioNotFound:    "`%s`: scenario not found",
+	IdempotencyConflict: "`%s`: idempotency key is used by another command",
+	RequestNotFound:     "`%s`: in-flight request not found",
+	DuplicateRequest:    "`%s`: request id is used by an in-flight request",
 
 	ServerError:          "server error, err: %s",
 	ServiceNotOpened:     "chaos service not opened",
@@ -75,10 +98,21 @@ var Errors = map[int32]string{
 	ServiceNotSupport:    "service not support: %s",
 	CtlFileNotFound:      "`%s`: ctl file not found",
 	CtlExecFailed:        "exec ctl file failed: %s",
+	HandlerBusy:          "handler busy, err: %s",
+	QueueTimeout:         "`%s`: wait in queue timeout",
+	Upgrading:            "agent is upgrading, %s",
+	UpgradeFailed:        "upgrade agent failed, %s",
+	CommandCancelled:     "command cancelled, %s",
 
 	ChaosbladeFileNotFound: fmt.Sprintf("%s, chaosblade file not found", options.BladeBinPath),
 	ResultUnmarshalFailed:  "`%s`: exec result unmarshal failed, err: %s",
 	Helm3ExecError:         "helm3 exec error, err: %s",
+	PolicyDenied:           "denied by safety policy, %s",
+	ExperimentInvalid:      "invalid experiment, %s",
+	BladeInstallFailed:     "install chaosblade failed, %s",
+	ProbeFailed:            "steady state probe failed, %s",
+	BlastRadiusExceeded:    "blast radius exceeded, %s",
+	LitmusCatalogFailed:    "litmus catalog failed, %s",
 }
 
 func ReturnFail(errCode int32, args ...interface{}) *Response {
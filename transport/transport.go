@@ -83,12 +83,17 @@ func InitTransprotUri() {
 	TransportUriMap[API_REGISTRY] = NewUri(Chaos, HttpHandlerRegister)
 	TransportUriMap[API_HEARTBEAT] = NewUri(Chaos, HttpHandlerHeartbeat)
 	TransportUriMap[API_CLOSE] = NewUri(Chaos, HttpHandlerClose)
+	TransportUriMap[API_UPGRADE_CALLBACK] = NewUri(Chaos, HttpHandlerUpgradeCallback)
 	TransportUriMap[API_CHAOSBLADE_ASYNC] = NewUri(Chaos, MKChaosbladeAsync)
 
 	TransportUriMap[API_JAVA_INSTALL] = NewUri(Chaos, HttpHandlerJavaAgentInstall)
 	TransportUriMap[API_JAVA_UNINSTALL] = NewUri(Chaos, HttpHandlerJavaAgentUninstall)
+	TransportUriMap[API_PREPARATION] = NewUri(Chaos, HttpHandlerPreparation)
 
 	TransportUriMap[API_K8S_POD] = NewUri(Chaos, HttpHandlerK8sPod)
+
+	TransportUriMap[API_DIAGNOSE] = NewUri(Chaos, HttpHandlerDiagnose)
+	TransportUriMap[API_EVENT] = NewUri(Chaos, HttpHandlerAgentEvent)
 }
 
 func BuildInterceptor() RequestInterceptor {
@@ -123,7 +128,7 @@ func (tc *TransportClient) Invoke(uri Uri, request *Request, needInterceptor boo
 	// set requestId
 
 	requestId := tools.GetUUID()
-	request.AddHeader("rid", requestId)
+	request.AddHeader(Rid, requestId)
 	uri.RequestId = requestId
 
 	// encode
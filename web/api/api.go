@@ -17,6 +17,7 @@
 package api
 
 import (
+	"github.com/chaosblade-io/chaos-agent/metricreport"
 	"github.com/chaosblade-io/chaos-agent/pkg/helm3"
 	"github.com/chaosblade-io/chaos-agent/pkg/kubernetes"
 	"github.com/chaosblade-io/chaos-agent/transport"
@@ -29,48 +30,126 @@ import (
 type API struct {
 	chaosweb.APiServer
 	// ready func(http.HandlerFunc) http.HandlerFunc
+
+	// Chaosblade is shared with the local admin server
+	Chaosblade *handler.ChaosbladeHandler
+	// Schedule runs the local experiment schedules
+	Schedule *handler.ScheduleHandler
 }
 
 // community just use http
 func NewAPI() *API {
 	return &API{
-		server.NewHttpServer(),
+		APiServer: server.NewHttpServer(),
 	}
 }
 
-func (api *API) Register(transportClient *transport.TransportClient, k8sInstance *kubernetes.Channel, helm *helm3.Helm) error {
-	chaosbladeHandler := NewServerRequestHandler(handler.NewChaosbladeHandler(transportClient))
+func (api *API) Register(transportClient *transport.TransportClient, k8sInstance *kubernetes.Channel, helm *helm3.Helm,
+	reportMetricConfigMap *metricreport.ReportMetricConfigMap,
+) error {
+	api.Chaosblade = handler.NewChaosbladeHandler(transportClient)
+	chaosbladeHandler := NewServerRequestHandler("chaosblade", api.Chaosblade)
 	if err := api.RegisterHandler("chaosblade", chaosbladeHandler); err != nil {
 		return err
 	}
 
-	pingHandler := NewServerRequestHandler(handler.NewPingHandler())
+	jobHandler := NewServerRequestHandler("job", handler.NewJobHandler(api.Chaosblade.Jobs()))
+	if err := api.RegisterHandler("job", jobHandler); err != nil {
+		return err
+	}
+
+	cancelHandler := NewServerRequestHandler("cancel", handler.NewCancelHandler(api.Chaosblade))
+	if err := api.RegisterHandler("cancel", cancelHandler); err != nil {
+		return err
+	}
+
+	api.Schedule = handler.NewScheduleHandler(api.Chaosblade, transportClient)
+	scheduleHandler := NewServerRequestHandler("schedule", api.Schedule)
+	if err := api.RegisterHandler("schedule", scheduleHandler); err != nil {
+		return err
+	}
+
+	scenarioHandler := NewServerRequestHandler("scenario", handler.NewScenarioHandler(api.Chaosblade, transportClient))
+	if err := api.RegisterHandler("scenario", scenarioHandler); err != nil {
+		return err
+	}
+
+	preflightHandler := NewServerRequestHandler("preflight", handler.NewPreflightHandler(k8sInstance))
+	if err := api.RegisterHandler("preflight", preflightHandler); err != nil {
+		return err
+	}
+
+	pingHandler := NewServerRequestHandler("ping", handler.NewPingHandler())
 	if err := api.RegisterHandler("ping", pingHandler); err != nil {
 		return err
 	}
 
-	uninstallHandler := NewServerRequestHandler(handler.NewUninstallInstallHandler(transportClient))
+	uninstallHandler := NewServerRequestHandler("uninstall", handler.NewUninstallInstallHandler(transportClient))
 	if err := api.RegisterHandler("uninstall", uninstallHandler); err != nil {
 		return err
 	}
 
-	updateApplicationHandler := NewServerRequestHandler(handler.NewUpdateApplicationHandler())
+	updateApplicationHandler := NewServerRequestHandler("updateApplication", handler.NewUpdateApplicationHandler())
 	if err := api.RegisterHandler("updateApplication", updateApplicationHandler); err != nil {
 		return err
 	}
 
+	installBladeHandler := NewServerRequestHandler("installBlade", handler.NewInstallBladeHandler(api.Chaosblade))
+	if err := api.RegisterHandler("installBlade", installBladeHandler); err != nil {
+		return err
+	}
+
+	upgradeHandler := NewServerRequestHandler("upgrade", handler.NewUpgradeHandler(transportClient))
+	if err := api.RegisterHandler("upgrade", upgradeHandler); err != nil {
+		return err
+	}
+
+	healthHandler := NewServerRequestHandler("health", handler.NewHealthHandler())
+	if err := api.RegisterHandler("health", healthHandler); err != nil {
+		return err
+	}
+
+	diagnoseHandler := NewServerRequestHandler("diagnose", handler.NewDiagnoseHandler(transportClient, api.Chaosblade, reportMetricConfigMap))
+	if err := api.RegisterHandler("diagnose", diagnoseHandler); err != nil {
+		return err
+	}
+
+	logLevelHandler := NewServerRequestHandler("logLevel", handler.NewLogLevelHandler())
+	if err := api.RegisterHandler("logLevel", logLevelHandler); err != nil {
+		return err
+	}
+
+	logTailHandler := NewServerRequestHandler("logTail", handler.NewLogTailHandler())
+	if err := api.RegisterHandler("logTail", logTailHandler); err != nil {
+		return err
+	}
+
+	if err := api.RegisterStreamHandler("stream", NewServerStreamHandler("stream")); err != nil {
+		return err
+	}
+
+	auditHandler := NewServerRequestHandler("audit", handler.NewAuditHandler())
+	if err := api.RegisterHandler("audit", auditHandler); err != nil {
+		return err
+	}
+
 	// litmus
-	litmuschaosHandler := NewServerRequestHandler(litmuschaos.NewLitmusChaosHandler(transportClient, k8sInstance))
+	litmuschaosHandler := NewServerRequestHandler("litmuschaos", litmuschaos.NewLitmusChaosHandler(transportClient, k8sInstance))
 	if err := api.RegisterHandler("litmuschaos", litmuschaosHandler); err != nil {
 		return err
 	}
 
-	installlitmusHandler := NewServerRequestHandler(litmuschaos.NewInstallLitmusHandler(helm))
+	litmusCatalogHandler := NewServerRequestHandler("litmusCatalog", litmuschaos.NewLitmusCatalogHandler())
+	if err := api.RegisterHandler("litmusCatalog", litmusCatalogHandler); err != nil {
+		return err
+	}
+
+	installlitmusHandler := NewServerRequestHandler("installLitmus", litmuschaos.NewInstallLitmusHandler(helm))
 	if err := api.RegisterHandler("installLitmus", installlitmusHandler); err != nil {
 		return err
 	}
 
-	uninstalllitmusHandler := NewServerRequestHandler(litmuschaos.NewUninstallLitmusHandler(helm))
+	uninstalllitmusHandler := NewServerRequestHandler("uninstallLitmus", litmuschaos.NewUninstallLitmusHandler(helm))
 	if err := api.RegisterHandler("uninstallLitmus", uninstalllitmusHandler); err != nil {
 		return err
 	}
@@ -19,34 +19,54 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/limiter"
+	"github.com/chaosblade-io/chaos-agent/pkg/log"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
 	"github.com/chaosblade-io/chaos-agent/transport"
 	"github.com/chaosblade-io/chaos-agent/web"
 )
 
 type ServerRequestHandler struct {
+	Name        string
 	Interceptor transport.RequestInterceptor
 	Handler     web.ApiHandler
+	Limiter     *limiter.Limiter
 	Ctx         context.Context
 }
 
-func NewServerRequestHandler(handler web.ApiHandler) *ServerRequestHandler {
+func NewServerRequestHandler(name string, handler web.ApiHandler) *ServerRequestHandler {
 	if handler == nil {
 		return nil
 	}
 
 	return &ServerRequestHandler{
+		Name:        name,
 		Interceptor: transport.BuildInterceptor(),
 		Handler:     handler,
+		Limiter:     newHandlerLimiter(name),
 		Ctx:         context.Background(),
 	}
 }
 
-// handle(request string) (string, error)
-func (handler *ServerRequestHandler) Handle(request string) (string, error) {
+// newHandlerLimiter returns nil if the concurrency of handler is not limited
+func newHandlerLimiter(name string) *limiter.Limiter {
+	cfg := options.Opts.ConcurrencyConfig
+	concurrency, ok := cfg.HandlerLimits[name]
+	if !ok || concurrency <= 0 {
+		return nil
+	}
+	return limiter.New(name, concurrency, cfg.QueueSize, cfg.QueueTimeout)
+}
+
+// handle(request, remoteAddr string) (string, error)
+func (handler *ServerRequestHandler) Handle(request, remoteAddr string) (string, error) {
 	handleStartTime := time.Now()
 	logrus.Infof("[ServerRequestHandler] Handle() called at %v, request length: %d", handleStartTime, len(request))
 	var response *transport.Response
@@ -68,12 +88,9 @@ func (handler *ServerRequestHandler) Handle(request string) (string, error) {
 
 		//拦截器先拦截，允许之后再执行
 		if response, allow = handler.Interceptor.Handle(req); allow {
-			handlerStartTime := time.Now()
-			logrus.Infof("[ServerRequestHandler] Calling Handler.Handle() at %v, time since handle start: %v", handlerStartTime, time.Since(handleStartTime))
-			response = handler.Handler.Handle(req)
-			handlerDuration := time.Since(handlerStartTime)
-			logrus.Infof("[ServerRequestHandler] Handler.Handle completed, duration: %v, time since handle start: %v", handlerDuration, time.Since(handleStartTime))
+			response = handler.handleWithLimit(req, handleStartTime)
 		}
+		handler.audit(req, remoteAddr, allow, response, handleStartTime)
 	}
 	// encode
 	encodeStartTime := time.Now()
@@ -86,3 +103,55 @@ func (handler *ServerRequestHandler) Handle(request string) (string, error) {
 	logrus.Debugf("Response encode completed, encode duration: %v, total duration: %v", encodeDuration, totalDuration)
 	return string(bytes), nil
 }
+
+// handleWithLimit waits for a free slot of the handler before handling
+func (handler *ServerRequestHandler) handleWithLimit(req *transport.Request, handleStartTime time.Time) *transport.Response {
+	if err := handler.Limiter.Acquire(); err != nil {
+		logrus.Warningf("[ServerRequestHandler] %s handler acquire slot failed, err: %v", handler.Name, err)
+		if err == limiter.ErrQueueTimeout {
+			return transport.ReturnFail(transport.QueueTimeout, handler.Name)
+		}
+		return transport.ReturnFail(transport.HandlerBusy, fmt.Sprintf("%s, %s", handler.Name, err.Error()))
+	}
+	defer handler.Limiter.Release()
+
+	handlerStartTime := time.Now()
+	entry := log.WithRid(req.Headers[transport.Rid])
+	entry.Infof("[ServerRequestHandler] Calling %s Handler.Handle() at %v, time since handle start: %v", handler.Name, handlerStartTime, time.Since(handleStartTime))
+	response := handler.Handler.Handle(req)
+	handlerDuration := time.Since(handlerStartTime)
+	entry.Infof("[ServerRequestHandler] %s Handler.Handle completed, duration: %v, time since handle start: %v", handler.Name, handlerDuration, time.Since(handleStartTime))
+	return response
+}
+
+// audit records who called which handler with what, and the result
+func (handler *ServerRequestHandler) audit(req *transport.Request, remoteAddr string, allow bool,
+	response *transport.Response, startTime time.Time,
+) {
+	entry := &audit.Entry{
+		Time:       startTime.UnixMilli(),
+		RequestId:  req.Headers[transport.Rid],
+		AccessKey:  req.Headers[transport.AccessKey],
+		SourceIp:   remoteIp(remoteAddr),
+		Handler:    handler.Name,
+		Params:     log.RedactParams(req.Params),
+		DurationMs: time.Since(startTime).Milliseconds(),
+	}
+	if describer, ok := handler.Handler.(web.CommandDescriber); ok && allow {
+		entry.Command = describer.Command(req)
+	}
+	if response != nil {
+		entry.Code = response.Code
+		entry.Success = response.Success
+		entry.Error = response.Error
+	}
+	audit.Record(entry)
+}
+
+func remoteIp(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
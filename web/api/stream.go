@@ -0,0 +1,176 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/log"
+	"github.com/chaosblade-io/chaos-agent/pkg/progress"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+const (
+	// streamKeepAlive is the interval of the comment line which keeps the idle connection
+	streamKeepAlive = 15 * time.Second
+	// streamMaxDuration closes the stream which never finishes
+	streamMaxDuration = 30 * time.Minute
+)
+
+// ServerStreamHandler streams the progress of a request or job as server-sent events,
+// params: id (request id or job id), from (the last seq received, to resume)
+type ServerStreamHandler struct {
+	Name        string
+	Interceptor transport.RequestInterceptor
+}
+
+func NewServerStreamHandler(name string) *ServerStreamHandler {
+	return &ServerStreamHandler{
+		Name:        name,
+		Interceptor: transport.BuildInterceptor(),
+	}
+}
+
+func (handler *ServerStreamHandler) HandleStream(ctx context.Context, request, remoteAddr string, writer http.ResponseWriter) {
+	startTime := time.Now()
+	req := &transport.Request{}
+	if err := json.Unmarshal([]byte(request), req); err != nil {
+		logrus.Warningf("[ServerStreamHandler] Request decode failed, error: %v", err)
+		writeJson(writer, transport.ReturnFail(transport.EncodeError, err.Error()))
+		return
+	}
+	response, allow := handler.Interceptor.Handle(req)
+	if allow {
+		response = handler.check(req)
+	}
+	audit.Record(&audit.Entry{
+		Time:      startTime.UnixMilli(),
+		RequestId: req.Headers[transport.Rid],
+		AccessKey: req.Headers[transport.AccessKey],
+		SourceIp:  remoteIp(remoteAddr),
+		Handler:   handler.Name,
+		Params:    log.RedactParams(req.Params),
+		Code:      responseCode(response),
+		Success:   response == nil,
+		Error:     responseError(response),
+	})
+	if response != nil {
+		writeJson(writer, response)
+		return
+	}
+
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		writeJson(writer, transport.ReturnFail(transport.ServiceNotSupport, "stream"))
+		return
+	}
+	from, _ := strconv.ParseInt(req.Params["from"], 10, 64)
+	past, events, cancel := progress.Subscribe(req.Params["id"], from)
+	defer cancel()
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.Header().Set("Connection", "keep-alive")
+	writer.WriteHeader(http.StatusOK)
+	for _, event := range past {
+		if err := writeEvent(writer, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	timeout := time.NewTimer(streamMaxDuration)
+	defer timeout.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timeout.C:
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(writer, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(writer, event); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// check returns the failed response if the params are illegal
+func (handler *ServerStreamHandler) check(req *transport.Request) *transport.Response {
+	if req.Params["id"] == "" {
+		return transport.ReturnFail(transport.ParameterEmpty, "id")
+	}
+	if from := req.Params["from"]; from != "" {
+		if _, err := strconv.ParseInt(from, 10, 64); err != nil {
+			return transport.ReturnFail(transport.ParameterTypeError, "from")
+		}
+	}
+	return nil
+}
+
+func writeEvent(writer http.ResponseWriter, event progress.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		logrus.Warningf("[ServerStreamHandler] marshal event failed, err: %v", err)
+		return nil
+	}
+	_, err = fmt.Fprintf(writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data)
+	return err
+}
+
+func writeJson(writer http.ResponseWriter, response *transport.Response) {
+	bytes, err := json.Marshal(response)
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if _, err := writer.Write(bytes); err != nil {
+		logrus.Warningf("[ServerStreamHandler] write response err, %v", err)
+	}
+}
+
+func responseCode(response *transport.Response) int32 {
+	if response == nil {
+		return transport.OK
+	}
+	return response.Code
+}
+
+func responseError(response *transport.Response) string {
+	if response == nil {
+		return ""
+	}
+	return response.Error
+}
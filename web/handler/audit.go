@@ -0,0 +1,71 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+type AuditHandler struct{}
+
+func NewAuditHandler() *AuditHandler {
+	return &AuditHandler{}
+}
+
+// Handle query audit entries, params: since, until (unix millis), handler, limit
+func (ah *AuditHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Info("Receive server audit query request")
+
+	var filter audit.Filter
+	var err error
+	if filter.Since, err = parseInt64Param(request, "since"); err != nil {
+		return transport.ReturnFail(transport.ParameterTypeError, "since")
+	}
+	if filter.Until, err = parseInt64Param(request, "until"); err != nil {
+		return transport.ReturnFail(transport.ParameterTypeError, "until")
+	}
+	limit, err := parseInt64Param(request, "limit")
+	if err != nil {
+		return transport.ReturnFail(transport.ParameterTypeError, "limit")
+	}
+	filter.Limit = int(limit)
+	filter.Handler = request.Params["handler"]
+
+	result, err := audit.Query(filter)
+	if err != nil {
+		logrus.Warningf("[audit] query audit entries failed, err: %v", err)
+		return transport.ReturnFail(transport.ServerError, err.Error())
+	}
+	if !result.Verified {
+		logrus.Warningf("[audit] audit chain verify failed at %d, reason: %s", result.BrokenAt, result.Reason)
+	}
+	return transport.ReturnSuccessWithResult(result)
+}
+
+// parseInt64Param returns 0 if the param is absent
+func parseInt64Param(request *transport.Request, key string) (int64, error) {
+	value := request.Params[key]
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
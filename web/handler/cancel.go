@@ -0,0 +1,151 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+// cancelAuditHandler is the audit handler name of the cleanup of the interrupted commands
+const cancelAuditHandler = "chaosblade/cancel"
+
+// CancelHandler kills the in-flight blade process of the chaosblade request
+type CancelHandler struct {
+	chaosblade *ChaosbladeHandler
+}
+
+func NewCancelHandler(chaosblade *ChaosbladeHandler) *CancelHandler {
+	return &CancelHandler{chaosblade: chaosblade}
+}
+
+// Command describes the cancelled request
+func (h *CancelHandler) Command(request *transport.Request) string {
+	return fmt.Sprintf("cancel request %s", request.Params["rid"])
+}
+
+// Handle cancels the chaosblade request of the rid param, the queued async one is not executed.
+// The experiment partially created by the cancelled command is destroyed if its uid is known, which
+// is the uid param of the request or the uid in the partial output of blade.
+func (h *CancelHandler) Handle(request *transport.Request) *transport.Response {
+	rid := request.Params["rid"]
+	if rid == "" {
+		return transport.ReturnFail(transport.ParameterLess, "rid")
+	}
+	if !h.chaosblade.Cancel(rid) {
+		return transport.ReturnFail(transport.RequestNotFound, rid)
+	}
+	logrus.Infof("[cancel] request %s is cancelled", rid)
+	return transport.ReturnSuccessWithResult(rid)
+}
+
+// track registers the request to be cancelled, untrack must be called once the command is finished.
+// ok is false if the rid is used by another in-flight request, which could not be cancelled anymore.
+func (ch *ChaosbladeHandler) track(rid string) (ctx context.Context, untrack func(), ok bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if rid == "" {
+		return ctx, cancel, true
+	}
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	if _, exists := ch.cancels[rid]; exists {
+		cancel()
+		return nil, nil, false
+	}
+	ch.cancels[rid] = cancel
+	return ctx, func() {
+		ch.mutex.Lock()
+		defer ch.mutex.Unlock()
+		delete(ch.cancels, rid)
+		cancel()
+	}, true
+}
+
+// Cancel cancels the in-flight command of the request, returns false if it's not found
+func (ch *ChaosbladeHandler) Cancel(rid string) bool {
+	ch.mutex.Lock()
+	cancel, ok := ch.cancels[rid]
+	ch.mutex.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// withUid adds a generated uid to the create or prepare command without one, so the experiment
+// can be cleaned up by the uid if the command is cancelled or timed out
+func withUid(cmd *bladeCommand) *bladeCommand {
+	if !isCreateOrPrepareCmd(cmd.arg(0)) {
+		return cmd
+	}
+	if _, ok := policy.ParseCommand(cmd.Args).Flags[experiment.UidFlag]; ok {
+		return cmd
+	}
+	uid, err := tools.GenerateUid()
+	if err != nil {
+		cmd.logger().Warningf("[cancel] generate uid failed, err: %v, cmd: %s", err, cmd.Line)
+		return cmd
+	}
+	copied := *cmd
+	if cmd.Shell {
+		copied.Line = fmt.Sprintf("%s --%s %s", cmd.Line, experiment.UidFlag, uid)
+		copied.Args = strings.Fields(copied.Line)
+	} else {
+		copied.Args = append(append(make([]string, 0, len(cmd.Args)+2), cmd.Args...), "--"+experiment.UidFlag, uid)
+		copied.Line = experiment.CommandLine(copied.Args)
+	}
+	return &copied
+}
+
+// cleanupInterrupted destroys or revokes the experiment which may be partially created by the
+// cancelled or timed out command, stdout is the partial output of blade
+func (ch *ChaosbladeHandler) cleanupInterrupted(bladeCmd *bladeCommand, stdout string) {
+	uid := policy.ParseCommand(bladeCmd.Args).Flags[experiment.UidFlag]
+	if uid == "" {
+		uid = ch.extractUidFromRawResult(stdout)
+	}
+	if uid == "" {
+		bladeCmd.logger().Warningf("[cancel] the uid of the interrupted command is unknown, nothing is cleaned up, cmd: %s", bladeCmd.Line)
+		return
+	}
+	operation := experiment.DestroyOperation
+	if isPrepareCmd(bladeCmd.arg(0)) {
+		operation = experiment.RevokeOperation
+	}
+	cmd := newArgsCommand([]string{operation, uid})
+	cmd.entry = bladeCmd.entry
+	cmd.logger().Warningf("[cancel] clean up the interrupted command, cmd: %s, interrupted: %s", cmd.Line, bladeCmd.Line)
+	response := ch.execWithOutput(cmd, nil)
+	audit.Record(&audit.Entry{
+		Handler: cancelAuditHandler,
+		Params:  map[string]string{"interrupted": bladeCmd.Line},
+		Command: fmt.Sprintf("%s %s", options.BladeBinPath, cmd.Line),
+		Code:    response.Code,
+		Success: response.Success,
+		Error:   response.Error,
+	})
+}
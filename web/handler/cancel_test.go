@@ -0,0 +1,71 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+)
+
+func TestCancel(t *testing.T) {
+	ch := &ChaosbladeHandler{cancels: make(map[string]context.CancelFunc)}
+	ctx, untrack, ok := ch.track("rid-1")
+	if !ok {
+		t.Fatalf("track() of new request = false, want true")
+	}
+	if _, _, ok := ch.track("rid-1"); ok {
+		t.Errorf("track() of in-flight request id = true, want false")
+	}
+	if ch.Cancel("rid-2") {
+		t.Errorf("Cancel() of unknown request = true, want false")
+	}
+	if !ch.Cancel("rid-1") {
+		t.Fatalf("Cancel() of in-flight request = false, want true")
+	}
+	if ctx.Err() == nil {
+		t.Errorf("ctx is not done after Cancel()")
+	}
+	untrack()
+	if ch.Cancel("rid-1") {
+		t.Errorf("Cancel() of finished request = true, want false")
+	}
+}
+
+func TestWithUid(t *testing.T) {
+	tests := []struct {
+		cmd     *bladeCommand
+		wantUid bool
+	}{
+		{cmd: newArgsCommand([]string{"create", "cpu", "fullload"}), wantUid: true},
+		{cmd: newShellCommand("c cpu fullload --timeout 60"), wantUid: true},
+		{cmd: newArgsCommand([]string{"create", "cpu", "fullload", "--uid", "abc"})},
+		{cmd: newArgsCommand([]string{"destroy", "abc"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd.Line, func(t *testing.T) {
+			got := withUid(tt.cmd)
+			uid := policy.ParseCommand(got.Args).Flags[experiment.UidFlag]
+			if tt.wantUid != (got != tt.cmd) || (tt.wantUid && (uid == "" || !strings.Contains(got.Line, uid))) {
+				t.Errorf("withUid() = %q, want uid added %t", got.Line, tt.wantUid)
+			}
+		})
+	}
+}
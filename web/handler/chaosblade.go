@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -27,87 +29,534 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/chaosblade-io/chaos-agent/conn/asyncreport"
-	"github.com/chaosblade-io/chaos-agent/pkg/bash"
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/executor"
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/idempotency"
+	"github.com/chaosblade-io/chaos-agent/pkg/job"
+	"github.com/chaosblade-io/chaos-agent/pkg/limiter"
+	"github.com/chaosblade-io/chaos-agent/pkg/log"
 	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+	"github.com/chaosblade-io/chaos-agent/pkg/probe"
+	"github.com/chaosblade-io/chaos-agent/pkg/progress"
+	"github.com/chaosblade-io/chaos-agent/pkg/radius"
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
 	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/pkg/upgrade"
 	"github.com/chaosblade-io/chaos-agent/transport"
 )
 
-const serviceName = "chaosblade"
+const (
+	serviceName = "chaosblade"
+
+	// bladeLimiterName is the limiter of in-flight blade processes of all handlers
+	bladeLimiterName = "blade"
+
+	// policyAuditHandler is the audit handler name of the commands denied by the safety policy
+	policyAuditHandler = "chaosblade/policy"
+
+	// jobAuditHandler is the audit handler name of the finished async jobs, the submit is audited
+	// by the request
+	jobAuditHandler = "chaosblade/job"
+
+	// bladeSpecDir is the directory of the blade spec files in the blade home
+	bladeSpecDir = "yaml"
+
+	// idempotencyKeyParam is the request param, the create or prepare requests with the same key
+	// are executed once
+	idempotencyKeyParam = "idempotencyKey"
+
+	// execTimeoutParam is the request param of the maximum execution time of blade, eg: 5m
+	execTimeoutParam = "execTimeout"
+	// maxExecTimeout is the upper bound of execTimeoutParam, the blade process and its slot are
+	// not held longer than it
+	maxExecTimeout = time.Hour
+
+	// executedCommandHeader is the request header set by the handler to the command line executed
+	// for the request, which includes the generated uid
+	executedCommandHeader = "executedCmd"
+)
 
 type ChaosbladeHandler struct {
-	mutex   sync.Mutex
-	running map[string]string
+	mutex sync.Mutex
+	// running is the blade arguments of the running experiments and preparations, key is uid
+	running map[string][]string
+	// deadlines is the expire time of the created experiments, key is uid
+	deadlines map[string]time.Time
+	// reserved is the scope of the create commands in flight, counted by the blast radius limits
+	reserved map[string]radius.Scope
+
+	// jobs runs the async requests
+	jobs *job.Manager
+	// probes watches the steady state of the created experiments
+	probes *probe.Monitor
+	// idempotency keeps the results of the idempotency keys
+	idempotency *idempotency.Store
+	// cancels are the cancel functions of the in-flight commands, key is the request id
+	cancels map[string]context.CancelFunc
 
 	transportClient *transport.TransportClient
 }
 
 func NewChaosbladeHandler(transportClient *transport.TransportClient) *ChaosbladeHandler {
-	return &ChaosbladeHandler{
-		running:         make(map[string]string, 0),
+	if limiter.Get(bladeLimiterName) == nil {
+		cfg := options.Opts.ConcurrencyConfig
+		limiter.New(bladeLimiterName, cfg.MaxBladeProcess, cfg.QueueSize, cfg.QueueTimeout)
+	}
+	jobConfig := options.Opts.JobConfig
+	ch := &ChaosbladeHandler{
+		running:         make(map[string][]string, 0),
+		deadlines:       make(map[string]time.Time),
+		reserved:        make(map[string]radius.Scope),
+		cancels:         make(map[string]context.CancelFunc),
 		mutex:           sync.Mutex{},
+		jobs:            job.NewManager(jobConfig.Workers, jobConfig.QueueSize, jobConfig.Retention),
+		probes:          probe.NewMonitor(),
+		idempotency:     idempotency.NewStore(idempotencyFile(), options.Opts.IdempotencyConfig.Retention),
 		transportClient: transportClient,
 	}
+	ch.loadRegistry()
+	return ch
+}
+
+func idempotencyFile() string {
+	if options.Opts.IdempotencyConfig.File != "" {
+		return options.Opts.IdempotencyConfig.File
+	}
+	return tools.GetIdempotencyFilePath()
+}
+
+// Jobs returns the async jobs of the handler
+func (ch *ChaosbladeHandler) Jobs() *job.Manager {
+	return ch.jobs
 }
 
 func (ch *ChaosbladeHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Debugf("[chaosblade] Handle request received at %v, request: %+v", time.Now(), request)
+
+	// the operation is in flight until it's finished, the upgrade waits for it
+	release, ok := upgrade.Enter()
+	if !ok {
+		return transport.ReturnFail(transport.Upgrading, "retry later")
+	}
+	return ch.handle(request, release)
+}
+
+// handle runs the request which has entered the safe point, release is called once it's finished
+func (ch *ChaosbladeHandler) handle(request *transport.Request, release func()) *transport.Response {
 	handleStartTime := time.Now()
-	logrus.Infof("[chaosblade] Handle request received at %v, request: %+v", handleStartTime, request)
+	delete(request.Headers, executedCommandHeader)
+	cmd, response := buildCommand(request)
+	if response != nil {
+		release()
+		return response
+	}
+	rid := request.Headers[transport.Rid]
+	log.WithRid(rid).Infof("[chaosblade] Command extracted, cmd: %s, time since handle start: %v", cmd.Line, time.Since(handleStartTime))
+	key := request.Params[idempotencyKeyParam]
+	if key == "" || !isCreateOrPrepareCmd(cmd.arg(0)) {
+		return ch.dispatch(cmd, request, release)
+	}
+	response, executed, err := ch.idempotency.Do(key, cmd.Line, func() *transport.Response {
+		return ch.dispatch(cmd, request, release)
+	})
+	if !executed {
+		release()
+	}
+	if err != nil {
+		log.WithRid(rid).Warningf("[chaosblade] idempotency key %s conflicts, cmd: %s", key, cmd.Line)
+		return transport.ReturnFail(transport.IdempotencyConflict, key)
+	}
+	if !executed {
+		log.WithRid(rid).Infof("[chaosblade] duplicate request of idempotency key %s, the original result is returned", key)
+	}
+	return response
+}
 
-	//todo 版本不一致时，需要update,这里是判断是否升级完成
-	//if handler.blade.upgrade.NeedWait() {
-	//	return transport.ReturnFail(transport.Code[transport.Upgrading], "agent is in upgrading")
-	//}
-	cmd := request.Params["cmd"]
-	if cmd == "" {
-		return transport.ReturnFail(transport.ParameterEmpty, "cmd")
+// dispatch executes the command, or submits it as a job if async is true. release is called once
+// the command is finished.
+func (ch *ChaosbladeHandler) dispatch(cmd *bladeCommand, request *transport.Request, release func()) *transport.Response {
+	rid := request.Headers[transport.Rid]
+	cmd.entry = log.WithRid(rid)
+	ctx, untrack, ok := ch.track(rid)
+	if !ok {
+		release()
+		cmd.entry.Warningf("[chaosblade] request id is used by an in-flight request, cmd: %s", cmd.Line)
+		return transport.ReturnFail(transport.DuplicateRequest, rid)
 	}
-	logrus.Infof("[chaosblade] Command extracted, cmd: %s, time since handle start: %v", cmd, time.Since(handleStartTime))
-	return ch.exec(cmd)
+	cmd = withUid(cmd)
+	request.AddHeader(executedCommandHeader, cmd.Line)
+	cmd.ctx = ctx
+	cmd.Progress = progress.NewReporter(rid)
+	finish := func() {
+		untrack()
+		release()
+	}
+	if request.Params["async"] == "true" {
+		return ch.submit(cmd, rid, finish)
+	}
+	defer finish()
+	response := ch.execWithOutput(cmd, nil)
+	cmd.Progress.Finish(response)
+	log.WithRid(rid).Infof("[chaosblade] Command completed, success: %t, code: %d, err: %s", response.Success, response.Code, response.Error)
+	return response
 }
 
-func (ch *ChaosbladeHandler) exec(cmd string) *transport.Response {
-	execStartTime := time.Now()
-	logrus.Infof("[chaosblade] exec() called at %v, cmd: %s", execStartTime, cmd)
+// Command returns the full blade command line of the request, it's the executed one with the
+// generated uid once the request is dispatched
+func (ch *ChaosbladeHandler) Command(request *transport.Request) string {
+	if line := request.Headers[executedCommandHeader]; line != "" {
+		return fmt.Sprintf("%s %s", options.BladeBinPath, line)
+	}
+	cmd, response := buildCommand(request)
+	if response != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %s", options.BladeBinPath, cmd.Line)
+}
 
-	fieldsStartTime := time.Now()
-	fields := strings.Fields(cmd)
-	fieldsDuration := time.Since(fieldsStartTime)
-	logrus.Debugf("[chaosblade] Fields parsing completed, duration: %v", fieldsDuration)
+// bladeCommand is the blade arguments, Line is the command line without blade for logs and the running cache
+type bladeCommand struct {
+	Args []string
+	Line string
+	// Shell is true for the legacy cmd param, which is executed by shell as it is
+	Shell bool
+	// TTL is the maximum duration requested by server, the experiment is destroyed by watchdog after it
+	TTL time.Duration
+	// Progress receives the output lines and phases, can be nil
+	Progress *progress.Reporter
+	// Probes check the steady state before, during and after the created experiment
+	Probes []probe.Probe
+	// Timeout is the maximum execution time of blade, the blade.timeout flag is used if it's 0
+	Timeout time.Duration
+
+	// ctx cancels the blade process by the cancel request
+	ctx context.Context
+	// entry logs with the request id of the command, can be nil
+	entry *logrus.Entry
+}
+
+func newShellCommand(cmd string) *bladeCommand {
+	return &bladeCommand{Args: strings.Fields(cmd), Line: cmd, Shell: true}
+}
+
+func newArgsCommand(args []string) *bladeCommand {
+	return &bladeCommand{Args: args, Line: experiment.CommandLine(args)}
+}
+
+// arg returns the argument at index, or empty string if absent
+func (c *bladeCommand) arg(index int) string {
+	if index < len(c.Args) {
+		return c.Args[index]
+	}
+	return ""
+}
+
+// logger returns the log entry of the command, which carries the request id if it's dispatched by a request
+func (c *bladeCommand) logger() *logrus.Entry {
+	if c.entry == nil {
+		return logrus.NewEntry(logrus.StandardLogger())
+	}
+	return c.entry
+}
+
+// context returns the context of the command, which is done once the command is cancelled
+func (c *bladeCommand) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+func (c *bladeCommand) run(ctx context.Context) *executor.Result {
+	opts := executor.Options{Timeout: options.Opts.BladeTimeout}
+	if c.Timeout > 0 {
+		opts.Timeout = c.Timeout
+	}
+	if c.Progress != nil {
+		opts.OnLine = c.Progress.Output
+	}
+	if c.Shell {
+		return runBlade(ctx, []string{c.Line}, true, opts)
+	}
+	return runBlade(ctx, c.Args, false, opts)
+}
+
+// buildCommand reads the legacy cmd param if present, otherwise the structured experiment params
+func buildCommand(request *transport.Request) (*bladeCommand, *transport.Response) {
+	var ttl, timeout time.Duration
+	if value := request.Params["ttl"]; value != "" {
+		var err error
+		if ttl, err = time.ParseDuration(value); err != nil || ttl < 0 {
+			return nil, transport.ReturnFail(transport.ParameterTypeError, "ttl")
+		}
+	}
+	if value := request.Params[execTimeoutParam]; value != "" {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil || timeout <= 0 {
+			return nil, transport.ReturnFail(transport.ParameterTypeError, execTimeoutParam)
+		}
+		if timeout > maxExecTimeout {
+			logrus.Warningf("[chaosblade] %s %s exceeds the maximum, %s is used", execTimeoutParam, value, maxExecTimeout)
+			timeout = maxExecTimeout
+		}
+	}
+	if cmd, ok := request.Params["cmd"]; ok {
+		if !options.Opts.ChaosbladeLegacyCmd {
+			return nil, transport.ReturnFail(transport.ServiceNotSupport, "legacy cmd param")
+		}
+		if cmd == "" {
+			return nil, transport.ReturnFail(transport.ParameterEmpty, "cmd")
+		}
+		bladeCmd := newShellCommand(cmd)
+		bladeCmd.TTL, bladeCmd.Timeout = ttl, timeout
+		return withProbes(bladeCmd, request)
+	}
+	exp, err := experiment.Parse(request.Params)
+	if err != nil {
+		return nil, transport.ReturnFail(transport.ExperimentInvalid, err.Error())
+	}
+	if spec := experiment.GetSpec(path.Join(options.BladeHome, bladeSpecDir)); spec != nil {
+		if err := spec.Validate(exp); err != nil {
+			return nil, transport.ReturnFail(transport.ExperimentInvalid, err.Error())
+		}
+	}
+	bladeCmd := newArgsCommand(exp.Args())
+	bladeCmd.TTL, bladeCmd.Timeout = ttl, timeout
+	return withProbes(bladeCmd, request)
+}
+
+// experimentRequest is the chaosblade request of the structured experiment, which is built by the
+// agent itself, eg: the scheduled or the scenario experiments
+func experimentRequest(exp *experiment.Experiment) *transport.Request {
+	request := transport.NewRequest()
+	request.AddParam("operation", exp.Operation).
+		AddParam("scope", exp.Scope).
+		AddParam("target", exp.Target).
+		AddParam("action", exp.Action).
+		AddParam("uid", exp.Uid)
+	if len(exp.Flags) > 0 {
+		flags, _ := json.Marshal(exp.Flags)
+		request.AddParam("flags", string(flags))
+	}
+	return request
+}
+
+// Experiment is a running experiment or preparation recorded by the handler
+type Experiment struct {
+	Uid      string     `json:"uid"`
+	Command  string     `json:"command"`
+	ExpireAt *time.Time `json:"expireAt,omitempty"`
+}
+
+// Experiments returns the running experiments and preparations, sorted by uid
+func (ch *ChaosbladeHandler) Experiments() []Experiment {
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	experiments := make([]Experiment, 0, len(ch.running))
+	for uid, args := range ch.running {
+		experiment := Experiment{Uid: uid, Command: experiment.CommandLine(args)}
+		if deadline, ok := ch.deadlines[uid]; ok {
+			experiment.ExpireAt = &deadline
+		}
+		experiments = append(experiments, experiment)
+	}
+	sort.Slice(experiments, func(i, j int) bool {
+		return experiments[i].Uid < experiments[j].Uid
+	})
+	return experiments
+}
+
+// DestroyResult is the result of destroying one experiment
+type DestroyResult struct {
+	Uid      string              `json:"uid"`
+	Command  string              `json:"command"`
+	Response *transport.Response `json:"response"`
+}
+
+// DestroyAll destroys all running experiments and revokes all preparations
+func (ch *ChaosbladeHandler) DestroyAll() []DestroyResult {
+	results := make([]DestroyResult, 0)
+	for _, experiment := range ch.Experiments() {
+		operation := "destroy"
+		if fields := strings.Fields(experiment.Command); len(fields) > 0 && options.PrepareOperation[fields[0]] {
+			operation = "revoke"
+		}
+		cmd := newArgsCommand([]string{operation, experiment.Uid})
+		logrus.Warningf("[chaosblade] destroy all, cmd: %s, experiment: %s", cmd.Line, experiment.Command)
+		results = append(results, DestroyResult{
+			Uid:      experiment.Uid,
+			Command:  fmt.Sprintf("%s %s", options.BladeBinPath, cmd.Line),
+			Response: ch.execInFlight(cmd, 0),
+		})
+	}
+	return results
+}
+
+// submit runs the command as an async job, the job id is returned immediately. release is called
+// once the job is finished.
+func (ch *ChaosbladeHandler) submit(cmd *bladeCommand, rid string, release func()) *transport.Response {
+	submitted, err := ch.jobs.Submit(cmd.Line, rid, func(id string) *job.Output {
+		defer release()
+		cmd.Progress = cmd.Progress.With(id)
+		output := &job.Output{}
+		output.Response = ch.execWithOutput(cmd, output)
+		cmd.Progress.Finish(output.Response)
+		log.WithRid(rid).Infof("[chaosblade] async command completed, cmd: %s, success: %t", cmd.Line, output.Response.Success)
+		return output
+	}, ch.finishJob)
+	if err != nil {
+		release()
+		logrus.Warningf("[chaosblade] submit async job failed, err: %v, cmd: %s", err, cmd.Line)
+		return transport.ReturnFail(transport.HandlerBusy, fmt.Sprintf("job, %s", err.Error()))
+	}
+	log.WithRid(rid).Infof("[chaosblade] async job submitted, job: %s, cmd: %s", submitted.Id, cmd.Line)
+	return transport.ReturnSuccessWithResult(submitted)
+}
+
+// finishJob audits the final result of the finished job and reports it
+func (ch *ChaosbladeHandler) finishJob(finished *job.Job) {
+	entry := &audit.Entry{
+		Time:      finished.SubmitTime.UnixMilli(),
+		RequestId: finished.RequestId,
+		Handler:   jobAuditHandler,
+		Params:    map[string]string{"jobId": finished.Id},
+		Command:   fmt.Sprintf("%s %s", options.BladeBinPath, finished.Command),
+	}
+	if finished.EndTime != nil {
+		entry.DurationMs = finished.EndTime.Sub(finished.SubmitTime).Milliseconds()
+	}
+	if finished.Response != nil {
+		entry.Code = finished.Response.Code
+		entry.Success = finished.Response.Success
+		entry.Error = finished.Response.Error
+	}
+	audit.Record(entry)
+	ch.reportJob(finished)
+}
+
+// reportJob reports the finished job through the chaosblade async report uri
+func (ch *ChaosbladeHandler) reportJob(finished *job.Job) {
+	uri, ok := transport.TransportUriMap[transport.API_CHAOSBLADE_ASYNC]
+	if !ok {
+		logrus.Warnf("[report job] report uri is null!")
+		return
+	}
+	var uid, errorMsg string
+	status := "Error"
+	if finished.Response != nil {
+		errorMsg = finished.Response.Error
+		if finished.Response.Success {
+			status = "Success"
+			uid, _ = finished.Response.Result.(string)
+		}
+	}
+	ar := asyncreport.NewClientCloseHandler(ch.transportClient)
+	ar.ReportJobStatus(finished.Id, uid, status, errorMsg, uri)
+}
+
+// execInFlight executes the command started by the agent itself as an experiment operation, so that
+// the upgrade and the blade installation wait for it. It waits up to wait for the upgrade in progress.
+func (ch *ChaosbladeHandler) execInFlight(cmd *bladeCommand, wait time.Duration) *transport.Response {
+	release, ok := upgrade.EnterWait(wait)
+	if !ok {
+		return transport.ReturnFail(transport.Upgrading, "retry later")
+	}
+	defer release()
+	return ch.execWithOutput(cmd, nil)
+}
+
+// execWithOutput executes the command, the raw stdout and stderr of blade are kept in output if it's not nil
+func (ch *ChaosbladeHandler) execWithOutput(bladeCmd *bladeCommand, output *job.Output) *transport.Response {
+	execStartTime := time.Now()
+	cmd, fields := bladeCmd.Line, bladeCmd.Args
+	logger := bladeCmd.logger()
+	logger.Infof("[chaosblade] exec() called at %v, cmd: %s", execStartTime, cmd)
 
 	if len(fields) == 0 {
-		logrus.Warning("less command parameters")
+		logger.Warning("less command parameters")
 		return transport.ReturnFail(transport.ParameterLess, "command")
 	}
 
 	// 判断 chaosblade 是否存在
 	checkStartTime := time.Now()
 	if !tools.IsExist(options.BladeBinPath) {
-		logrus.Warning(transport.Errors[transport.ChaosbladeFileNotFound])
+		logger.Warning(transport.Errors[transport.ChaosbladeFileNotFound])
 		return transport.ReturnFail(transport.ChaosbladeFileNotFound)
 	}
 	checkDuration := time.Since(checkStartTime)
-	logrus.Debugf("[chaosblade] BladeBinPath check completed, duration: %v, time since exec start: %v", checkDuration, time.Since(execStartTime))
+	logger.Debugf("[chaosblade] BladeBinPath check completed, duration: %v, time since exec start: %v", checkDuration, time.Since(execStartTime))
 	command := fields[0]
 
+	reason := policy.Check(fields)
+	if reason == "" && bladeCmd.Shell {
+		reason = policy.CheckShell(cmd)
+	}
+	if reason != "" {
+		logger.Warningf("[chaosblade] command denied by safety policy, reason: %s, cmd: %s", reason, cmd)
+		response := transport.ReturnFail(transport.PolicyDenied, reason)
+		audit.Record(&audit.Entry{
+			Handler: policyAuditHandler,
+			Command: fmt.Sprintf("%s %s", options.BladeBinPath, cmd),
+			Code:    response.Code,
+			Error:   response.Error,
+		})
+		return response
+	}
+
+	if options.CreateOperation[command] {
+		release, reason := ch.reserve(fields)
+		if reason != "" {
+			return rejectByRadius(cmd, reason)
+		}
+		defer release()
+	}
+
+	if len(bladeCmd.Probes) > 0 {
+		bladeCmd.Progress.Phase("check steady state, probes: %d", len(bladeCmd.Probes))
+		if evidences, ok := probe.CheckAll(bladeCmd.context(), bladeCmd.Probes); !ok {
+			logger.Warningf("[chaosblade] steady state is not met before injection, evidences: %+v, cmd: %s", evidences, cmd)
+			return transport.ReturnFail(transport.ProbeFailed, fmt.Sprintf("steady state is not met before injection, %s", evidenceJson(evidences)))
+		}
+	}
+
+	if err := bladeCmd.context().Err(); err != nil {
+		return transport.ReturnFail(transport.CommandCancelled, "before execution")
+	}
+
 	// 执行 blade 命令
 	scriptStartTime := time.Now()
-	logrus.Infof("[chaosblade] Starting to execute blade command at %v (time since exec start: %v), cmd: %s", scriptStartTime, time.Since(execStartTime), cmd)
-	result, errMsg, ok := bash.ExecScript(context.Background(), options.BladeBinPath, cmd)
+	logger.Infof("[chaosblade] Starting to execute blade command at %v (time since exec start: %v), cmd: %s", scriptStartTime, time.Since(execStartTime), cmd)
+	bladeCmd.Progress.Phase("exec started: blade %s", cmd)
+	execResult := bladeCmd.run(bladeCmd.context())
+	result, errMsg, ok := bladeResult(execResult), execResult.Error(), execResult.Success()
+	bladeCmd.Progress.Phase("exec finished, exit code: %d, duration: %s", execResult.ExitCode, execResult.Duration)
 	scriptDuration := time.Since(scriptStartTime)
 	diffTime := time.Since(execStartTime)
-	logrus.Infof("[chaosblade] execute chaosblade result, result: %s, errMsg: %s, ok: %t, script duration: %v, total exec duration: %v, cmd: %v", result, errMsg, ok, scriptDuration, diffTime, cmd)
+	logger.Infof("[chaosblade] execute chaosblade result, result: %s, errMsg: %s, ok: %t, script duration: %v, total exec duration: %v, cmd: %v", result, errMsg, ok, scriptDuration, diffTime, cmd)
+	if output != nil {
+		output.Stdout, output.Stderr = execResult.Stdout, execResult.Stderr
+		output.ExitCode = &execResult.ExitCode
+	}
+	cancelled := bladeCmd.context().Err() != nil
+	if (cancelled || execResult.TimedOut) && isCreateOrPrepareCmd(command) {
+		ch.cleanupInterrupted(bladeCmd, result)
+	}
+	if cancelled {
+		return transport.ReturnFail(transport.CommandCancelled, fmt.Sprintf("after %s", execResult.Duration))
+	}
 	if ok {
 		// 解析返回结果
 		response := parseResult(result)
 		if !response.Success {
-			logrus.Warningf("execute chaos failed, result: %s", result)
+			logger.Warningf("execute chaos failed, result: %s", result)
 			// 即使失败，如果是 K8s create 命令，也要检查是否有 uid 并等待状态
 			if isK8sCreateCmd(cmd, command) {
 				uid := ch.extractUidFromResponse(response, result)
 				if uid != "" {
-					logrus.Infof("K8s create command failed but uid found, waiting for operator to process, uid: %s", uid)
-					ch.waitForK8sStatus(uid)
+					logger.Infof("K8s create command failed but uid found, waiting for operator to process, uid: %s", uid)
+					ch.waitForK8sStatus(uid, bladeCmd)
 				}
 			}
 			return response
@@ -117,25 +566,41 @@ func (ch *ChaosbladeHandler) exec(cmd string) *transport.Response {
 		if isK8sCreateCmd(cmd, command) {
 			uid := ch.extractUidFromResponse(response, result)
 			if uid != "" {
-				logrus.Infof("K8s create command detected, waiting for operator to process, uid: %s", uid)
-				ch.waitForK8sStatus(uid)
+				logger.Infof("K8s create command detected, waiting for operator to process, uid: %s", uid)
+				ch.waitForK8sStatus(uid, bladeCmd)
 			}
 		}
 
 		// 安全点处理
-		ch.handleCacheAndSafePoint(cmd, command, fields[1], response)
+		ttlConfig := options.Opts.TTLConfig
+		ttl := experiment.TTL(fields, bladeCmd.TTL, ttlConfig.Default, ttlConfig.Max)
+		arg := bladeCmd.arg(1)
+		if isDestroyOrRevokeCmd(command) {
+			arg = destroyedUid(fields)
+		}
+		ch.handleCacheAndSafePoint(bladeCmd, command, arg, ttl, response)
+		if len(bladeCmd.Probes) > 0 && options.CreateOperation[command] {
+			if uid, ok := response.Result.(string); ok && uid != "" {
+				ch.watchProbes(uid, bladeCmd.Probes)
+			}
+		}
 		return response
 	} else {
 		var response transport.Response
-		err := json.Unmarshal([]byte(result), &response)
+		// the error of blade may be prefixed by its logs as well
+		jsonResult := result
+		if index := strings.Index(result, "{"); index > 0 {
+			jsonResult = result[index:]
+		}
+		err := json.Unmarshal([]byte(jsonResult), &response)
 		if err != nil {
-			logrus.Warningf("Unmarshal chaosblade error message err: %s, result: %s", err.Error(), result)
+			logger.Warningf("Unmarshal chaosblade error message err: %s, result: %s", err.Error(), result)
 			// 即使解析失败，也要尝试提取 uid（可能结果格式不标准）
 			if isK8sCreateCmd(cmd, command) {
 				uid := ch.extractUidFromRawResult(result)
 				if uid != "" {
-					logrus.Infof("K8s create command failed to parse but uid found in raw result, waiting for operator to process, uid: %s", uid)
-					ch.waitForK8sStatus(uid)
+					logger.Infof("K8s create command failed to parse but uid found in raw result, waiting for operator to process, uid: %s", uid)
+					ch.waitForK8sStatus(uid, bladeCmd)
 				}
 			}
 			return transport.ReturnFail(transport.ResultUnmarshalFailed, result, errMsg)
@@ -144,8 +609,8 @@ func (ch *ChaosbladeHandler) exec(cmd string) *transport.Response {
 			if isK8sCreateCmd(cmd, command) {
 				uid := ch.extractUidFromResponse(&response, result)
 				if uid != "" {
-					logrus.Infof("K8s create command returned error but uid found, waiting for operator to process, uid: %s", uid)
-					ch.waitForK8sStatus(uid)
+					logger.Infof("K8s create command returned error but uid found, waiting for operator to process, uid: %s", uid)
+					ch.waitForK8sStatus(uid, bladeCmd)
 				}
 			}
 			return &response
@@ -153,13 +618,45 @@ func (ch *ChaosbladeHandler) exec(cmd string) *transport.Response {
 	}
 }
 
+// execBlade runs blade with the arguments, returns the stdout, the error description and whether it succeeded
+func execBlade(ctx context.Context, args ...string) (string, string, bool) {
+	result := runBlade(ctx, args, false, executor.Options{Timeout: options.Opts.BladeTimeout})
+	return bladeResult(result), result.Error(), result.Success()
+}
+
+// bladeResult returns the json result of blade, which is written to stderr instead of stdout by blade
+// on some failures, then stdout has no json
+func bladeResult(result *executor.Result) string {
+	if !strings.Contains(result.Stdout, "{") && strings.Contains(result.Stderr, "{") {
+		return result.Stderr
+	}
+	return result.Stdout
+}
+
+// runBlade runs blade under the limit of in-flight blade processes. If shell is true, args[0] is the
+// legacy command line which is executed by shell.
+func runBlade(ctx context.Context, args []string, shell bool, opts executor.Options) *executor.Result {
+	bladeLimiter := limiter.Get(bladeLimiterName)
+	if err := bladeLimiter.Acquire(); err != nil {
+		logrus.Warningf("[chaosblade] acquire blade process slot failed, err: %v, args: %v", err, args)
+		return &executor.Result{ExitCode: -1, Err: fmt.Errorf("acquire blade process slot failed, %s", err.Error())}
+	}
+	defer bladeLimiter.Release()
+	if shell {
+		return executor.Shell(ctx, fmt.Sprintf("%s %s", options.BladeBinPath, args[0]), opts)
+	}
+	return executor.Run(ctx, options.BladeBinPath, args, opts)
+}
+
 // handleCacheAndSafePoint， 记录缓存并操作安全点，将uid记录下来，并异步返回结果
-// cmdline 命令参数，不包含开头的 blade
+// bladeCmd 命令参数，不包含开头的 blade
 // command: create, prepare, destroy 等命令
 // arg: 第二个参数，比如 prepare 操作，则 arg 是 jvm，destroy 操作, arg 是 UID
+// ttl: create 的演练到期后由 watchdog 销毁，0 表示不限制
 // todo 这里后面需要看下agent停止的时候有没有把演练中的演练关停
-func (ch *ChaosbladeHandler) handleCacheAndSafePoint(cmdline, command, arg string, response *transport.Response) {
+func (ch *ChaosbladeHandler) handleCacheAndSafePoint(bladeCmd *bladeCommand, command, arg string, ttl time.Duration, response *transport.Response) {
 	handleCacheStartTime := time.Now()
+	cmdline := bladeCmd.Line
 	logrus.Debugf("[chaosblade] handleCacheAndSafePoint start, cmdline: %s, command: %s, arg: %s", cmdline, command, arg)
 
 	lockStartTime := time.Now()
@@ -178,133 +675,48 @@ func (ch *ChaosbladeHandler) handleCacheAndSafePoint(cmdline, command, arg strin
 	if isCreateOrPrepareCmd(command) {
 		// 记录正在运行的演练
 		uid := response.Result.(string)
-		ch.running[uid] = cmdline
+		ch.running[uid] = bladeCmd.Args
+		if options.CreateOperation[command] && ttl > 0 {
+			ch.deadlines[uid] = time.Now().Add(ttl)
+		}
 		// 设置安全点
 		// todo 这里是后面的update会用到，后面看下
 		// ch.upgrade.SetUnsafePoint(serviceName)
 
-		if isJavaAgentInstall(command, arg) {
-			// 先记录安全点，如果失败，则删除安全点
-			go ch.checkAndReportJavaAgentStatus(uid, ch.reportStatusFunc, ch.deleteCallback)
+		if isPrepareCmd(command) {
+			// arg is the program type of prepare, the uid is forgotten if the installation fails
+			go ch.trackPreparation(uid, arg, installPhase)
 		}
 		if isAsyncCreate(cmdline) {
 			go ch.checkAndReportAsyncStatus(uid, ch.reportStatusFunc)
 		}
+		ch.persistLocked()
 	} else if isDestroyOrRevokeCmd(command) {
 		// 删除已停止的演练, arg=uid
 		uid := arg
+		delete(ch.deadlines, uid)
 		if _, ok := ch.running[uid]; ok {
 			delete(ch.running, uid)
 			// 删除安全点
 			// todo 同上
 			// ch.upgrade.DeleteUnsafePoint(serviceName)
+			ch.persistLocked()
 		}
+		go ch.checkAfterExperiment(uid)
 		// 判断是否是 revoke
 		if isRevokeOperation(command) {
-			// 查询 agent 类型
-			record, err := ch.queryPreparationStatus(uid)
-			if err != nil {
-				logrus.Warningf("Query preparation err, %v, uid: %s", err, uid)
-				return
-			}
-			if record == nil {
-				logrus.Warningf("Preparation record not found, uid: %s", uid)
-				return
-			}
-			if record.ProgramType == JavaType {
-				// 如果是 java agent，则检查上报
-				go ch.checkAndReportJavaAgentUninstallStatus(uid, ch.reportStatusFunc, func(uid string, status string) {})
-			}
+			go ch.trackRevoke(uid)
 		}
 	}
 }
 
-func (ch *ChaosbladeHandler) checkAndReportJavaAgentStatus(uid string, reportFunc func(uid, status, errorMsg string, uri transport.Uri),
-	callbackFunc func(uid, status string),
-) {
-	logrus.Debugf("start checkAndReportJavaAgentStatus...")
-	status, errorMsg := ch.timingCheckStatus(uid)
-	// 处理缓存回调
-	callbackFunc(uid, status)
-
-	uri, ok := transport.TransportUriMap[transport.API_JAVA_INSTALL]
-	if !ok {
-		logrus.Warnf("[report java install] report uri is null!")
-		return
-	}
-
-	reportFunc(uid, status, errorMsg, uri)
-}
-
-func (ch *ChaosbladeHandler) checkAndReportJavaAgentUninstallStatus(uid string, reportFunc func(uid, status, errorMsg string, uri transport.Uri),
-	callbackFunc func(uid, status string),
-) {
-	logrus.Debugf("start checkAndReportJavaAgentUninstallStatus...")
-	status, errorMsg := ch.timingCheckStatus(uid)
-	// 处理缓存回调
-	callbackFunc(uid, status)
-
-	uri, ok := transport.TransportUriMap[transport.API_JAVA_UNINSTALL]
-	if !ok {
-		logrus.Warnf("[report java uninstall] report uri is null!")
-		return
-	}
-	reportFunc(uid, status, errorMsg, uri)
-}
-
 func (ch *ChaosbladeHandler) checkAndReportAsyncStatus(uid string, reportFunc func(uid, status, errorMsg string, uri transport.Uri)) {
 	logrus.Debugf("start checkAndReportAsyncStatus...")
-	status, errorMsg := ch.timingCheckStatus(uid)
+	result := status.Wait(context.TODO(), experiment.CreateOperation, uid, createSource, status.Pending)
 
-	// 上报状态
+	// 上报状态, the legacy uri expects the status of blade
 	uri := transport.TransportUriMap[transport.API_CHAOSBLADE_ASYNC]
-	reportFunc(uid, status, errorMsg, uri)
-}
-
-func (ch *ChaosbladeHandler) timingCheckStatus(uid string) (status, errorMsg string) {
-	// 设置定时器
-	logrus.Debugf("start timing check uid: %s status...", uid)
-	ticker := time.NewTicker(time.Second)
-	timeoutCtx, cancelFunc := context.WithTimeout(context.TODO(), time.Minute)
-	defer cancelFunc()
-	// 设置上报程序
-	status = "Unknown"
-	var stopped bool
-	// 周期性检查状态
-	for range ticker.C {
-		select {
-		case <-timeoutCtx.Done():
-			logrus.Warningf("timeout checkAndReportJavaAgentStatus...")
-			ticker.Stop()
-			stopped = true
-		default:
-			logrus.Debugf("periodically checkAndReportJavaAgentStatus...")
-			record, err := ch.queryPreparationStatus(uid)
-			if err != nil {
-				logrus.Warningf("Query preparation status err periodically, %v", err)
-				continue
-			}
-			if record == nil {
-				errorMsg = "record not found"
-				ticker.Stop()
-				stopped = true
-			}
-			status = record.Status
-			// "status":"Created|Running|Error|Revoked"
-			if strings.EqualFold(record.Status, "Created") {
-				continue
-			}
-			if strings.EqualFold(status, "Error") {
-				errorMsg = record.Error
-			}
-			ticker.Stop()
-			stopped = true
-		}
-		if stopped {
-			break
-		}
-	}
-	return status, errorMsg
+	reportFunc(uid, result.RawStatus(), result.Error, uri)
 }
 
 // 上报状态
@@ -313,53 +725,6 @@ func (ch *ChaosbladeHandler) reportStatusFunc(uid, status, errorMsg string, uri
 	ar.ReportStatus(uid, status, errorMsg, "", uri)
 }
 
-// 如果挂载失败，则需要删除缓存
-func (ch *ChaosbladeHandler) deleteCallback(uid, status string) {
-	if strings.EqualFold(status, "Error") {
-		if _, ok := ch.running[uid]; ok {
-			delete(ch.running, uid)
-			// todo 安全点这个暂时往后放
-			// ch.upgrade.DeleteUnsafePoint(serviceName)
-		}
-	}
-}
-
-type preparation struct {
-	Uid         string `json:"Uid"`
-	ProgramType string `json:"ProgramType"`
-	Status      string `json:"Status"`
-	Error       string `json:"Error"`
-}
-
-// queryPreparationStatus
-func (ch *ChaosbladeHandler) queryPreparationStatus(uid string) (*preparation, error) {
-	result, errorMsg, isSuccess := bash.ExecScript(context.TODO(), options.BladeBinPath, fmt.Sprintf("status %s", uid))
-	if !isSuccess {
-		return nil, fmt.Errorf("invoke blade error, %s", errorMsg)
-	}
-	response := parseResult(result)
-	// map[string]interface {}
-	if response.Result == nil {
-		return nil, fmt.Errorf("cannot get record")
-	}
-	if fields, ok := response.Result.(map[string]interface{}); ok {
-		var record preparation
-		record.Uid = uid
-		if programType, ok := fields["ProgramType"]; ok {
-			record.ProgramType = programType.(string)
-		}
-		if status, ok := fields["Status"]; ok {
-			record.Status = status.(string)
-		}
-		if err, ok := fields["Error"]; ok {
-			record.Error = err.(string)
-		}
-		return &record, nil
-	} else {
-		return nil, fmt.Errorf("unknown type of response, %v", response.Result)
-	}
-}
-
 // parse result to response
 func parseResult(result string) *transport.Response {
 	var response transport.Response
@@ -403,13 +768,19 @@ func isDestroyOrRevokeCmd(command string) bool {
 	return false
 }
 
-const JavaType = "jvm"
-
-func isJavaAgentInstall(command, agentType string) bool {
-	if _, ok := options.PrepareOperation[command]; ok {
-		return agentType == JavaType
+// destroyedUid returns the uid of the destroy or revoke arguments, which is the first subject, eg:
+// destroy 7c1f7afc281482c8, or the uid flag, eg: destroy k8s pod-network delay --uid 7c1f7afc281482c8
+func destroyedUid(args []string) string {
+	command := policy.ParseCommand(args)
+	if uid := command.Flags[experiment.UidFlag]; uid != "" {
+		return uid
 	}
-	return false
+	return command.Target()
+}
+
+func isPrepareCmd(command string) bool {
+	_, ok := options.PrepareOperation[command]
+	return ok
 }
 
 func isRevokeOperation(command string) bool {
@@ -502,100 +873,3 @@ func (ch *ChaosbladeHandler) extractUidFromRawResult(rawResult string) string {
 	}
 	return ""
 }
-
-// waitForK8sStatus 等待 K8s 实验状态，确保 chaosblade-operator 处理完成
-// 通过查询状态来确认是否完成，最多等待 10 秒
-func (ch *ChaosbladeHandler) waitForK8sStatus(uid string) {
-	logrus.Infof("[chaosblade] waiting for K8s experiment status, uid: %s", uid)
-	timeoutCtx, cancelFunc := context.WithTimeout(context.TODO(), 10*time.Second)
-	defer cancelFunc()
-
-	ticker := time.NewTicker(500 * time.Millisecond)
-	defer ticker.Stop()
-
-	// 先等待一小段时间，给 operator 一些处理时间
-	time.Sleep(500 * time.Millisecond)
-
-	for {
-		select {
-		case <-timeoutCtx.Done():
-			logrus.Warningf("[chaosblade] timeout waiting for K8s experiment status, uid: %s", uid)
-			return
-		case <-ticker.C:
-			// 查询 K8s 实验状态，确保命令格式正确（有空格）
-			queryCmd := fmt.Sprintf("query k8s create %s", uid)
-			result, errMsg, ok := bash.ExecScript(context.TODO(), options.BladeBinPath, queryCmd)
-			if !ok {
-				logrus.Debugf("query K8s status failed, uid: %s, error: %s", uid, errMsg)
-				continue
-			}
-
-			response := parseResult(result)
-			if response == nil || !response.Success {
-				logrus.Debugf("query K8s status returned error, uid: %s, result: %s", uid, result)
-				continue
-			}
-
-			// 检查返回结果中是否有状态信息
-			// K8sResultBean 格式: {"uid":"xxx","success":true,"error":"","statuses":[...]}
-			// 或者直接返回 statuses 数组
-			if response.Result != nil {
-				// 尝试解析为 map
-				if resultMap, ok := response.Result.(map[string]interface{}); ok {
-					// 检查是否有 statuses 字段且不为空
-					if statuses, ok := resultMap["statuses"].([]interface{}); ok {
-						if len(statuses) > 0 {
-							logrus.Infof("K8s experiment status found, uid: %s, statuses count: %d", uid, len(statuses))
-							return
-						}
-					}
-					// 或者检查 success 字段为 true（即使 statuses 为空，success 为 true 也表示已完成）
-					if success, ok := resultMap["success"].(bool); ok && success {
-						// 如果 success 为 true，即使 statuses 为空，也认为已完成（可能是 operator 还在处理中，但已经接受请求）
-						// 等待一下再检查一次，确保有 statuses
-						time.Sleep(1 * time.Second)
-						// 再次查询
-						result2, _, ok2 := bash.ExecScript(context.TODO(), options.BladeBinPath, queryCmd)
-						if ok2 {
-							response2 := parseResult(result2)
-							if response2 != nil && response2.Success && response2.Result != nil {
-								if resultMap2, ok2 := response2.Result.(map[string]interface{}); ok2 {
-									if statuses2, ok2 := resultMap2["statuses"].([]interface{}); ok2 && len(statuses2) > 0 {
-										logrus.Infof("K8s experiment status found after retry, uid: %s, statuses count: %d", uid, len(statuses2))
-										return
-									}
-								}
-							}
-						}
-						// 如果还是没有 statuses，但 success 为 true，也认为已完成（可能是异步场景）
-						logrus.Infof("K8s experiment accepted (success=true), uid: %s, statuses may be empty", uid)
-						return
-					}
-				} else if resultStr, ok := response.Result.(string); ok {
-					// 如果 Result 是字符串，尝试解析 JSON
-					var resultMap map[string]interface{}
-					if err := json.Unmarshal([]byte(resultStr), &resultMap); err == nil {
-						if statuses, ok := resultMap["statuses"].([]interface{}); ok {
-							if len(statuses) > 0 {
-								logrus.Infof("K8s experiment status found, uid: %s, statuses count: %d", uid, len(statuses))
-								return
-							}
-						}
-						if success, ok := resultMap["success"].(bool); ok && success {
-							logrus.Infof("K8s experiment accepted (success=true), uid: %s", uid)
-							return
-						}
-					}
-				} else if statusesArray, ok := response.Result.([]interface{}); ok {
-					// 如果直接返回数组
-					if len(statusesArray) > 0 {
-						logrus.Infof("K8s experiment status found, uid: %s, statuses count: %d", uid, len(statusesArray))
-						return
-					}
-				}
-			}
-
-			logrus.Debugf("K8s experiment status not ready yet, uid: %s, will retry", uid)
-		}
-	}
-}
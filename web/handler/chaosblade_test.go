@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/executor"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+func TestBladeResult(t *testing.T) {
+	failure := `{"code":47000,"success":false,"error":"the pid not found"}`
+	tests := []struct {
+		name   string
+		result executor.Result
+		want   string
+	}{
+		{"stdout", executor.Result{Stdout: `{"code":200,"success":true,"result":"uid"}`, Stderr: "warning"}, `{"code":200,"success":true,"result":"uid"}`},
+		{"stderr", executor.Result{ExitCode: 1, Stderr: failure}, failure},
+		{"stderr with logs", executor.Result{ExitCode: 1, Stdout: "getcwd: cannot access parent directories", Stderr: failure}, failure},
+		{"no json", executor.Result{ExitCode: 1, Stdout: "out", Stderr: "err"}, "out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bladeResult(&tt.result); got != tt.want {
+				t.Errorf("bladeResult() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	request := &transport.Request{Headers: map[string]string{}, Params: map[string]string{}}
+	request.AddParam("operation", "create").AddParam("target", "cpu").AddParam("action", "fullload")
+	handler := &ChaosbladeHandler{}
+	if got := handler.Command(request); !strings.HasSuffix(got, " create cpu fullload") {
+		t.Errorf("Command() = %q, want the built command", got)
+	}
+	request.AddHeader(executedCommandHeader, "create cpu fullload --uid 9b4cf3a2d1e0")
+	if got := handler.Command(request); !strings.HasSuffix(got, " create cpu fullload --uid 9b4cf3a2d1e0") {
+		t.Errorf("Command() = %q, want the executed command", got)
+	}
+}
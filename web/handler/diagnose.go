@@ -0,0 +1,125 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/diagnose"
+	"github.com/chaosblade-io/chaos-agent/metricreport"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+// bladeDiagnoseCommands is the blade outputs in the bundle, key is the file name
+var bladeDiagnoseCommands = map[string]string{
+	"blade-version.txt":        "version",
+	"blade-status-create.txt":  "status --type create",
+	"blade-status-prepare.txt": "status --type prepare",
+}
+
+type DiagnoseHandler struct {
+	chaosblade            *ChaosbladeHandler
+	reportMetricConfigMap *metricreport.ReportMetricConfigMap
+
+	transportClient *transport.TransportClient
+}
+
+// DiagnoseResult is the bundle info, Content is the base64 of bundle if not uploaded
+type DiagnoseResult struct {
+	Name     string `json:"name"`
+	Size     int    `json:"size"`
+	Uploaded bool   `json:"uploaded"`
+	Content  string `json:"content,omitempty"`
+}
+
+func NewDiagnoseHandler(transportClient *transport.TransportClient, chaosblade *ChaosbladeHandler,
+	reportMetricConfigMap *metricreport.ReportMetricConfigMap,
+) *DiagnoseHandler {
+	return &DiagnoseHandler{
+		chaosblade:            chaosblade,
+		reportMetricConfigMap: reportMetricConfigMap,
+		transportClient:       transportClient,
+	}
+}
+
+// Handle assembles the diagnostics bundle, params: upload (true|false), logSize (bytes of agent log tail)
+func (dh *DiagnoseHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Info("Receive server diagnose request")
+
+	logSize, err := parseInt64Param(request, "logSize")
+	if err != nil {
+		return transport.ReturnFail(transport.ParameterTypeError, "logSize")
+	}
+	upload := request.Params["upload"] == "true"
+
+	bundle := diagnose.NewBundle(logSize)
+	for name, args := range bladeDiagnoseCommands {
+		result, errMsg, _ := execBlade(context.TODO(), strings.Fields(args)...)
+		bundle.AddFile(name, []byte(fmt.Sprintf("$ blade %s\n%s\n%s", args, result, errMsg)))
+	}
+	if dh.chaosblade != nil {
+		bundle.AddJson("experiments.json", dh.chaosblade.Experiments())
+	}
+	if dh.reportMetricConfigMap != nil {
+		bundle.AddJson("collectors.json", dh.reportMetricConfigMap.State())
+	}
+
+	content, err := bundle.Bytes()
+	if err != nil {
+		logrus.Warningf("[diagnose] assemble bundle failed, err: %v", err)
+		return transport.ReturnFail(transport.ServerError, err.Error())
+	}
+	result := &DiagnoseResult{
+		Name: bundle.Name,
+		Size: len(content),
+	}
+	encoded := base64.StdEncoding.EncodeToString(content)
+	if !upload {
+		result.Content = encoded
+		return transport.ReturnSuccessWithResult(result)
+	}
+
+	if err := dh.upload(bundle.Name, encoded); err != nil {
+		logrus.Warningf("[diagnose] upload bundle %s failed, err: %v", bundle.Name, err)
+		return transport.ReturnFail(transport.ServerError, err.Error())
+	}
+	result.Uploaded = true
+	return transport.ReturnSuccessWithResult(result)
+}
+
+func (dh *DiagnoseHandler) upload(name, encoded string) error {
+	uri, ok := transport.TransportUriMap[transport.API_DIAGNOSE]
+	if !ok {
+		return fmt.Errorf("diagnose upload uri not found")
+	}
+	request := transport.NewRequest()
+	request.AddParam("name", name).AddParam("bundle", encoded)
+	response, err := dh.transportClient.Invoke(uri, request, true)
+	if err != nil {
+		return err
+	}
+	if !response.Success {
+		return fmt.Errorf("upload failed, %s", response.Error)
+	}
+	logrus.Infof("[diagnose] upload bundle %s success", name)
+	return nil
+}
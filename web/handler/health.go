@@ -0,0 +1,51 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/conn/heartbeat"
+	"github.com/chaosblade-io/chaos-agent/diagnose"
+	"github.com/chaosblade-io/chaos-agent/pkg/limiter"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+type HealthHandler struct{}
+
+type Health struct {
+	// Heartbeat is the recent heartbeat results, the latest is the last
+	Heartbeat   []heartbeat.HBSnapshot  `json:"heartbeat"`
+	Concurrency map[string]limiter.Stat `json:"concurrency"`
+}
+
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{}
+}
+
+func (hh *HealthHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Info("Receive server health request")
+	return transport.ReturnSuccessWithResult(GetHealth())
+}
+
+// GetHealth returns the heartbeat history and the concurrency of handlers
+func GetHealth() *Health {
+	return &Health{
+		Heartbeat:   diagnose.HeartbeatSnapshots(),
+		Concurrency: limiter.Stats(),
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/blade"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/progress"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+const (
+	InstallBladeAction  = "install"
+	RollbackBladeAction = "rollback"
+)
+
+type InstallBladeHandler struct {
+	chaosblade *ChaosbladeHandler
+	installer  *blade.Installer
+}
+
+func NewInstallBladeHandler(chaosblade *ChaosbladeHandler) *InstallBladeHandler {
+	return &InstallBladeHandler{
+		chaosblade: chaosblade,
+		installer:  newBladeInstaller(),
+	}
+}
+
+func newBladeInstaller() *blade.Installer {
+	return blade.NewInstaller(options.BladeHome, options.Opts.ChaosBladeVerifyKey,
+		options.Opts.ChaosBladeDownloadTimeout, options.Opts.UpgradeConfig.SafePointTimeout)
+}
+
+// Command describes the installation or rollback of chaosblade
+func (ibh *InstallBladeHandler) Command(request *transport.Request) string {
+	if request.Params["action"] == RollbackBladeAction {
+		return "rollback chaosblade"
+	}
+	return fmt.Sprintf("install chaosblade from %s", packageOf(request).Url)
+}
+
+// Handle installs the chaosblade package of the url param, or rolls back to the previous version if
+// action is rollback. It's refused while experiments are running unless force is true.
+func (ibh *InstallBladeHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Infof("Receive server install chaosblade request, params: %v", request.Params)
+
+	action := request.Params["action"]
+	if action == "" {
+		action = InstallBladeAction
+	}
+	if action != InstallBladeAction && action != RollbackBladeAction {
+		return transport.ReturnFail(transport.ParameterTypeError, "action")
+	}
+	if running := len(ibh.chaosblade.Experiments()); running > 0 && request.Params["force"] != "true" {
+		return transport.ReturnFail(transport.BladeInstallFailed,
+			fmt.Sprintf("%d experiments are running, destroy them first or set force to true", running))
+	}
+
+	reporter := progress.NewReporter(request.Headers[transport.Rid])
+	var result *blade.Result
+	var err error
+	if action == RollbackBladeAction {
+		result, err = ibh.installer.Rollback(reporter)
+	} else {
+		pkg := packageOf(request)
+		if pkg.Url == "" {
+			return transport.ReturnFail(transport.ParameterLess, "url")
+		}
+		result, err = ibh.installer.Install(pkg, reporter)
+	}
+
+	var response *transport.Response
+	if err != nil {
+		logrus.Warningf("[blade install] %s chaosblade failed, err: %s", action, err.Error())
+		response = transport.ReturnFail(transport.BladeInstallFailed, err.Error())
+	} else {
+		options.Opts.SetChaosBladeVersion(result.Version)
+		response = transport.ReturnSuccessWithResult(result)
+	}
+	reporter.Finish(response)
+	return response
+}
+
+// packageOf returns the package of the request, the url and checksum flags are used if absent
+func packageOf(request *transport.Request) blade.Package {
+	pkg := blade.Package{
+		Url:       request.Params["url"],
+		Sha256:    request.Params["sha256"],
+		Md5:       request.Params["md5"],
+		Signature: request.Params["signature"],
+	}
+	if pkg.Url == "" {
+		pkg.Url = options.Opts.ChaosBladeTarUrl
+		if pkg.Sha256 == "" && pkg.Md5 == "" {
+			pkg.Sha256 = options.Opts.ChaosBladeTarSha256
+			pkg.Insecure = options.Opts.ChaosBladeTarInsecure
+		}
+	}
+	return pkg
+}
+
+// InstallMissingBlade installs chaosblade from the blade.tar.url flag if it's not installed
+func InstallMissingBlade() {
+	if options.Opts.ChaosbladeVersion != "" || options.Opts.ChaosBladeTarUrl == "" {
+		return
+	}
+	if options.Opts.ChaosBladeTarSha256 == "" && !options.Opts.ChaosBladeTarInsecure {
+		logrus.Errorf("[blade install] blade.tar.sha256 is required to install missing chaosblade from %s, "+
+			"or set blade.tar.insecure to skip the checksum", options.Opts.ChaosBladeTarUrl)
+		return
+	}
+	go func() {
+		defer tools.PanicPrintStack()
+		installer := newBladeInstaller()
+		result, err := installer.Install(blade.Package{
+			Url:      options.Opts.ChaosBladeTarUrl,
+			Sha256:   options.Opts.ChaosBladeTarSha256,
+			Insecure: options.Opts.ChaosBladeTarInsecure,
+		}, nil)
+		if err != nil {
+			logrus.Warningf("[blade install] install missing chaosblade from %s failed, err: %s",
+				options.Opts.ChaosBladeTarUrl, err.Error())
+			return
+		}
+		options.Opts.SetChaosBladeVersion(result.Version)
+	}()
+}
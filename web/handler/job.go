@@ -0,0 +1,49 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/job"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+type JobHandler struct {
+	jobs *job.Manager
+}
+
+func NewJobHandler(jobs *job.Manager) *JobHandler {
+	return &JobHandler{
+		jobs: jobs,
+	}
+}
+
+// Handle returns the job of the jobId param, or all jobs if jobId is empty
+func (jh *JobHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Debug("Receive server job request")
+
+	jobId := request.Params["jobId"]
+	if jobId == "" {
+		return transport.ReturnSuccessWithResult(jh.jobs.List())
+	}
+	found, ok := jh.jobs.Get(jobId)
+	if !ok {
+		return transport.ReturnFail(transport.JobNotFound, jobId)
+	}
+	return transport.ReturnSuccessWithResult(found)
+}
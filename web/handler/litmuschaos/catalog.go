@@ -0,0 +1,163 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package litmuschaos
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/catalog"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+const (
+	CatalogListAction     = "list"
+	CatalogPrefetchAction = "prefetch"
+	CatalogImportAction   = "import"
+
+	bundleDownloadTimeout = 5 * time.Minute
+)
+
+var (
+	catalogOnce     sync.Once
+	catalogInstance *catalog.Catalog
+)
+
+// litmusCatalog returns the catalog of the litmus.catalog.dir and litmus.hub.url flags
+func litmusCatalog() *catalog.Catalog {
+	catalogOnce.Do(func() {
+		dir := options.Opts.LitmusCatalogDir
+		if dir == "" {
+			dir = tools.GetLitmusCatalogPath()
+		}
+		catalogInstance = catalog.NewCatalog(dir, options.Opts.LitmusHubUrl)
+	})
+	return catalogInstance
+}
+
+// LitmusCatalogHandler manages the local catalog of litmus experiments
+type LitmusCatalogHandler struct {
+	catalog *catalog.Catalog
+}
+
+func NewLitmusCatalogHandler() *LitmusCatalogHandler {
+	return &LitmusCatalogHandler{catalog: litmusCatalog()}
+}
+
+// Command describes the catalog operation of the request
+func (lch *LitmusCatalogHandler) Command(request *transport.Request) string {
+	switch request.Params["action"] {
+	case CatalogPrefetchAction:
+		return fmt.Sprintf("litmus catalog prefetch %s %s", versionOf(request), request.Params["experiments"])
+	case CatalogImportAction:
+		return fmt.Sprintf("litmus catalog import %s", request.Params["url"])
+	}
+	return ""
+}
+
+// Handle lists the experiments of the version param, or all versions if it's empty. The prefetch action
+// fetches the experiments param from the hub, eg: generic/pod-delete,generic/pod-cpu-hog, and verifies
+// them by the checksums param, or by the checksum file of the version in the catalog. The import action
+// extracts the tar.gz bundle of the url param, which is an http url or a file name in the
+// litmus.catalog.import.dir, and verifies it by the sha256 param if present. The OCI artifacts are not
+// pulled by the agent, pull them as the tar.gz bundle, eg: by oras, and import it.
+func (lch *LitmusCatalogHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Infof("[litmus catalog] receive request, params: %v", request.Params)
+
+	var entries []catalog.Entry
+	var err error
+	switch action := request.Params["action"]; action {
+	case "", CatalogListAction:
+		entries, err = lch.catalog.List(request.Params["version"])
+	case CatalogPrefetchAction:
+		experiments := tools.SplitValues(request.Params["experiments"])
+		if len(experiments) == 0 {
+			return transport.ReturnFail(transport.ParameterLess, "experiments")
+		}
+		version := versionOf(request)
+		if version == "" {
+			return transport.ReturnFail(transport.ParameterLess, "version")
+		}
+		checksums := make(map[string]string)
+		if value := request.Params["checksums"]; value != "" {
+			if err := json.Unmarshal([]byte(value), &checksums); err != nil {
+				return transport.ReturnFail(transport.ParameterTypeError, "checksums")
+			}
+		}
+		entries, err = lch.catalog.Prefetch(version, experiments, []string{LITMUS_EXPERIMENT, LITMUS_RBAC}, checksums)
+	case CatalogImportAction:
+		url := request.Params["url"]
+		if url == "" {
+			return transport.ReturnFail(transport.ParameterLess, "url")
+		}
+		entries, err = lch.importBundle(url, request.Params["sha256"])
+	default:
+		return transport.ReturnFail(transport.ParameterTypeError, "action")
+	}
+	if err != nil {
+		logrus.Warningf("[litmus catalog] %s failed, err: %v", request.Params["action"], err)
+		return transport.ReturnFail(transport.LitmusCatalogFailed, err.Error())
+	}
+	return transport.ReturnSuccessWithResult(entries)
+}
+
+func (lch *LitmusCatalogHandler) importBundle(url, sha256 string) ([]catalog.Entry, error) {
+	var bundle io.ReadCloser
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		client := &http.Client{Timeout: bundleDownloadTimeout}
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("download `%s` failed! response code: %d", url, resp.StatusCode)
+		}
+		bundle = resp.Body
+	} else {
+		dir := options.Opts.LitmusCatalogImportDir
+		if dir == "" {
+			return nil, fmt.Errorf("local bundle is not allowed, litmus.catalog.import.dir is not configured")
+		}
+		// the bundle can't escape the import directory, neither by the name nor by the symlinks
+		file, err := os.OpenInRoot(dir, url)
+		if err != nil {
+			return nil, err
+		}
+		bundle = file
+	}
+	defer bundle.Close()
+	return lch.catalog.Import(bundle, sha256)
+}
+
+// versionOf returns the version param, or the installed litmus version if it's empty
+func versionOf(request *transport.Request) string {
+	if version := request.Params["version"]; version != "" {
+		return version
+	}
+	return options.Opts.LitmusChaosVerison
+}
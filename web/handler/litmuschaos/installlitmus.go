@@ -17,10 +17,14 @@
 package litmuschaos
 
 import (
+	"fmt"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/chaosblade-io/chaos-agent/pkg/helm3"
 	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/progress"
+	"github.com/chaosblade-io/chaos-agent/pkg/upgrade"
 	"github.com/chaosblade-io/chaos-agent/transport"
 )
 
@@ -40,14 +44,15 @@ func NewInstallLitmusHandler(helm *helm3.Helm) *InstallLitmusHandler {
 }
 
 func (ilh *InstallLitmusHandler) Handle(request *transport.Request) *transport.Response {
-	logrus.Infof("litmuschaos install: %+v", request)
+	logrus.Debugf("litmuschaos install: %+v", request)
 	//if handler.litmus.IsStopped() {
 	//	return transport.ReturnFail(transport.Code[transport.ServerError], "litmuschaos service stopped")
 	//}
-	//todo 这里需要加上
-	//if handler.litmus.upgrade.NeedWait() {
-	//	return transport.ReturnFail(transport.Code[transport.Upgrading], "agent is in upgrading")
-	//}
+	release, ok := upgrade.Enter()
+	if !ok {
+		return transport.ReturnFail(transport.Upgrading, "retry later")
+	}
+	defer release()
 
 	// 对请求参数进行校验
 	version, ok := request.Params["version"]
@@ -58,10 +63,18 @@ func (ilh *InstallLitmusHandler) Handle(request *transport.Request) *transport.R
 	vals := map[string]string{
 		"namespace": LitmusHelmNamespace,
 	}
-	return ilh.installLitmus(version, vals)
+	reporter := progress.NewReporter(request.Headers[transport.Rid])
+	response := ilh.installLitmus(version, vals, reporter)
+	reporter.Finish(response)
+	return response
+}
+
+// Command describes the helm install of litmus
+func (ilh *InstallLitmusHandler) Command(request *transport.Request) string {
+	return fmt.Sprintf("helm install %s %s -n %s", LitmusHelmName, getLitmusUrlByVersionAndEnv(request.Params["version"]), LitmusHelmNamespace)
 }
 
-func (ilh *InstallLitmusHandler) installLitmus(version string, vals map[string]string) *transport.Response {
+func (ilh *InstallLitmusHandler) installLitmus(version string, vals map[string]string, reporter *progress.Reporter) *transport.Response {
 	if ilh.Helm == nil {
 		logrus.Warnf("[install litmus] failed, err: helm instance is nil")
 		return transport.ReturnFail(transport.Helm3ExecError, "helm instance is nil")
@@ -70,6 +83,7 @@ func (ilh *InstallLitmusHandler) installLitmus(version string, vals map[string]s
 	chartUrl := getLitmusUrlByVersionAndEnv(version)
 
 	// pull chart to cache
+	reporter.Phase("pull chart %s", chartUrl)
 	err := ilh.Helm.PullChart(chartUrl)
 	if err != nil {
 		logrus.Warnf("[install litmus] pull chart failed! err: %s", err.Error())
@@ -77,6 +91,7 @@ func (ilh *InstallLitmusHandler) installLitmus(version string, vals map[string]s
 	}
 
 	// load chart
+	reporter.Phase("load chart")
 	charts, err := ilh.Helm.LoadChart(chartUrl)
 	if err != nil {
 		logrus.Warnf("[install litmus] load chart by url `%s`, failed! err: %s", chartUrl, err.Error())
@@ -84,11 +99,13 @@ func (ilh *InstallLitmusHandler) installLitmus(version string, vals map[string]s
 	}
 
 	// install chart
+	reporter.Phase("install chart %s in namespace %s", LitmusHelmName, vals["namespace"])
 	err = ilh.Helm.Install(charts, vals)
 	if err != nil {
 		logrus.Warnf("[install litmus] install failed, err: %s", err.Error())
 		return transport.ReturnFail(transport.Helm3ExecError, err.Error())
 	}
 	options.Opts.LitmusChaosVerison = version
+	reporter.Phase("litmus %s installed", version)
 	return transport.ReturnSuccess()
 }
@@ -23,13 +23,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"os"
 	"strings"
 	"time"
 
-	"github.com/openebs/maya/pkg/util/retry"
 	"github.com/sirupsen/logrus"
 	coreV1 "k8s.io/api/core/v1"
 	rbacV1 "k8s.io/api/rbac/v1"
@@ -45,6 +41,7 @@ import (
 	"github.com/chaosblade-io/chaos-agent/conn/asyncreport"
 	"github.com/chaosblade-io/chaos-agent/pkg/kubernetes"
 	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
 	"github.com/chaosblade-io/chaos-agent/pkg/tools"
 
 	//"github.com/chaosblade-io/chaos-agent/pkg/options"
@@ -94,6 +91,19 @@ func (lh *LitmusChaosHandler) Handle(request *transport.Request) *transport.Resp
 	return transport.ReturnFail(transport.ServerError, fmt.Sprintf("litmus exec failed, no such action: %s", chaosAction))
 }
 
+// Command describes the litmus operation of the request
+func (lh *LitmusChaosHandler) Command(request *transport.Request) string {
+	chaosAction := request.Params["chaosAction"]
+	namespace := request.Params["namespace"]
+	if namespace == "" {
+		namespace = coreV1.NamespaceDefault
+	}
+	if _, ok := options.DestroyOperation[chaosAction]; ok {
+		return fmt.Sprintf("litmus %s engine %s -n %s", chaosAction, request.Params["name"], namespace)
+	}
+	return fmt.Sprintf("litmus %s %s/%s -n %s", chaosAction, request.Params["experimentType"], request.Params["experimentName"], namespace)
+}
+
 // destroy
 func (lh *LitmusChaosHandler) destroyParamerAndExec(ctx context.Context, request *transport.Request) *transport.Response {
 	name, ok := request.Params["name"]
@@ -184,50 +194,50 @@ func (lh *LitmusChaosHandler) createExec(ctx context.Context, experimentType, ex
 }
 
 func (lh *LitmusChaosHandler) AsyncHandlerResultStatus(ctx context.Context, name, namespace, experimentName string) {
-	status := "Unknown"
+	source := &resultSource{
+		handler:        lh,
+		namespace:      namespace,
+		experimentName: experimentName,
+		settled:        time.Now().Add(resultSettleTime),
+	}
+	result := status.Wait(ctx, "litmus", name, source, status.Pending)
 	var errorStr string
-	time.Sleep(15 * time.Second)
-	err := retry.
-		Times(90).
-		Wait(1 * time.Second).
-		Try(func(attempt uint) error {
-			chaosResult, err := lh.handlerResult(ctx, name, namespace, experimentName)
-			if err != nil {
-				return err
-			}
-			failedRuns := chaosResult.Status.History.FailedRuns
-			if chaosResult.Spec.EngineName != "" && failedRuns == 0 {
-				return nil
-			}
-			return fmt.Errorf("failed in chaos injection phase")
-
-			// todo: 拿到的chaosresult里面的status.ExperimentStatus，一直是空，所以根据这个来判断不好用
-
-			// https://github.com/litmuschaos/chaos-operator/issues/368 所以暂时通过先sleep再感觉faile个数来判断是否OK
-			//phase := chaosResult.Status.ExperimentStatus.Phase
-			//verdict := chaosResult.Status.ExperimentStatus.Verdict
-			//if phase == "" || verdict == "" {
-			//	return fmt.Errorf("chaosengine not ready")
-			//}
-			//if phase == "Running" && verdict == "Awaited" {
-			//	return nil
-			//}
-			//if phase == "Completed" && (verdict == "Pass" || verdict == "Stopped") {
-			//	return nil
-			//}
-			//return fmt.Errorf(chaosResult.Status.ExperimentStatus.FailStep)
-		})
-	if err != nil {
-		errorStr = fmt.Sprintf("inject fault failed, err: %s", err.Error())
-		status = "Error"
-	} else {
-		status = "Success"
+	if result.Status != status.Success {
+		errorStr = fmt.Sprintf("inject fault failed, err: %s", result.Error)
+		result.Status = status.Error
 	}
 
 	// async report result to server
 	uri := transport.TransportUriMap[transport.API_CHAOSBLADE_ASYNC]
 	ar := asyncreport.NewClientCloseHandler(lh.transportClient)
-	ar.ReportStatus(name, status, errorStr, LitmusHelmName, uri)
+	ar.ReportStatus(name, result.Status, errorStr, LitmusHelmName, uri)
+}
+
+// resultSettleTime is how long the chaos result is observed before it's regarded as successful,
+// the status.ExperimentStatus of chaosresult is always empty, so the failed runs are checked
+// after the engine runs for a while, see https://github.com/litmuschaos/chaos-operator/issues/368
+const resultSettleTime = 15 * time.Second
+
+// resultSource reads the injection status from the chaos result of the engine
+type resultSource struct {
+	handler        *LitmusChaosHandler
+	namespace      string
+	experimentName string
+	settled        time.Time
+}
+
+func (s *resultSource) Status(ctx context.Context, name string) (status.Result, error) {
+	chaosResult, err := s.handler.handlerResult(ctx, name, s.namespace, s.experimentName)
+	if err != nil {
+		return status.Result{}, err
+	}
+	if chaosResult.Status.History.FailedRuns > 0 {
+		return status.Result{Status: status.Error, Error: "failed in chaos injection phase"}, nil
+	}
+	if chaosResult.Spec.EngineName != "" && time.Now().After(s.settled) {
+		return status.Result{Status: status.Success}, nil
+	}
+	return status.Result{Status: status.Pending}, nil
 }
 
 // prepareLitmus before inject fault, need create experiment
@@ -445,44 +455,8 @@ func (lh *LitmusChaosHandler) handlerResult(ctx context.Context, name, namespace
 	return lh.LitmusClientSet.ChaosResults(namespace).Get(ctx, chaosResultName, metaV1.GetOptions{})
 }
 
-// Download from oss
+// DownloadLitmus returns the object yaml of the experiment from the local catalog, which is fetched
+// from the hub if it's absent and the hub is configured
 func DownloadLitmus(experimentType, experimentName, objectType string) ([]byte, error) {
-	version := options.Opts.LitmusChaosVerison
-	localFilePath := fmt.Sprintf("%s/%s/%s/%s.yaml", version, experimentType, experimentName, objectType)
-	if tools.IsExist(localFilePath) {
-		return ioutil.ReadFile(localFilePath)
-	}
-
-	// download url
-	// eg: https://hub.litmuschaos.io/api/chaos/1.13.5?file=charts/generic/pod-delete/experiment.yaml
-	experimentUrl := fmt.Sprintf("https://hub.litmuschaos.io/api/chaos/%s?file=charts/%s/%s/%s.yaml", version, experimentType, experimentName, objectType)
-	resp, err := http.Get(experimentUrl)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if resp.Body != nil {
-			_ = resp.Body.Close()
-		}
-	}()
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("download `%s` failed! response code: %d", experimentUrl, resp.StatusCode)
-	}
-
-	// create file
-	tools.IsExist(localFilePath)
-	file, err := os.Create(localFilePath)
-	os.Chmod(localFilePath, 0o744)
-	defer file.Close()
-
-	// copy url response to file
-	buf := make([]byte, 0)
-	for {
-		n, _ := resp.Body.Read(buf)
-		if 0 == n {
-			break
-		}
-		file.WriteString(string(buf[:n]))
-	}
-	return ioutil.ReadAll(resp.Body)
+	return litmusCatalog().Get(options.Opts.LitmusChaosVerison, experimentType, experimentName, objectType)
 }
@@ -17,10 +17,13 @@
 package litmuschaos
 
 import (
+	"fmt"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/chaosblade-io/chaos-agent/pkg/helm3"
 	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/upgrade"
 	"github.com/chaosblade-io/chaos-agent/transport"
 )
 
@@ -39,15 +42,21 @@ func NewUninstallLitmusHandler(helm *helm3.Helm) *UninstallLitmusHandler {
 }
 
 func (ulh *UninstallLitmusHandler) Handle(request *transport.Request) *transport.Response {
-	logrus.Infof("litmuschaos uninstall: %+v", request)
-	//todo 更新这块需要补充
-	//if handler.litmus.upgrade.NeedWait() {
-	//	return transport.ReturnFail(transport.Code[transport.Upgrading], "agent is in upgrading")
-	//}
+	logrus.Debugf("litmuschaos uninstall: %+v", request)
+	release, ok := upgrade.Enter()
+	if !ok {
+		return transport.ReturnFail(transport.Upgrading, "retry later")
+	}
+	defer release()
 
 	return ulh.uninstallLitmus()
 }
 
+// Command describes the helm uninstall of litmus
+func (ulh *UninstallLitmusHandler) Command(request *transport.Request) string {
+	return fmt.Sprintf("helm uninstall %s -n %s", LitmusHelmName, LitmusHelmNamespace)
+}
+
 func (ulh *UninstallLitmusHandler) uninstallLitmus() *transport.Response {
 	err := ulh.Helm.Uninstall()
 	if err != nil {
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/log"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+type LogLevelHandler struct{}
+
+func NewLogLevelHandler() *LogLevelHandler {
+	return &LogLevelHandler{}
+}
+
+// Handle changes the log level, params: level (debug|info|warn|error), duration (eg: 30m, revert after it).
+// Only the current level is returned if level is empty.
+func (lh *LogLevelHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Info("Receive server log level request")
+
+	level := request.Params["level"]
+	if level == "" {
+		return transport.ReturnSuccessWithResult(log.GetLevelState())
+	}
+	var duration time.Duration
+	if value := request.Params["duration"]; value != "" {
+		var err error
+		if duration, err = time.ParseDuration(value); err != nil {
+			return transport.ReturnFail(transport.ParameterTypeError, "duration")
+		}
+	}
+	state, err := log.SetLevel(level, duration)
+	if err != nil {
+		return transport.ReturnFail(transport.ParameterTypeError, "level")
+	}
+	return transport.ReturnSuccessWithResult(state)
+}
+
+type LogTailHandler struct{}
+
+func NewLogTailHandler() *LogTailHandler {
+	return &LogTailHandler{}
+}
+
+// Handle reads the agent log, params: rid (only the entries of the request), offset (the offset returned
+// by the last call to follow the log, the tail of log is read if absent), size (max bytes to read)
+func (lh *LogTailHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Debug("Receive server log tail request")
+
+	offset := int64(-1)
+	if request.Params["offset"] != "" {
+		var err error
+		if offset, err = parseInt64Param(request, "offset"); err != nil || offset < 0 {
+			return transport.ReturnFail(transport.ParameterTypeError, "offset")
+		}
+	}
+	size, err := parseInt64Param(request, "size")
+	if err != nil {
+		return transport.ReturnFail(transport.ParameterTypeError, "size")
+	}
+	if size > log.DefaultTailSize {
+		size = log.DefaultTailSize
+	}
+
+	var match func(string) bool
+	if rid := request.Params["rid"]; rid != "" {
+		match = log.RidMatcher(rid)
+	}
+	result, err := log.Tail(tools.GetAgentLogFilePath(), offset, size, match)
+	if err != nil {
+		logrus.Warningf("[log] tail agent log failed, err: %v", err)
+		return transport.ReturnFail(transport.ServerError, err.Error())
+	}
+	return transport.ReturnSuccessWithResult(result)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"path"
+
+	"github.com/sirupsen/logrus"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/kubernetes"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+	"github.com/chaosblade-io/chaos-agent/pkg/preflight"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+// PreflightHandler checks whether the experiment can be injected on this host, nothing is injected
+type PreflightHandler struct {
+	checker *preflight.Checker
+}
+
+func NewPreflightHandler(k8sInstance *kubernetes.Channel) *PreflightHandler {
+	var pods preflight.PodCounter
+	if k8sInstance != nil && k8sInstance.ClientSet != nil {
+		pods = func(ctx context.Context, namespace, selector string, names []string) (int, error) {
+			list, err := k8sInstance.ClientSet.CoreV1().Pods(namespace).List(ctx, metaV1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				return 0, err
+			}
+			if len(names) == 0 {
+				return len(list.Items), nil
+			}
+			count := 0
+			for _, pod := range list.Items {
+				for _, name := range names {
+					if pod.Name == name {
+						count++
+					}
+				}
+			}
+			return count, nil
+		}
+	}
+	return &PreflightHandler{checker: preflight.NewChecker(pods)}
+}
+
+// Handle takes the same experiment params as chaosblade, the operation is create by default. The
+// report is returned as the result, the request fails only if the params are illegal.
+func (ph *PreflightHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Infof("Receive server preflight request, params: %v", request.Params)
+
+	params := make(map[string]string, len(request.Params)+1)
+	for name, value := range request.Params {
+		params[name] = value
+	}
+	if params["operation"] == "" {
+		params["operation"] = experiment.CreateOperation
+	}
+	exp, err := experiment.Parse(params)
+	if err != nil {
+		return transport.ReturnFail(transport.ExperimentInvalid, err.Error())
+	}
+
+	report := ph.checker.Run(context.Background(), exp)
+	if tools.IsExist(options.BladeBinPath) {
+		report.Add("chaosblade", preflight.Pass, options.Opts.ChaosbladeVersion)
+	} else {
+		report.Add("chaosblade", preflight.Fail, transport.Errors[transport.ChaosbladeFileNotFound])
+	}
+	if spec := experiment.GetSpec(path.Join(options.BladeHome, bladeSpecDir)); spec == nil {
+		report.Add("spec", preflight.Skip, "blade spec files are not found")
+	} else if err := spec.Validate(exp); err != nil {
+		report.Add("spec", preflight.Fail, err.Error())
+	} else {
+		report.Add("spec", preflight.Pass, "valid")
+	}
+	if reason := policy.Check(exp.Args()); reason != "" {
+		report.Add("policy", preflight.Fail, reason)
+	} else {
+		report.Add("policy", preflight.Pass, "allowed")
+	}
+	logrus.Infof("[preflight] %s, passed: %t", report.Command, report.Passed)
+	return transport.ReturnSuccessWithResult(report)
+}
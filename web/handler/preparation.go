@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/conn/asyncreport"
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+// preparationPhase is one direction of the preparation lifecycle, the status is tracked while it's pending
+type preparationPhase struct {
+	name    string
+	pending []string
+}
+
+var (
+	// installPhase follows prepare, the preparation turns into Running or Error
+	installPhase = preparationPhase{name: "install", pending: []string{status.Pending}}
+	// uninstallPhase follows revoke, the preparation turns into Destroyed or Error
+	uninstallPhase = preparationPhase{name: "uninstall", pending: []string{status.Pending, status.Running}}
+)
+
+// preparationLifecycle is how the preparation of one program type is reported, the deadline of
+// the status is configured by the program type, see status.DefaultDeadlines
+type preparationLifecycle struct {
+	// legacyApis are reported besides the common preparation api for compatibility, keyed by phase
+	legacyApis map[string]string
+}
+
+// preparationLifecycles are keyed by the program type of prepare, eg: blade prepare jvm
+var preparationLifecycles = map[string]preparationLifecycle{
+	"jvm": {
+		legacyApis: map[string]string{
+			installPhase.name:   transport.API_JAVA_INSTALL,
+			uninstallPhase.name: transport.API_JAVA_UNINSTALL,
+		},
+	},
+}
+
+func lifecycleOf(programType string) preparationLifecycle {
+	return preparationLifecycles[programType]
+}
+
+// trackPreparation waits for the preparation status until it's not pending and reports it, the uid is
+// forgotten if the installation fails
+func (ch *ChaosbladeHandler) trackPreparation(uid, programType string, phase preparationPhase) {
+	lifecycle := lifecycleOf(programType)
+	logrus.Debugf("[preparation] track %s of %s, uid: %s", phase.name, programType, uid)
+	result := status.Wait(context.TODO(), programType, uid, prepareSource, phase.pending...)
+	if phase.name == installPhase.name && result.Status == status.Error {
+		ch.forget(uid)
+	}
+	logrus.Infof("[preparation] %s of %s finished, uid: %s, status: %s, err: %s",
+		phase.name, programType, uid, result.Status, result.Error)
+
+	ar := asyncreport.NewClientCloseHandler(ch.transportClient)
+	if uri, ok := transport.TransportUriMap[transport.API_PREPARATION]; ok {
+		ar.ReportPreparationStatus(uid, programType, phase.name, result.Status, result.Error, uri)
+	} else {
+		logrus.Warnf("[preparation] report uri is null!")
+	}
+	if api, ok := lifecycle.legacyApis[phase.name]; ok {
+		if uri, ok := transport.TransportUriMap[api]; ok {
+			// the legacy uris expect the status of blade
+			ar.ReportStatus(uid, result.RawStatus(), result.Error, "", uri)
+		}
+	}
+}
+
+// trackRevoke tracks the uninstallation, the program type is read from the preparation record
+func (ch *ChaosbladeHandler) trackRevoke(uid string) {
+	record, ok, err := prepareSource.Record(context.TODO(), uid)
+	if err != nil || !ok {
+		logrus.Warningf("Query preparation err, %v, found: %t, uid: %s", err, ok, uid)
+		return
+	}
+	ch.trackPreparation(uid, record.ProgramType, uninstallPhase)
+}
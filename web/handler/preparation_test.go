@@ -0,0 +1,46 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+func TestLifecycleOf(t *testing.T) {
+	tests := []struct {
+		programType  string
+		installApi   string
+		uninstallApi string
+	}{
+		{programType: "jvm", installApi: transport.API_JAVA_INSTALL, uninstallApi: transport.API_JAVA_UNINSTALL},
+		{programType: "cplus"},
+		{programType: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.programType, func(t *testing.T) {
+			lifecycle := lifecycleOf(tt.programType)
+			if got := lifecycle.legacyApis[installPhase.name]; got != tt.installApi {
+				t.Errorf("install api = %s, want %s", got, tt.installApi)
+			}
+			if got := lifecycle.legacyApis[uninstallPhase.name]; got != tt.uninstallApi {
+				t.Errorf("uninstall api = %s, want %s", got, tt.uninstallApi)
+			}
+		})
+	}
+}
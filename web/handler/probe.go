@@ -0,0 +1,142 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/probe"
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+const (
+	// AbortedStatus is reported if the experiment is destroyed because a probe breached the steady state
+	AbortedStatus = "Aborted"
+
+	// ProbeEvent is the event type of the probe evidences during and after the experiments
+	ProbeEvent = "probe"
+
+	probeAuditHandler = "chaosblade/probe"
+)
+
+// withProbes reads the probes param, which is only accepted by create
+func withProbes(cmd *bladeCommand, request *transport.Request) (*bladeCommand, *transport.Response) {
+	content := request.Params["probes"]
+	if content == "" {
+		return cmd, nil
+	}
+	if !options.CreateOperation[cmd.arg(0)] {
+		return nil, transport.ReturnFail(transport.ParameterTypeError, "probes, only create accepts probes")
+	}
+	probes, err := probe.Parse(content)
+	if err != nil {
+		return nil, transport.ReturnFail(transport.ParameterTypeError, fmt.Sprintf("probes, %s", err.Error()))
+	}
+	cmd.Probes = probes
+	return cmd, nil
+}
+
+// abortByProbe destroys the experiment immediately and reports the evidences of the breached probe
+func (ch *ChaosbladeHandler) abortByProbe(uid string, evidences []probe.Evidence) {
+	last := evidences[len(evidences)-1]
+	cmd := newArgsCommand([]string{experiment.DestroyOperation, uid})
+	logrus.Warningf("[probe] experiment %s is aborted by probe %s, %s", uid, last.Probe, last.Detail)
+	response := ch.execInFlight(cmd, options.Opts.UpgradeConfig.SafePointTimeout)
+	audit.Record(&audit.Entry{
+		Handler: probeAuditHandler,
+		Params:  map[string]string{"probe": last.Probe, "evidence": last.Detail},
+		Command: fmt.Sprintf("%s %s", options.BladeBinPath, cmd.Line),
+		Code:    response.Code,
+		Success: response.Success,
+		Error:   response.Error,
+	})
+
+	reported := AbortedStatus
+	errorMsg := fmt.Sprintf("aborted by probe %s, %s", last.Probe, last.Detail)
+	if !response.Success {
+		logrus.Warningf("[probe] destroy experiment %s failed, err: %s", uid, response.Error)
+		reported = status.Error
+		errorMsg = fmt.Sprintf("%s, but destroy failed: %s", errorMsg, response.Error)
+	}
+	if uri, ok := transport.TransportUriMap[transport.API_CHAOSBLADE_ASYNC]; ok {
+		ch.reportStatusFunc(uid, reported, errorMsg, uri)
+	}
+	ch.reportProbe(uid, "during", evidences)
+}
+
+// watchProbes watches the probes of the created experiment, they are kept in the registry so that
+// the watch is resumed after the agent restarts
+func (ch *ChaosbladeHandler) watchProbes(uid string, probes []probe.Probe) {
+	ch.probes.Watch(uid, probes, ch.abortByProbe)
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	ch.persistLocked()
+}
+
+// checkAfterExperiment checks the probes of the stopped experiment once more, and reports whether
+// the steady state is recovered
+func (ch *ChaosbladeHandler) checkAfterExperiment(uid string) {
+	defer tools.PanicPrintStack()
+	probes, ok := ch.probes.Stop(uid)
+	if !ok {
+		return
+	}
+	evidences, recovered := probe.CheckAll(context.Background(), probes)
+	logrus.Infof("[probe] steady state after experiment %s, recovered: %t", uid, recovered)
+	ch.reportProbe(uid, "after", evidences)
+}
+
+func (ch *ChaosbladeHandler) reportProbe(uid, phase string, evidences []probe.Evidence) {
+	uri, ok := transport.TransportUriMap[transport.API_EVENT]
+	if !ok {
+		logrus.Warnf("[probe] report uri is null!")
+		return
+	}
+	success := true
+	for _, evidence := range evidences {
+		success = success && evidence.Success
+	}
+	request := transport.NewRequest()
+	request.AddParam("type", ProbeEvent).
+		AddParam("uid", uid).
+		AddParam("phase", phase).
+		AddParam("success", strconv.FormatBool(success)).
+		AddParam("evidences", evidenceJson(evidences))
+	response, err := ch.transportClient.Invoke(uri, request, true)
+	if err != nil {
+		logrus.Warningf("[probe] report probe of %s err, %v", uid, err)
+		return
+	}
+	if !response.Success {
+		logrus.Warningf("[probe] report probe of %s failed, %s", uid, response.Error)
+	}
+}
+
+func evidenceJson(evidences []probe.Evidence) string {
+	bytes, _ := json.Marshal(evidences)
+	return string(bytes)
+}
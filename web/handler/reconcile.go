@@ -0,0 +1,227 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/pkg/upgrade"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+const (
+	// MissingStatus means the experiment known by agent is not found in blade
+	MissingStatus = "Missing"
+
+	// ExperimentStatusEvent is the event type of the status transitions
+	ExperimentStatusEvent = "experimentStatus"
+)
+
+// healthyStatus are the status of the experiments which are still in effect
+var healthyStatus = map[string]bool{
+	status.Pending: true,
+	status.Success: true,
+	status.Running: true,
+}
+
+// stoppedStatus are the status of the experiments which are not in effect anymore
+var stoppedStatus = map[string]bool{
+	status.Destroyed: true,
+	MissingStatus:    true,
+}
+
+// Transition is the status change of a known experiment
+type Transition struct {
+	Uid   string `json:"uid"`
+	Kind  string `json:"kind"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Error string `json:"error,omitempty"`
+}
+
+// Reconciler compares the experiments known by agent with the blade status periodically,
+// and reports the transitions to server
+type Reconciler struct {
+	chaosblade      *ChaosbladeHandler
+	transportClient *transport.TransportClient
+	interval        time.Duration
+	stopCh          chan struct{}
+	// last is the status of the known experiments in the last reconciliation
+	last map[string]string
+}
+
+func NewReconciler(chaosblade *ChaosbladeHandler, transportClient *transport.TransportClient, interval time.Duration) *Reconciler {
+	return &Reconciler{
+		chaosblade:      chaosblade,
+		transportClient: transportClient,
+		interval:        interval,
+		stopCh:          make(chan struct{}),
+		last:            make(map[string]string),
+	}
+}
+
+func (r *Reconciler) Start() error {
+	if r.interval <= 0 {
+		logrus.Infof("[reconcile] reconciliation is disabled")
+		return nil
+	}
+	ticker := time.NewTicker(r.interval)
+	go func() {
+		defer tools.PanicPrintStack()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stopCh:
+				return
+			case <-ticker.C:
+				r.reconcile()
+			}
+		}
+	}()
+	logrus.Infof("[reconcile] start successfully, interval: %s", r.interval)
+	return nil
+}
+
+func (r *Reconciler) Shutdown() {
+	close(r.stopCh)
+}
+
+func (r *Reconciler) reconcile() {
+	// blade is not queried while it's switched, the round is skipped
+	release, ok := upgrade.Enter()
+	if !ok {
+		logrus.Debugf("[reconcile] agent is upgrading, skip this round")
+		return
+	}
+	defer release()
+	known := r.chaosblade.knownExperiments()
+	if len(known) == 0 {
+		r.last = make(map[string]string)
+		return
+	}
+	records := make(map[string]status.Record)
+	for _, source := range []*status.BladeSource{createSource, prepareSource} {
+		kindRecords, err := source.Records(context.TODO())
+		if err != nil {
+			// skip this round, or all of the experiments would be reported as missing
+			logrus.Warningf("[reconcile] query blade status failed, err: %v", err)
+			return
+		}
+		for uid, record := range kindRecords {
+			records[uid] = record
+		}
+	}
+
+	transitions, last := diffStatus(known, r.last, records)
+	r.last = last
+	for _, transition := range transitions {
+		logrus.Warningf("[reconcile] experiment %s status changed from %s to %s, err: %s",
+			transition.Uid, transition.From, transition.To, transition.Error)
+		if stoppedStatus[transition.To] {
+			r.chaosblade.forget(transition.Uid)
+		}
+		r.report(transition)
+	}
+}
+
+func (r *Reconciler) report(transition Transition) {
+	uri, ok := transport.TransportUriMap[transport.API_EVENT]
+	if !ok {
+		logrus.Warnf("[reconcile] report uri is null!")
+		return
+	}
+	request := transport.NewRequest()
+	request.AddParam("type", ExperimentStatusEvent).
+		AddParam("uid", transition.Uid).
+		AddParam("kind", transition.Kind).
+		AddParam("from", transition.From).
+		AddParam("status", transition.To)
+	if transition.Error != "" {
+		request.AddParam("error", transition.Error)
+	}
+	response, err := r.transportClient.Invoke(uri, request, true)
+	if err != nil {
+		logrus.Warningf("[reconcile] report transition of %s err, %v", transition.Uid, err)
+		return
+	}
+	if !response.Success {
+		logrus.Warningf("[reconcile] report transition of %s failed, %s", transition.Uid, response.Error)
+	}
+}
+
+// diffStatus returns the transitions of the known experiments, known is uid to kind, last is uid to
+// the status in the last reconciliation. The experiment seen first time is reported only if unhealthy.
+func diffStatus(known, last map[string]string, records map[string]status.Record) ([]Transition, map[string]string) {
+	transitions := make([]Transition, 0)
+	current := make(map[string]string, len(known))
+	for uid, kind := range known {
+		to, errorMsg := MissingStatus, ""
+		if record, ok := records[uid]; ok {
+			to, errorMsg = status.Normalize(record.Status), record.Error
+		}
+		current[uid] = to
+		from, seen := last[uid]
+		if from == to || (!seen && healthyStatus[to]) {
+			continue
+		}
+		transitions = append(transitions, Transition{
+			Uid:   uid,
+			Kind:  kind,
+			From:  from,
+			To:    to,
+			Error: errorMsg,
+		})
+	}
+	return transitions, current
+}
+
+// knownExperiments returns uid to kind (create or prepare) of the running experiments recorded
+// by blade locally, the k8s experiments are recorded as custom resources and excluded
+func (ch *ChaosbladeHandler) knownExperiments() map[string]string {
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	known := make(map[string]string, len(ch.running))
+	for uid, args := range ch.running {
+		if len(args) < 2 || args[1] == "k8s" {
+			continue
+		}
+		switch {
+		case options.CreateOperation[args[0]]:
+			known[uid] = experiment.CreateOperation
+		case options.PrepareOperation[args[0]]:
+			known[uid] = experiment.PrepareOperation
+		}
+	}
+	return known
+}
+
+// forget removes the experiment which is not in effect anymore
+func (ch *ChaosbladeHandler) forget(uid string) {
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	delete(ch.running, uid)
+	delete(ch.deadlines, uid)
+	ch.persistLocked()
+	go ch.checkAfterExperiment(uid)
+}
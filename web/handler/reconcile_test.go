@@ -0,0 +1,74 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
+)
+
+func TestDiffStatus(t *testing.T) {
+	known := map[string]string{"a": "create", "b": "create", "c": "prepare", "d": "create", "e": "create"}
+	last := map[string]string{"a": "Success", "b": "Success", "c": "Running"}
+	records := map[string]status.Record{
+		"a": {Uid: "a", Status: "Success"},
+		"b": {Uid: "b", Status: "Error", Error: "process not found"},
+		"d": {Uid: "d", Status: "Success"},
+		"e": {Uid: "e", Status: "Destroyed"},
+	}
+	transitions, current := diffStatus(known, last, records)
+
+	want := map[string]Transition{
+		"b": {Uid: "b", Kind: "create", From: "Success", To: "Error", Error: "process not found"},
+		"c": {Uid: "c", Kind: "prepare", From: "Running", To: MissingStatus},
+		"e": {Uid: "e", Kind: "create", From: "", To: "Destroyed"},
+	}
+	got := make(map[string]Transition)
+	for _, transition := range transitions {
+		got[transition.Uid] = transition
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffStatus() transitions = %+v, want %+v", got, want)
+	}
+	wantCurrent := map[string]string{"a": "Success", "b": "Error", "c": MissingStatus, "d": "Success", "e": "Destroyed"}
+	if !reflect.DeepEqual(current, wantCurrent) {
+		t.Errorf("diffStatus() current = %v, want %v", current, wantCurrent)
+	}
+
+	// no transition if nothing changed
+	transitions, _ = diffStatus(known, current, records)
+	if len(transitions) != 0 {
+		t.Errorf("diffStatus() second round = %+v, want none", transitions)
+	}
+}
+
+func TestKnownExperiments(t *testing.T) {
+	ch := &ChaosbladeHandler{running: map[string][]string{
+		"a": {"create", "cpu", "fullload"},
+		"b": {"c", "network", "loss", "--interface", "eth0"},
+		"c": {"p", "jvm", "--pid", "1"},
+		"d": {"c", "k8s", "pod-cpu", "fullload"},
+		"e": {"status", "--type", "create"},
+		"f": {"create", "process", "kill", "--process", "java -jar x"},
+	}}
+	want := map[string]string{"a": "create", "b": "create", "c": "prepare", "f": "create"}
+	if got := ch.knownExperiments(); !reflect.DeepEqual(got, want) {
+		t.Errorf("knownExperiments() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/probe"
+	"github.com/chaosblade-io/chaos-agent/pkg/radius"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+// radiusAuditHandler is the audit handler name of the commands rejected by the blast radius limits
+const radiusAuditHandler = "chaosblade/radius"
+
+// registryRecord is a running experiment or preparation kept in the registry file
+type registryRecord struct {
+	Uid string `json:"uid"`
+	// Cmdline is kept for reading, Args is the source of truth
+	Cmdline  string     `json:"cmdline"`
+	Args     []string   `json:"args,omitempty"`
+	Deadline *time.Time `json:"deadline,omitempty"`
+	// Probes are watched during the experiment, the watch is resumed after restart
+	Probes []probe.Probe `json:"probes,omitempty"`
+}
+
+func registryFile() string {
+	if options.Opts.RegistryFile != "" {
+		return options.Opts.RegistryFile
+	}
+	return tools.GetRegistryFilePath()
+}
+
+// loadRegistry restores the experiments recorded by the previous agent, the ones not in effect
+// anymore are forgotten by the reconciler and the expired ones are destroyed by the watchdog
+func (ch *ChaosbladeHandler) loadRegistry() {
+	file := registryFile()
+	var records []registryRecord
+	loaded, err := tools.LoadJsonFile(file, &records)
+	if err != nil {
+		logrus.Errorf("[registry] load registry file %s failed, err: %v", file, err)
+		return
+	}
+	if !loaded {
+		return
+	}
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	for _, record := range records {
+		args := record.Args
+		if len(args) == 0 {
+			// the registry written by the previous version has no args
+			args = strings.Fields(record.Cmdline)
+		}
+		ch.running[record.Uid] = args
+		if record.Deadline != nil {
+			ch.deadlines[record.Uid] = *record.Deadline
+		}
+		if len(record.Probes) > 0 {
+			ch.probes.Watch(record.Uid, record.Probes, ch.abortByProbe)
+		}
+	}
+	logrus.Infof("[registry] registry file %s loaded, experiments: %d", file, len(records))
+}
+
+// persistLocked saves the running experiments to the registry file, ch.mutex must be held
+func (ch *ChaosbladeHandler) persistLocked() {
+	records := make([]registryRecord, 0, len(ch.running))
+	for uid, args := range ch.running {
+		record := registryRecord{Uid: uid, Cmdline: experiment.CommandLine(args), Args: args}
+		if deadline, ok := ch.deadlines[uid]; ok {
+			record.Deadline = &deadline
+		}
+		if probes, ok := ch.probes.Probes(uid); ok {
+			record.Probes = probes
+		}
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Uid < records[j].Uid
+	})
+	if err := writeRegistry(registryFile(), records); err != nil {
+		logrus.Warningf("[registry] save registry file failed, err: %v", err)
+	}
+}
+
+func writeRegistry(file string, records []registryRecord) error {
+	bytes, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return tools.WriteFileAtomic(file, bytes, 0o600)
+}
+
+func blastRadius() *radius.Limits {
+	cfg := options.Opts.BlastRadiusConfig
+	return &radius.Limits{
+		Host:      cfg.Host,
+		Targets:   cfg.Targets,
+		Namespace: cfg.Namespace,
+		Workload:  cfg.Workload,
+	}
+}
+
+// reserve counts the create command against the blast radius limits with the running and the
+// in-flight experiments, release must be called once the command is finished and recorded
+func (ch *ChaosbladeHandler) reserve(args []string) (release func(), reason string) {
+	limits := blastRadius()
+	if !limits.Enabled() {
+		return func() {}, ""
+	}
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	scopes := make([]radius.Scope, 0, len(ch.running)+len(ch.reserved))
+	for _, args := range ch.running {
+		if len(args) > 0 && options.CreateOperation[args[0]] {
+			scopes = append(scopes, radius.ScopeOf(args))
+		}
+	}
+	for _, scope := range ch.reserved {
+		scopes = append(scopes, scope)
+	}
+	if reason = limits.Check(scopes, radius.ScopeOf(args)); reason != "" {
+		return nil, reason
+	}
+	id := tools.GetUUID()
+	ch.reserved[id] = radius.ScopeOf(args)
+	return func() {
+		ch.mutex.Lock()
+		defer ch.mutex.Unlock()
+		delete(ch.reserved, id)
+	}, ""
+}
+
+// rejectByRadius returns the failure of the command over the blast radius limits
+func rejectByRadius(cmdline, reason string) *transport.Response {
+	logrus.Warningf("[chaosblade] command rejected by blast radius limits, reason: %s, cmd: %s", reason, cmdline)
+	response := transport.ReturnFail(transport.BlastRadiusExceeded, reason)
+	audit.Record(&audit.Entry{
+		Handler: radiusAuditHandler,
+		Command: fmt.Sprintf("%s %s", options.BladeBinPath, cmdline),
+		Code:    response.Code,
+		Error:   response.Error,
+	})
+	return response
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+	"github.com/chaosblade-io/chaos-agent/pkg/probe"
+	"github.com/chaosblade-io/chaos-agent/pkg/radius"
+)
+
+func TestReserve(t *testing.T) {
+	saved := options.Opts
+	defer func() { options.Opts = saved }()
+	options.Opts = &options.Options{BlastRadiusConfig: options.BlastRadiusConfig{Host: 1}}
+
+	tests := []struct {
+		name    string
+		running string
+		reject  bool
+	}{
+		{name: "create", running: "create cpu fullload", reject: true},
+		{name: "create alias", running: "c cpu fullload", reject: true},
+		{name: "prepare alias", running: "p jvm --pid 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := &ChaosbladeHandler{
+				running:  map[string][]string{"a": strings.Fields(tt.running)},
+				reserved: make(map[string]radius.Scope),
+			}
+			release, reason := ch.reserve(strings.Fields("c mem load"))
+			if (reason != "") != tt.reject {
+				t.Fatalf("reserve() reason = %q, want rejected %t", reason, tt.reject)
+			}
+			if release != nil {
+				release()
+			}
+		})
+	}
+}
+
+func TestRegistry_Restore(t *testing.T) {
+	saved := options.Opts
+	defer func() { options.Opts = saved }()
+	options.Opts = &options.Options{RegistryFile: filepath.Join(t.TempDir(), "registry.json")}
+
+	args := []string{"create", "process", "kill", "--process", "java -jar app.jar"}
+	probes := []probe.Probe{{Name: "web", Type: "tcp", Address: "127.0.0.1:80", Interval: policy.Duration{Duration: time.Hour}, FailureThreshold: 1}}
+	ch := &ChaosbladeHandler{running: map[string][]string{"a": args}, deadlines: make(map[string]time.Time), probes: probe.NewMonitor()}
+	ch.probes.Watch("a", probes, ch.abortByProbe)
+	defer ch.probes.Stop("a")
+	ch.persistLocked()
+
+	loaded := &ChaosbladeHandler{running: make(map[string][]string), deadlines: make(map[string]time.Time), probes: probe.NewMonitor()}
+	loaded.loadRegistry()
+	defer loaded.probes.Stop("a")
+	if got := loaded.running["a"]; !reflect.DeepEqual(got, args) {
+		t.Errorf("loadRegistry() args = %q, want %q", got, args)
+	}
+	if got, ok := loaded.probes.Probes("a"); !ok || !reflect.DeepEqual(got, probes) {
+		t.Errorf("loadRegistry() probes = %+v, want the watch resumed with %+v", got, probes)
+	}
+}
+
+func TestDestroyedUid(t *testing.T) {
+	tests := map[string]string{
+		"destroy 7c1f7afc281482c8":                             "7c1f7afc281482c8",
+		"d 7c1f7afc281482c8":                                   "7c1f7afc281482c8",
+		"destroy k8s pod-network delay --uid 7c1f7afc281482c8": "7c1f7afc281482c8",
+		"destroy k8s pod-network delay --uid=7c1f7afc281482c8": "7c1f7afc281482c8",
+	}
+	for cmd, want := range tests {
+		if got := destroyedUid(strings.Fields(cmd)); got != want {
+			t.Errorf("destroyedUid(%q) = %q, want %q", cmd, got, want)
+		}
+	}
+}
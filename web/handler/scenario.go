@@ -0,0 +1,212 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+	"github.com/chaosblade-io/chaos-agent/pkg/progress"
+	"github.com/chaosblade-io/chaos-agent/pkg/scenario"
+	"github.com/chaosblade-io/chaos-agent/pkg/upgrade"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+const (
+	RunScenarioAction    = "run"
+	CancelScenarioAction = "cancel"
+	GetScenarioAction    = "get"
+
+	// ScenarioEvent is the event type of the finished scenarios
+	ScenarioEvent = "scenario"
+
+	scenarioAuditHandler = "scenario/step"
+)
+
+// ScenarioHandler runs the multi-step scenarios in background, the applied steps are rolled back in
+// reverse order if a step fails or the scenario is cancelled
+type ScenarioHandler struct {
+	chaosblade      *ChaosbladeHandler
+	transportClient *transport.TransportClient
+	manager         *scenario.Manager
+}
+
+func NewScenarioHandler(chaosblade *ChaosbladeHandler, transportClient *transport.TransportClient) *ScenarioHandler {
+	return &ScenarioHandler{
+		chaosblade:      chaosblade,
+		transportClient: transportClient,
+		manager:         scenario.NewManager(options.Opts.JobConfig.Retention),
+	}
+}
+
+// Command describes the scenario operation
+func (sh *ScenarioHandler) Command(request *transport.Request) string {
+	action := request.Params["action"]
+	if action == "" || action == RunScenarioAction {
+		return fmt.Sprintf("scenario run %s", request.Params["scenario"])
+	}
+	return fmt.Sprintf("scenario %s %s", action, request.Params["id"])
+}
+
+// Handle runs the scenario param, or cancels, gets the execution of the id param, or lists all executions
+func (sh *ScenarioHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Infof("Receive server scenario request, params: %v", request.Params)
+
+	id := request.Params["id"]
+	switch request.Params["action"] {
+	case "", RunScenarioAction:
+		return sh.run(request)
+	case CancelScenarioAction:
+		if !sh.manager.Cancel(id) {
+			return transport.ReturnFail(transport.ScenarioNotFound, id)
+		}
+		return transport.ReturnSuccess()
+	case GetScenarioAction:
+		if id == "" {
+			return transport.ReturnSuccessWithResult(sh.manager.List())
+		}
+		execution, ok := sh.manager.Get(id)
+		if !ok {
+			return transport.ReturnFail(transport.ScenarioNotFound, id)
+		}
+		return transport.ReturnSuccessWithResult(execution)
+	default:
+		return transport.ReturnFail(transport.ParameterTypeError, "action")
+	}
+}
+
+// run checks all of the experiments before the first step, the scenario is in flight until it's
+// finished, so the upgrade waits for it
+func (sh *ScenarioHandler) run(request *transport.Request) *transport.Response {
+	content := request.Params["scenario"]
+	if content == "" {
+		return transport.ReturnFail(transport.ParameterEmpty, "scenario")
+	}
+	s, err := scenario.Parse(content)
+	if err != nil {
+		return transport.ReturnFail(transport.ParameterTypeError, fmt.Sprintf("scenario, %s", err.Error()))
+	}
+	for _, step := range s.Steps {
+		if step.IsWait() {
+			continue
+		}
+		cmd, response := buildCommand(experimentRequest(step.Experiment))
+		if response != nil {
+			response.Error = fmt.Sprintf("step %s, %s", step.Name, response.Error)
+			return response
+		}
+		if reason := policy.Check(cmd.Args); reason != "" {
+			return transport.ReturnFail(transport.PolicyDenied, fmt.Sprintf("step %s, %s", step.Name, reason))
+		}
+	}
+
+	release, ok := upgrade.Enter()
+	if !ok {
+		return transport.ReturnFail(transport.Upgrading, "retry later")
+	}
+	rid := request.Headers[transport.Rid]
+	executor := &scenarioExecutor{chaosblade: sh.chaosblade, scenario: s.Name, progress: progress.NewReporter(rid)}
+	execution := sh.manager.Start(s, rid, executor, func(finished *scenario.Execution) {
+		release()
+		sh.report(finished)
+	})
+	logrus.Infof("[scenario] scenario %s started, id: %s, steps: %d", s.Name, execution.Id, len(s.Steps))
+	return transport.ReturnSuccessWithResult(execution)
+}
+
+func (sh *ScenarioHandler) report(execution *scenario.Execution) {
+	uri, ok := transport.TransportUriMap[transport.API_EVENT]
+	if !ok {
+		logrus.Warnf("[scenario] report uri is null!")
+		return
+	}
+	steps, _ := json.Marshal(execution.Steps)
+	request := transport.NewRequest()
+	request.AddParam("type", ScenarioEvent).
+		AddParam("id", execution.Id).
+		AddParam("name", execution.Name).
+		AddParam("status", execution.Status).
+		AddParam("steps", string(steps))
+	if execution.Error != "" {
+		request.AddParam("error", execution.Error)
+	}
+	response, err := sh.transportClient.Invoke(uri, request, true)
+	if err != nil {
+		logrus.Warningf("[scenario] report scenario %s err, %v", execution.Id, err)
+		return
+	}
+	if !response.Success {
+		logrus.Warningf("[scenario] report scenario %s failed, %s", execution.Id, response.Error)
+	}
+}
+
+// scenarioExecutor runs the steps by the chaosblade handler without entering the safe point again,
+// as the whole scenario is in flight
+type scenarioExecutor struct {
+	chaosblade *ChaosbladeHandler
+	scenario   string
+	progress   *progress.Reporter
+}
+
+func (se *scenarioExecutor) Apply(step scenario.Step) (string, error) {
+	cmd, response := buildCommand(experimentRequest(step.Experiment))
+	if response != nil {
+		return "", errors.New(response.Error)
+	}
+	cmd.Progress = se.progress
+	se.progress.Phase("step %s: blade %s", step.Name, cmd.Line)
+	response = se.exec(step, cmd)
+	if !response.Success {
+		return "", errors.New(response.Error)
+	}
+	uid, _ := response.Result.(string)
+	return uid, nil
+}
+
+// Undo destroys the created experiment or revokes the preparation
+func (se *scenarioExecutor) Undo(step scenario.Step, uid string) error {
+	operation := experiment.DestroyOperation
+	if step.Experiment.Operation == experiment.PrepareOperation {
+		operation = experiment.RevokeOperation
+	}
+	cmd := newArgsCommand([]string{operation, uid})
+	se.progress.Phase("rollback step %s: blade %s", step.Name, cmd.Line)
+	if response := se.exec(step, cmd); !response.Success {
+		return errors.New(response.Error)
+	}
+	return nil
+}
+
+func (se *scenarioExecutor) exec(step scenario.Step, cmd *bladeCommand) *transport.Response {
+	response := se.chaosblade.execWithOutput(cmd, nil)
+	audit.Record(&audit.Entry{
+		Handler: scenarioAuditHandler,
+		Params:  map[string]string{"scenario": se.scenario, "step": step.Name},
+		Command: fmt.Sprintf("%s %s", options.BladeBinPath, cmd.Line),
+		Code:    response.Code,
+		Success: response.Success,
+		Error:   response.Error,
+	})
+	return response
+}
@@ -0,0 +1,307 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/policy"
+	"github.com/chaosblade-io/chaos-agent/pkg/schedule"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/pkg/upgrade"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+const (
+	AddScheduleAction      = "add"
+	RemoveScheduleAction   = "remove"
+	ListScheduleAction     = "list"
+	EnableScheduleAction   = "enable"
+	DisableScheduleAction  = "disable"
+	BlackoutScheduleAction = "blackout"
+
+	// ScheduleRunEvent is the event type of the scheduled runs
+	ScheduleRunEvent = "scheduleRun"
+
+	scheduleAuditHandler = "schedule/run"
+)
+
+// ScheduleHandler manages the local experiment schedules, which are run by the agent itself
+// even if the server is not reachable
+type ScheduleHandler struct {
+	chaosblade      *ChaosbladeHandler
+	transportClient *transport.TransportClient
+	store           *schedule.Store
+	scheduler       *schedule.Scheduler
+}
+
+func NewScheduleHandler(chaosblade *ChaosbladeHandler, transportClient *transport.TransportClient) *ScheduleHandler {
+	file := options.Opts.ScheduleFile
+	if file == "" {
+		file = tools.GetScheduleFilePath()
+	}
+	sh := &ScheduleHandler{
+		chaosblade:      chaosblade,
+		transportClient: transportClient,
+		store:           schedule.NewStore(file),
+	}
+	sh.scheduler = schedule.NewScheduler(sh.store, sh, sh.report)
+	return sh
+}
+
+func (sh *ScheduleHandler) Start() error {
+	return sh.scheduler.Start()
+}
+
+func (sh *ScheduleHandler) Shutdown() {
+	sh.scheduler.Shutdown()
+}
+
+// Command describes the schedule operation
+func (sh *ScheduleHandler) Command(request *transport.Request) string {
+	action := request.Params["action"]
+	switch action {
+	case AddScheduleAction:
+		return fmt.Sprintf("schedule add %s %q for %s: %s %s %s %s", request.Params["name"], request.Params["cron"],
+			request.Params["duration"], request.Params["scope"], request.Params["target"], request.Params["experiment"], request.Params["flags"])
+	case BlackoutScheduleAction:
+		return fmt.Sprintf("schedule blackout %s", request.Params["windows"])
+	}
+	return fmt.Sprintf("schedule %s %s", action, request.Params["name"])
+}
+
+// Handle adds, removes, enables, disables or lists the schedules, or replaces the blackout windows.
+// The experiment of add is the same params as chaosblade: scope, target, experiment (the action) and flags.
+func (sh *ScheduleHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Infof("Receive server schedule request, params: %v", request.Params)
+
+	name := request.Params["name"]
+	switch action := request.Params["action"]; action {
+	case "", ListScheduleAction:
+		return transport.ReturnSuccessWithResult(sh.list())
+	case AddScheduleAction:
+		return sh.add(request)
+	case RemoveScheduleAction:
+		removed, err := sh.store.Remove(name)
+		if err != nil {
+			return transport.ReturnFail(transport.ServerError, err.Error())
+		}
+		if !removed {
+			return transport.ReturnFail(transport.ScheduleNotFound, name)
+		}
+		return transport.ReturnSuccess()
+	case EnableScheduleAction, DisableScheduleAction:
+		found, ok := sh.store.Get(name)
+		if !ok {
+			return transport.ReturnFail(transport.ScheduleNotFound, name)
+		}
+		found.Disabled = action == DisableScheduleAction
+		found.UpdateTime = time.Now().UnixMilli()
+		if err := sh.store.Put(found); err != nil {
+			return transport.ReturnFail(transport.ServerError, err.Error())
+		}
+		return transport.ReturnSuccess()
+	case BlackoutScheduleAction:
+		var windows []schedule.Window
+		if err := json.Unmarshal([]byte(request.Params["windows"]), &windows); err != nil {
+			return transport.ReturnFail(transport.ParameterTypeError, "windows")
+		}
+		for _, window := range windows {
+			if err := window.Validate(); err != nil {
+				return transport.ReturnFail(transport.ParameterTypeError, fmt.Sprintf("windows, %s", err.Error()))
+			}
+		}
+		if err := sh.store.SetBlackouts(windows); err != nil {
+			return transport.ReturnFail(transport.ServerError, err.Error())
+		}
+		return transport.ReturnSuccess()
+	default:
+		return transport.ReturnFail(transport.ParameterTypeError, "action")
+	}
+}
+
+// scheduleView is the schedule with its next run
+type scheduleView struct {
+	schedule.Schedule
+	// unix millis, absent if disabled
+	Next int64 `json:"next,omitempty"`
+}
+
+// scheduleList is the result of list
+type scheduleList struct {
+	Schedules []scheduleView    `json:"schedules"`
+	Blackouts []schedule.Window `json:"blackouts"`
+}
+
+func (sh *ScheduleHandler) list() *scheduleList {
+	result := &scheduleList{Schedules: make([]scheduleView, 0), Blackouts: sh.store.Blackouts()}
+	now := time.Now()
+	for _, s := range sh.store.List() {
+		view := scheduleView{Schedule: s}
+		if cron, err := schedule.ParseCron(s.Cron); err == nil && !s.Disabled {
+			if next := cron.Next(now); !next.IsZero() {
+				view.Next = next.UnixMilli()
+			}
+		}
+		result.Schedules = append(result.Schedules, view)
+	}
+	return result
+}
+
+// add validates the experiment with the spec and the safety policy before it's saved, the policy is
+// checked again at each run as it may be changed
+func (sh *ScheduleHandler) add(request *transport.Request) *transport.Response {
+	s := schedule.Schedule{
+		Name:       request.Params["name"],
+		Cron:       request.Params["cron"],
+		Disabled:   request.Params["disabled"] == "true",
+		UpdateTime: time.Now().UnixMilli(),
+	}
+	var err error
+	if s.Duration.Duration, err = time.ParseDuration(request.Params["duration"]); err != nil {
+		return transport.ReturnFail(transport.ParameterTypeError, "duration")
+	}
+	if value := request.Params["jitter"]; value != "" {
+		if s.Jitter.Duration, err = time.ParseDuration(value); err != nil {
+			return transport.ReturnFail(transport.ParameterTypeError, "jitter")
+		}
+	}
+	params := map[string]string{
+		"operation": experiment.CreateOperation,
+		"scope":     request.Params["scope"],
+		"target":    request.Params["target"],
+		"action":    request.Params["experiment"],
+		"flags":     request.Params["flags"],
+	}
+	exp, err := experiment.Parse(params)
+	if err != nil {
+		return transport.ReturnFail(transport.ExperimentInvalid, err.Error())
+	}
+	s.Experiment = *exp
+	if err := s.Validate(); err != nil {
+		return transport.ReturnFail(transport.ParameterTypeError, err.Error())
+	}
+	cmd, response := buildCommand(scheduleRequest(s))
+	if response != nil {
+		return response
+	}
+	if reason := policy.Check(cmd.Args); reason != "" {
+		return transport.ReturnFail(transport.PolicyDenied, reason)
+	}
+	if err := sh.store.Put(s); err != nil {
+		return transport.ReturnFail(transport.ServerError, err.Error())
+	}
+	logrus.Infof("[schedule] schedule %s saved, cron: %s, duration: %s, cmd: %s", s.Name, s.Cron, s.Duration.Duration, cmd.Line)
+	return transport.ReturnSuccess()
+}
+
+// scheduleRequest is the chaosblade request of the scheduled experiment, the timeout flag is the duration
+// if absent, so blade destroys the experiment itself if the agent stops during the run
+func scheduleRequest(s schedule.Schedule) *transport.Request {
+	exp := s.Experiment
+	exp.Flags = make(map[string]string, len(s.Experiment.Flags)+1)
+	for name, value := range s.Experiment.Flags {
+		exp.Flags[name] = value
+	}
+	if _, ok := exp.Flags[policy.TimeoutFlag]; !ok {
+		exp.Flags[policy.TimeoutFlag] = strconv.FormatInt(int64(math.Ceil(s.Duration.Seconds())), 10)
+	}
+	return experimentRequest(&exp)
+}
+
+// Create runs the experiment through the chaosblade handler, so it's checked by the safety policy,
+// recorded and destroyed by the watchdog like the experiments created by server
+func (sh *ScheduleHandler) Create(s schedule.Schedule) (string, error) {
+	request := scheduleRequest(s)
+	response := sh.chaosblade.Handle(request)
+	sh.audit(s, sh.chaosblade.Command(request), response)
+	if !response.Success {
+		return "", errors.New(response.Error)
+	}
+	uid, _ := response.Result.(string)
+	return uid, nil
+}
+
+// Destroy waits for the upgrade in progress instead of leaving the experiment running
+func (sh *ScheduleHandler) Destroy(s schedule.Schedule, uid string) error {
+	request := transport.NewRequest()
+	request.AddParam("operation", experiment.DestroyOperation).AddParam("uid", uid)
+	var response *transport.Response
+	if release, ok := upgrade.EnterWait(options.Opts.UpgradeConfig.SafePointTimeout); ok {
+		response = sh.chaosblade.handle(request, release)
+	} else {
+		response = transport.ReturnFail(transport.Upgrading, "retry later")
+	}
+	sh.audit(s, sh.chaosblade.Command(request), response)
+	if !response.Success {
+		return errors.New(response.Error)
+	}
+	return nil
+}
+
+func (sh *ScheduleHandler) audit(s schedule.Schedule, command string, response *transport.Response) {
+	audit.Record(&audit.Entry{
+		Handler: scheduleAuditHandler,
+		Params:  map[string]string{"name": s.Name, "cron": s.Cron},
+		Command: command,
+		Code:    response.Code,
+		Success: response.Success,
+		Error:   response.Error,
+	})
+}
+
+// report sends the run as an event, and the experiment status as the async experiments
+func (sh *ScheduleHandler) report(run schedule.Run) {
+	logrus.Infof("[schedule] run of schedule %s, uid: %s, status: %s, err: %s", run.Schedule, run.Uid, run.Status, run.Error)
+	if run.Uid != "" {
+		if uri, ok := transport.TransportUriMap[transport.API_CHAOSBLADE_ASYNC]; ok {
+			sh.chaosblade.reportStatusFunc(run.Uid, run.Status, run.Error, uri)
+		}
+	}
+	uri, ok := transport.TransportUriMap[transport.API_EVENT]
+	if !ok {
+		logrus.Warnf("[schedule] report uri is null!")
+		return
+	}
+	request := transport.NewRequest()
+	request.AddParam("type", ScheduleRunEvent).
+		AddParam("schedule", run.Schedule).
+		AddParam("fire", strconv.FormatInt(run.Fire, 10)).
+		AddParam("uid", run.Uid).
+		AddParam("status", run.Status)
+	if run.Error != "" {
+		request.AddParam("error", run.Error)
+	}
+	response, err := sh.transportClient.Invoke(uri, request, true)
+	if err != nil {
+		logrus.Warningf("[schedule] report run of %s err, %v", run.Schedule, err)
+		return
+	}
+	if !response.Success {
+		logrus.Warningf("[schedule] report run of %s failed, %s", run.Schedule, response.Error)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/kubernetes"
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
+)
+
+// recordsMaxAge is how long the blade records are shared by the waiters
+const recordsMaxAge = time.Second
+
+var (
+	// createSource and prepareSource read the status of host experiments and preparations, one
+	// blade process serves all waiters
+	createSource  = status.NewBladeSource(experiment.CreateOperation, queryStatusRecords, recordsMaxAge)
+	prepareSource = status.NewBladeSource(experiment.PrepareOperation, queryStatusRecords, recordsMaxAge)
+)
+
+// queryStatusRecords runs blade status --type kind, returns uid to record
+func queryStatusRecords(ctx context.Context, kind string) (map[string]status.Record, error) {
+	result, errMsg, ok := execBlade(ctx, "status", "--type", kind)
+	if !ok {
+		return nil, fmt.Errorf("invoke blade error, %s", errMsg)
+	}
+	// the result may be prefixed by the logs of blade, which are skipped by parseResult
+	response := parseResult(result)
+	if !response.Success {
+		return nil, fmt.Errorf("blade status failed, %s", response.Error)
+	}
+	bytes, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+	var list []status.Record
+	if err := json.Unmarshal(bytes, &list); err != nil {
+		return nil, fmt.Errorf("unmarshal blade status failed, %s", err.Error())
+	}
+	records := make(map[string]status.Record, len(list))
+	for _, record := range list {
+		records[record.Uid] = record
+	}
+	return records, nil
+}
+
+// k8sSource watches the chaosblade custom resources if the agent runs in the cluster,
+// otherwise blade query k8s is polled. blade query k8s is also polled if the custom resources
+// cannot be read, eg: the agent is not granted by rbac.
+func k8sSource() status.Source {
+	if client := kubernetes.GetInstance().DynamicClient; client != nil {
+		return status.WithFallback(status.NewCRSource(client), bladeK8sSource{})
+	}
+	return bladeK8sSource{}
+}
+
+// waitForK8sStatus 等待 K8s 实验状态，确保 chaosblade-operator 处理完成
+func (ch *ChaosbladeHandler) waitForK8sStatus(uid string, bladeCmd *bladeCommand) {
+	logger, reporter := bladeCmd.logger(), bladeCmd.Progress
+	logger.Infof("[chaosblade] waiting for K8s experiment status, uid: %s", uid)
+	reporter.Phase("waiting for operator, uid: %s", uid)
+	result := status.Wait(context.TODO(), "k8s", uid, k8sSource(), status.Pending)
+	if result.Error != "" && (result.Status == status.Pending || result.Status == status.Unknown) {
+		logger.Warningf("[chaosblade] timeout waiting for K8s experiment status, uid: %s", uid)
+		reporter.Phase("timeout waiting for operator, uid: %s", uid)
+		return
+	}
+	logger.Infof("K8s experiment status found, uid: %s, status: %s, err: %s", uid, result.Status, result.Error)
+	reporter.Phase("status %s, uid: %s", result.Status, uid)
+}
+
+// bladeK8sSource reads the status of k8s experiments by blade query k8s create
+type bladeK8sSource struct{}
+
+func (bladeK8sSource) Status(ctx context.Context, uid string) (status.Result, error) {
+	result, errMsg, ok := execBlade(ctx, "query", "k8s", "create", uid)
+	if !ok {
+		return status.Result{}, errors.New(errMsg)
+	}
+	response := parseResult(result)
+	if !response.Success {
+		return status.Result{}, fmt.Errorf("query k8s status failed, %s", response.Error)
+	}
+	statuses := k8sStatusesOf(response.Result)
+	if len(statuses) == 0 {
+		// accepted by the operator, the resource statuses may be empty in async scenarios
+		return status.Result{Status: status.Running}, nil
+	}
+	return k8sResult(statuses), nil
+}
+
+// k8sStatusesOf returns the resource statuses of the query result, which is a K8sResultBean
+// {"uid":"xxx","success":true,"error":"","statuses":[...]}, its json string, or the statuses
+func k8sStatusesOf(result interface{}) []interface{} {
+	switch value := result.(type) {
+	case map[string]interface{}:
+		statuses, _ := value["statuses"].([]interface{})
+		return statuses
+	case string:
+		var resultMap map[string]interface{}
+		if err := json.Unmarshal([]byte(value), &resultMap); err == nil {
+			statuses, _ := resultMap["statuses"].([]interface{})
+			return statuses
+		}
+	case []interface{}:
+		return value
+	}
+	return nil
+}
+
+// k8sResult is Error if any resource failed, otherwise Running
+func k8sResult(statuses []interface{}) status.Result {
+	result := status.Result{Status: status.Running}
+	errs := make([]string, 0)
+	for _, item := range statuses {
+		resource, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if state, _ := resource["state"].(string); strings.EqualFold(state, status.Error) {
+			result.Status = status.Error
+		}
+		if msg, _ := resource["error"].(string); msg != "" {
+			errs = append(errs, msg)
+		}
+	}
+	result.Error = strings.Join(errs, "; ")
+	return result
+}
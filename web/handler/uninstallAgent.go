@@ -21,7 +21,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
-	"github.com/chaosblade-io/chaos-agent/pkg/bash"
+	"github.com/chaosblade-io/chaos-agent/pkg/executor"
 	"github.com/chaosblade-io/chaos-agent/pkg/options"
 	"github.com/chaosblade-io/chaos-agent/pkg/tools"
 	"github.com/chaosblade-io/chaos-agent/transport"
@@ -37,6 +37,11 @@ func NewUninstallInstallHandler(transportClient *transport.TransportClient) *Uni
 	}
 }
 
+// Command returns the ctl command line of uninstalling agent
+func (ph *UninstallInstallHandler) Command(request *transport.Request) string {
+	return options.CtlPathFunc() + " uninstall"
+}
+
 func (ph *UninstallInstallHandler) Handle(request *transport.Request) *transport.Response {
 	logrus.Info("Receive server uninstall agent request")
 
@@ -48,10 +53,10 @@ func (ph *UninstallInstallHandler) Handle(request *transport.Request) *transport
 	}
 
 	// 2. exec uninstall command
-	_, errMsg, ok := bash.ExecScript(context.Background(), ctlPath, "uninstall")
-	if !ok || errMsg != "" {
-		logrus.Warningf(transport.Errors[transport.CtlExecFailed], errMsg)
-		return transport.ReturnFail(transport.CtlExecFailed, errMsg)
+	result := executor.Run(context.Background(), ctlPath, []string{"uninstall"}, executor.Options{})
+	if !result.Success() {
+		logrus.Warningf(transport.Errors[transport.CtlExecFailed], result.Error())
+		return transport.ReturnFail(transport.CtlExecFailed, result.Error())
 	}
 
 	return transport.ReturnSuccess()
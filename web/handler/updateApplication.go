@@ -17,6 +17,8 @@
 package handler
 
 import (
+	"fmt"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/chaosblade-io/chaos-agent/pkg/options"
@@ -30,6 +32,12 @@ func NewUpdateApplicationHandler() *UpdateApplicationHandler {
 	return &UpdateApplicationHandler{}
 }
 
+// Command describes the application update
+func (sh *UpdateApplicationHandler) Command(request *transport.Request) string {
+	return fmt.Sprintf("update application, instance: %s, group: %s",
+		request.Params[tools.AppInstanceKeyName], request.Params[tools.AppGroupKeyName])
+}
+
 func (sh *UpdateApplicationHandler) Handle(request *transport.Request) *transport.Response {
 	logrus.Info("Receive server update applocation request")
 
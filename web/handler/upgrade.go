@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/conn/callback"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/upgrade"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+type UpgradeHandler struct {
+	upgrader *upgrade.Upgrader
+	callback *callback.CallbackHandler
+}
+
+func NewUpgradeHandler(transportClient *transport.TransportClient) *UpgradeHandler {
+	return &UpgradeHandler{
+		upgrader: upgrade.NewUpgrader(agentBinary(), options.Opts.UpgradeConfig),
+		callback: callback.NewClientCloseHandler(transportClient),
+	}
+}
+
+// Command describes the upgrade of agent
+func (uh *UpgradeHandler) Command(request *transport.Request) string {
+	return fmt.Sprintf("upgrade agent to %s from %s", request.Params["version"], upgradePackageOf(request).Url)
+}
+
+// Handle starts the upgrade and returns immediately, the steps are reported by the upgrade callback
+func (uh *UpgradeHandler) Handle(request *transport.Request) *transport.Response {
+	logrus.Infof("Receive server upgrade agent request, params: %v", request.Params)
+
+	pkg := upgradePackageOf(request)
+	if pkg.Version == "" {
+		return transport.ReturnFail(transport.ParameterLess, "version")
+	}
+	err := uh.upgrader.Start(pkg, func(step int, message string) {
+		uh.callback.Callback(step, options.Opts.Version, pkg.Version, options.Opts.Version, message, options.ProgramName)
+	})
+	if err != nil {
+		logrus.Warningf("[upgrade] start upgrade to %s failed, err: %s", pkg.Version, err.Error())
+		return transport.ReturnFail(transport.UpgradeFailed, err.Error())
+	}
+	return transport.ReturnSuccess()
+}
+
+func upgradePackageOf(request *transport.Request) upgrade.Package {
+	pkg := upgrade.Package{
+		Url:       request.Params["url"],
+		Sha256:    request.Params["sha256"],
+		Version:   request.Params["version"],
+		Signature: request.Params["signature"],
+	}
+	if pkg.Url == "" {
+		pkg.Url = options.Opts.ChaosAgentBinUrl
+	}
+	return pkg
+}
+
+// ResumeUpgrade confirms the upgrade once the new agent is registered, or reports the rollback
+func ResumeUpgrade(transportClient *transport.TransportClient) {
+	binary := agentBinary()
+	if binary == "" {
+		return
+	}
+	upgradeCallback := callback.NewClientCloseHandler(transportClient)
+	upgrade.Resume(binary, func() bool {
+		return options.Opts.Cid != ""
+	}, func(state *upgrade.State) {
+		step := upgrade.StepSucceeded
+		if state.Status == upgrade.StatusRolledBack {
+			step = upgrade.StepRolledBack
+		}
+		upgradeCallback.Callback(step, state.OldVersion, state.NewVersion, options.Opts.Version, state.Message, options.ProgramName)
+	})
+}
+
+func agentBinary() string {
+	binary, err := os.Executable()
+	if err != nil {
+		logrus.Warningf("[upgrade] get agent binary path failed, err: %v", err)
+		return ""
+	}
+	return binary
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2025 The ChaosBlade Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/chaosblade-io/chaos-agent/pkg/audit"
+	"github.com/chaosblade-io/chaos-agent/pkg/experiment"
+	"github.com/chaosblade-io/chaos-agent/pkg/options"
+	"github.com/chaosblade-io/chaos-agent/pkg/status"
+	"github.com/chaosblade-io/chaos-agent/pkg/tools"
+	"github.com/chaosblade-io/chaos-agent/transport"
+)
+
+const (
+	// ExpiredStatus is reported when the experiment is destroyed by the watchdog
+	ExpiredStatus = "Expired"
+
+	watchdogAuditHandler = "chaosblade/watchdog"
+
+	// watchdogRetryDelay is the delay of destroying again after the first failed destroy, it's doubled
+	// by each failure up to watchdogMaxRetryDelay
+	watchdogRetryDelay    = time.Minute
+	watchdogMaxRetryDelay = 30 * time.Minute
+	// watchdogReportAttempts is the number of failed destroys after which the error is reported, the
+	// watchdog keeps retrying until the experiment is destroyed or forgotten by the reconciler
+	watchdogReportAttempts = 5
+)
+
+// Watchdog destroys the experiments which run over their ttl
+type Watchdog struct {
+	chaosblade *ChaosbladeHandler
+	interval   time.Duration
+	stopCh     chan struct{}
+	// failures is the number of failed destroys of the experiments, it's only accessed by the check loop
+	failures map[string]int
+}
+
+func NewWatchdog(chaosblade *ChaosbladeHandler, interval time.Duration) *Watchdog {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return &Watchdog{
+		chaosblade: chaosblade,
+		interval:   interval,
+		stopCh:     make(chan struct{}),
+		failures:   make(map[string]int),
+	}
+}
+
+func (w *Watchdog) Start() error {
+	ticker := time.NewTicker(w.interval)
+	go func() {
+		defer tools.PanicPrintStack()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stopCh:
+				return
+			case now := <-ticker.C:
+				w.check(now)
+			}
+		}
+	}()
+	logrus.Infof("[watchdog] start successfully, interval: %s", w.interval)
+	return nil
+}
+
+// Shutdown stops the watchdog, the running experiments are left as they are
+func (w *Watchdog) Shutdown() {
+	close(w.stopCh)
+}
+
+func (w *Watchdog) check(now time.Time) {
+	for _, uid := range w.chaosblade.expired(now) {
+		w.destroy(uid)
+	}
+	// the experiments forgotten by the reconciler are not retried anymore
+	for uid := range w.failures {
+		if !w.chaosblade.hasDeadline(uid) {
+			delete(w.failures, uid)
+		}
+	}
+}
+
+func (w *Watchdog) destroy(uid string) {
+	cmd := newArgsCommand([]string{experiment.DestroyOperation, uid})
+	logrus.Warningf("[watchdog] experiment %s expired, destroy it", uid)
+	// the destroy is retried later if the agent is upgrading
+	response := w.chaosblade.execInFlight(cmd, 0)
+	audit.Record(&audit.Entry{
+		Handler: watchdogAuditHandler,
+		Command: fmt.Sprintf("%s %s", options.BladeBinPath, cmd.Line),
+		Code:    response.Code,
+		Success: response.Success,
+		Error:   response.Error,
+	})
+	uri, ok := transport.TransportUriMap[transport.API_CHAOSBLADE_ASYNC]
+	if !response.Success {
+		w.failures[uid]++
+		delay := retryDelay(w.failures[uid])
+		logrus.Warningf("[watchdog] destroy expired experiment %s failed %d times, err: %s, retry after %s",
+			uid, w.failures[uid], response.Error, delay)
+		w.chaosblade.postpone(uid, delay)
+		// the error is reported once, the status turns to expired when the retry succeeds
+		if w.failures[uid] == watchdogReportAttempts && ok {
+			errorMsg := fmt.Sprintf("destroy by agent after the ttl expired failed %d times, retrying, err: %s", w.failures[uid], response.Error)
+			w.chaosblade.reportStatusFunc(uid, status.Error, errorMsg, uri)
+		}
+		return
+	}
+	delete(w.failures, uid)
+	if !ok {
+		logrus.Warnf("[watchdog] report uri is null!")
+		return
+	}
+	w.chaosblade.reportStatusFunc(uid, ExpiredStatus, "destroyed by agent after the ttl expired", uri)
+}
+
+// retryDelay is the backoff of the failed destroys
+func retryDelay(failures int) time.Duration {
+	delay := watchdogRetryDelay
+	for i := 1; i < failures && delay < watchdogMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > watchdogMaxRetryDelay {
+		delay = watchdogMaxRetryDelay
+	}
+	return delay
+}
+
+// expired returns the uids of the experiments whose deadline is before now
+func (ch *ChaosbladeHandler) expired(now time.Time) []string {
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	uids := make([]string, 0)
+	for uid, deadline := range ch.deadlines {
+		if deadline.Before(now) {
+			uids = append(uids, uid)
+		}
+	}
+	return uids
+}
+
+// postpone delays the deadline of the experiment
+func (ch *ChaosbladeHandler) postpone(uid string, delay time.Duration) {
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	if _, ok := ch.deadlines[uid]; ok {
+		ch.deadlines[uid] = time.Now().Add(delay)
+		ch.persistLocked()
+	}
+}
+
+// hasDeadline returns whether the experiment is still watched by the watchdog
+func (ch *ChaosbladeHandler) hasDeadline(uid string) bool {
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	_, ok := ch.deadlines[uid]
+	return ok
+}
@@ -20,6 +20,8 @@ import (
 	"errors"
 	"sync"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/chaosblade-io/chaos-agent/web"
 )
 
@@ -51,3 +53,9 @@ func (s *GatewayServer) RegisterHandler(handlerName string, handler web.ServerHa
 	web.Handlers[handlerName] = handler
 	return nil
 }
+
+// RegisterStreamHandler is ignored, the requests through gateway cannot be streamed
+func (s *GatewayServer) RegisterStreamHandler(handlerName string, handler web.StreamHandler) error {
+	logrus.Infof("[gateway] stream handler %s is not supported by gateway server", handlerName)
+	return nil
+}
@@ -35,7 +35,7 @@ func NewHttpServer() web.APiServer {
 func (this HttpServer) RegisterHandler(handlerName string, handler web.ServerHandler) error {
 	http.HandleFunc("/"+handlerName, func(writer http.ResponseWriter, request *http.Request) {
 		requestStartTime := time.Now()
-		logrus.Infof("[%s] HTTP request received at %v, request: %+v", handlerName, requestStartTime, request)
+		logrus.Debugf("[%s] HTTP request received at %v, request: %+v", handlerName, requestStartTime, request)
 
 		parseFormStartTime := time.Now()
 		err := request.ParseForm()
@@ -47,7 +47,7 @@ func (this HttpServer) RegisterHandler(handlerName string, handler web.ServerHan
 		logrus.Infof("[%s] ParseForm completed, duration: %v", handlerName, parseFormDuration)
 
 		handleStartTime := time.Now()
-		result, err := handler.Handle(request.Form["body"][0])
+		result, err := handler.Handle(request.Form["body"][0], request.RemoteAddr)
 		handleDuration := time.Since(handleStartTime)
 		if err != nil {
 			errBytes := fmt.Sprintf("handle %s request err, %v", handlerName, err)
@@ -63,3 +63,18 @@ func (this HttpServer) RegisterHandler(handlerName string, handler web.ServerHan
 	})
 	return nil
 }
+
+// RegisterStreamHandler registers the handler which keeps the connection and writes progressively
+func (this HttpServer) RegisterStreamHandler(handlerName string, handler web.StreamHandler) error {
+	http.HandleFunc("/"+handlerName, func(writer http.ResponseWriter, request *http.Request) {
+		logrus.Infof("[%s] HTTP stream request received from %s", handlerName, request.RemoteAddr)
+		if err := request.ParseForm(); err != nil || len(request.Form["body"]) == 0 {
+			logrus.Warnf("[%s] http handler: %s, get request param wrong, err: %v", handlerName, handlerName, err)
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		handler.HandleStream(request.Context(), request.Form["body"][0], request.RemoteAddr, writer)
+		logrus.Infof("[%s] HTTP stream from %s closed", handlerName, request.RemoteAddr)
+	})
+	return nil
+}
@@ -17,6 +17,9 @@
 package web
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/chaosblade-io/chaos-agent/transport"
 )
 
@@ -24,12 +27,26 @@ type ApiHandler interface {
 	Handle(request *transport.Request) *transport.Response
 }
 
+// CommandDescriber is implemented by the handlers which execute external commands,
+// the described command is recorded in the audit trail
+type CommandDescriber interface {
+	Command(request *transport.Request) string
+}
+
 type APiServer interface {
 	RegisterHandler(handlerName string, handler ServerHandler) error
+	RegisterStreamHandler(handlerName string, handler StreamHandler) error
 }
 
 type ServerHandler interface {
-	Handle(request string) (string, error)
+	// remoteAddr is the address of the caller, ip or ip:port
+	Handle(request, remoteAddr string) (string, error)
+}
+
+// StreamHandler writes the response progressively, eg: server-sent events
+type StreamHandler interface {
+	// request is the encoded transport request, the handler returns when the stream ends
+	HandleStream(ctx context.Context, request, remoteAddr string, writer http.ResponseWriter)
 }
 
 var Handlers = make(map[string]ServerHandler)